@@ -17,6 +17,7 @@ import (
 
 	"github.com/containers/kubensmnt"
 	"github.com/containers/storage/pkg/reexec"
+	"github.com/cri-o/cri-o/internal/audit"
 	"github.com/cri-o/cri-o/internal/criocli"
 	"github.com/cri-o/cri-o/internal/log"
 	"github.com/cri-o/cri-o/internal/opentelemetry"
@@ -292,17 +293,36 @@ func main() {
 				logrus.Fatalf("Failed to initialize tracer provider: %v", err)
 			}
 		}
-		grpcServer := grpc.NewServer(
-			grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(
-				otel_collector.UnaryInterceptor(),
-			)),
+		unaryInterceptors := []grpc.UnaryServerInterceptor{
+			otel_collector.UnaryInterceptor(),
+		}
+		grpcOpts := []grpc.ServerOption{
 			grpc.StreamInterceptor(grpc_middleware.ChainStreamServer(
 				otel_collector.StreamInterceptor(),
 			)),
 			grpc.StatsHandler(otelgrpc.NewServerHandler(opts...)),
 			grpc.MaxSendMsgSize(config.GRPCMaxSendMsgSize),
 			grpc.MaxRecvMsgSize(config.GRPCMaxRecvMsgSize),
-		)
+		}
+
+		var auditLogger *audit.Logger
+		if config.AuditLogPath != "" {
+			auditLogger, err = audit.New(
+				config.AuditLogPath,
+				config.AuditLogMaxSize,
+				config.AuditLogMaxBackups,
+				config.AuditLogPolicy,
+			)
+			if err != nil {
+				logrus.Fatalf("Failed to create audit log: %v", err)
+			}
+			unaryInterceptors = append(unaryInterceptors, auditLogger.UnaryInterceptor())
+			grpcOpts = append(grpcOpts, grpc.Creds(audit.NewPeerCredentials()))
+		}
+
+		grpcServer := grpc.NewServer(append(grpcOpts,
+			grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(unaryInterceptors...)),
+		)...)
 
 		crioServer, err := server.New(ctx, config)
 		if err != nil {
@@ -436,6 +456,12 @@ func main() {
 		<-serverCloseCh
 		logrus.Debugf("Closed main server")
 
+		if auditLogger != nil {
+			if err := auditLogger.Close(); err != nil {
+				logrus.Warnf("Unable to close audit log: %v", err)
+			}
+		}
+
 		memProfilePath := c.String("profile-mem")
 		if memProfilePath != "" {
 			logrus.Infof("Creating memory profile in: %v", memProfilePath)
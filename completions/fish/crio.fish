@@ -2,7 +2,7 @@
 
 function __fish_crio_no_subcommand --description 'Test if there has been any subcommand yet'
     for i in (commandline -opc)
-        if contains -- $i complete completion help h man markdown md config version wipe status config c containers container cs s info i help h
+        if contains -- $i complete completion help h man markdown md config version wipe status config c containers container cs s info i pods pod pulls pull p prepull pre-pull reload help h
             return 1
         end
     end
@@ -14,6 +14,10 @@ complete -c crio -n '__fish_crio_no_subcommand' -f -l add-inheritable-capabiliti
 complete -c crio -n '__fish_crio_no_subcommand' -f -l additional-devices -r -d 'Devices to add to the containers.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l allowed-devices -r -d 'Devices a user is allowed to specify with the "io.kubernetes.cri-o.Devices" allowed annotation.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l apparmor-profile -r -d 'Name of the apparmor profile to be used as the runtime\'s default. This only takes effect if the user does not specify a profile via the Kubernetes Pod\'s metadata annotation.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l audit-log-max-backups -r -d 'Number of rotated audit logs to keep.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l audit-log-max-size -r -d 'Size in bytes after which the audit log gets rotated. If set to 0, then the audit log does not get rotated.'
+complete -c crio -n '__fish_crio_no_subcommand' -l audit-log-path -r -d 'Path to the audit log, which records the mutating and interactive CRI calls including the credentials of the caller. An empty path disables the audit log.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l audit-log-policy -r -d 'Audit level per CRI method in the format \'Method=level\'. Supported levels are \'none\', \'metadata\' and \'request\'.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l big-files-temporary-dir -r -d 'Path to the temporary directory to use for storing big files, used to store image blobs and data streams related to containers image management.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l bind-mount-prefix -r -d 'A prefix to use for the source of the bind mounts. This option would be useful if you were running CRI-O in a container. And had \'/\' mounted on \'/host\' in your container. Then if you ran CRI-O with the \'--bind-mount-prefix=/host\' option, CRI-O would add /host to any bind mounts it is handed over CRI. If Kubernetes asked to have \'/var/lib/foobar\' bind mounted into the container, then CRI-O would bind mount \'/host/var/lib/foobar\'. Since CRI-O itself is running in a container with \'/\' or the host mounted on \'/host\', the container would end up with \'/var/lib/foobar\' from the host mounted in the container rather then \'/var/lib/foobar\' from the CRI-O container.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l blockio-config-file -r -d 'Path to the blockio class configuration file for configuring the cgroup blockio controller.'
@@ -51,6 +55,7 @@ complete -c crio -n '__fish_crio_no_subcommand' -f -l default-transport -r -d 'A
 complete -c crio -n '__fish_crio_no_subcommand' -f -l default-ulimits -r -d 'Ulimits to apply to containers by default (name=soft:hard).'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l device-ownership-from-security-context -d 'Set devices\' uid/gid ownership from runAsUser/runAsGroup.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l disable-hostport-mapping -d 'If true, CRI-O would disable the hostport mapping.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l hostport-mapping-backend -r -d 'The backend used to program the container hostport mappings. Supported values: \'iptables\', \'nftables\'.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l drop-infra-ctr -d 'Determines whether pods are created without an infra container, when the pod is not using a pod level PID namespace.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l enable-criu-support -d 'Enable CRIU integration, requires that the criu binary is available in $PATH.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l enable-metrics -d 'Enable metrics endpoint for the server.'
@@ -82,6 +87,10 @@ complete -c crio -n '__fish_crio_no_subcommand' -f -l hooks-dir -r -d 'Set the O
     Kubernetes configuration are considered. Bind mounts that CRI-O
     inserts by default (e.g. \'/dev/shm\') are not considered.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l hostnetwork-disable-selinux -d 'Determines whether SELinux should be disabled within a pod when it is running in the host network namespace.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l image-gc-interval -r -d 'Interval in which CRI-O garbage collects unused images. Pinned images and images in use by containers are never removed. 0 disables the image garbage collection.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l image-gc-keep-per-repository -r -d 'Only keep this amount of the most recently used images per repository. 0 means no limit.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l image-gc-max-age -r -d 'Remove images which have not been used by any container for this duration. 0 means no age limit.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l image-gc-max-store-size -r -d 'Remove the least recently used images until all images take less than this amount of bytes. 0 means no limit.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l image-volumes -r -d 'Image volume handling (\'mkdir\', \'bind\', or \'ignore\')
     1. mkdir: A directory is created inside the container root filesystem for
        the volumes.
@@ -112,6 +121,8 @@ complete -c crio -n '__fish_crio_no_subcommand' -f -l log-format -r -d 'Set the
 complete -c crio -n '__fish_crio_no_subcommand' -f -l log-journald -d 'Log to systemd journal (journald) in addition to kubernetes log file.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l log-level -s l -r -d 'Log messages above specified level: trace, debug, info, warn, error, fatal or panic.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l log-size-max -r -d 'Maximum log size in bytes for a container. If it is positive, it must be >= 8192 to match/exceed conmon read buffer. This option is deprecated. The Kubelet flag \'--container-log-max-size\' should be used instead.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l max-concurrent-image-pulls -r -d 'Maximum number of image pulls running at the same time. Further pulls are queued. 0 means unlimited.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l max-concurrent-image-pulls-per-registry -r -d 'Maximum number of image pulls from a single registry running at the same time. Further pulls are queued. 0 means unlimited.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l metrics-cert -r -d 'Certificate for the secure metrics endpoint.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l metrics-collectors -r -d 'Enabled metrics collectors.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l metrics-host -r -d 'Host for the metrics endpoint.'
@@ -123,6 +134,7 @@ complete -c crio -n '__fish_crio_no_subcommand' -f -l minimum-mappable-uid -r -d
 complete -c crio -n '__fish_crio_no_subcommand' -f -l namespaces-dir -r -d 'The directory where the state of the managed namespaces gets tracked. Only used when manage-ns-lifecycle is true.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l no-pivot -d 'If true, the runtime will not use \'pivot_root\', but instead use \'MS_MOVE\'.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l nri-disable-connections -r -d 'Disable connections from externally started NRI plugins. (default: false)'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l nri-eviction-grace-period -r -d 'Grace period for containers evicted by an NRI plugin to stop before getting killed.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l nri-listen -r -d 'Socket to listen on for externally started NRI plugins to connect to. (default: "/var/run/nri/nri.sock")'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l nri-plugin-config-dir -r -d 'Directory to scan for configuration of pre-installed NRI plugins. (default: "/etc/nri/conf.d")'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l nri-plugin-dir -r -d 'Directory to scan for pre-installed NRI plugins to start automatically. (default: "/opt/nri/plugins")'
@@ -134,6 +146,7 @@ complete -c crio -n '__fish_crio_no_subcommand' -l pause-image-auth-file -r -d '
 complete -c crio -n '__fish_crio_no_subcommand' -f -l pids-limit -r -d 'Maximum number of processes allowed in a container. This option is deprecated. The Kubelet flag \'--pod-pids-limit\' should be used instead.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l pinned-images -r -d 'A list of images that will be excluded from the kubelet\'s garbage collection.'
 complete -c crio -n '__fish_crio_no_subcommand' -l pinns-path -r -d 'The path to find the pinns binary, which is needed to manage namespace lifecycle. Will be searched for in $PATH if empty.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l pre-pull-images -r -d 'A list of images which are pulled in the background on startup and on config reload.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l profile -d 'Enable pprof remote profiler on localhost:6060.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l profile-cpu -r -d 'Write a pprof CPU profile to the provided path.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l profile-mem -r -d 'Write a pprof memory profile to the provided path.'
@@ -143,8 +156,10 @@ complete -c crio -n '__fish_crio_no_subcommand' -f -l read-only -d 'Setup all un
 complete -c crio -n '__fish_crio_no_subcommand' -f -l registry -r -d 'Registry to be prepended when pulling unqualified images. Can be specified multiple times.'
 complete -c crio -n '__fish_crio_no_subcommand' -l root -s r -r -d 'The CRI-O root directory.'
 complete -c crio -n '__fish_crio_no_subcommand' -l runroot -r -d 'The CRI-O state directory.'
+complete -c crio -n '__fish_crio_no_subcommand' -f -l runtime-health-check-interval -r -d 'Interval in which CRI-O probes the configured runtime handlers for their binary, version and supported features. Pod sandboxes of unhealthy runtime handlers fail to be created. 0 disables the runtime handler health checks.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l runtimes -r -d 'OCI runtimes, format is \'runtime_name:runtime_path:runtime_root:runtime_type:privileged_without_host_devices:runtime_config_path:container_min_memory\'.'
 complete -c crio -n '__fish_crio_no_subcommand' -l seccomp-profile -r -d 'Path to the seccomp.json profile to be used as the runtime\'s default. If not specified, then the internal default seccomp profile will be used.'
+complete -c crio -n '__fish_crio_no_subcommand' -l seccomp-profile-record-dir -r -d 'Directory where the seccomp profiles recorded by the seccomp notifier \'record\' action are written to.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l selinux -d 'Enable selinux support. This option is deprecated, and be interpreted from whether SELinux is enabled on the host in the future.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l separate-pull-cgroup -r -d '[EXPERIMENTAL] Pull in new cgroup.'
 complete -c crio -n '__fish_crio_no_subcommand' -f -l shared-cpuset -r -d 'CPUs set that will be used for guaranteed containers that want access to shared cpus'
@@ -212,9 +227,29 @@ complete -c crio -n '__fish_seen_subcommand_from status' -l socket -s s -r -d 'a
 complete -c crio -n '__fish_seen_subcommand_from config c' -f -l help -s h -d 'show help'
 complete -r -c crio -n '__fish_seen_subcommand_from status' -a 'config c' -d 'Show the configuration of CRI-O as a TOML string.'
 complete -c crio -n '__fish_seen_subcommand_from containers container cs s' -f -l help -s h -d 'show help'
-complete -r -c crio -n '__fish_seen_subcommand_from status' -a 'containers container cs s' -d 'Display detailed information about the provided container ID.'
+complete -r -c crio -n '__fish_seen_subcommand_from status' -a 'containers container cs s' -d 'Display detailed information about the provided container ID or list all containers.'
 complete -c crio -n '__fish_seen_subcommand_from containers container cs s' -f -l id -s i -r -d 'the container ID'
+complete -c crio -n '__fish_seen_subcommand_from containers container cs s' -f -l stats -d 'display the block I/O, pressure and hugetlb statistics of the container'
+complete -c crio -n '__fish_seen_subcommand_from containers container cs s' -f -l namespace -s n -r -d 'filter by the namespace of the pod'
+complete -c crio -n '__fish_seen_subcommand_from containers container cs s' -f -l label -s l -r -d 'filter by a label, specified as \'key=value\', can be used multiple times'
+complete -c crio -n '__fish_seen_subcommand_from containers container cs s' -f -l state -r -d 'filter by the state, for example \'ready\' or \'notready\' for pods and \'running\' or \'exited\' for containers'
+complete -c crio -n '__fish_seen_subcommand_from containers container cs s' -f -l runtime-handler -r -d 'filter by the runtime handler'
+complete -c crio -n '__fish_seen_subcommand_from containers container cs s' -f -l output -s o -r -d 'output format of the list, one of: table, json'
 complete -c crio -n '__fish_seen_subcommand_from info i' -f -l help -s h -d 'show help'
 complete -r -c crio -n '__fish_seen_subcommand_from status' -a 'info i' -d 'Retrieve generic information about CRI-O, such as the cgroup and storage driver.'
+complete -c crio -n '__fish_seen_subcommand_from pods pod' -f -l help -s h -d 'show help'
+complete -r -c crio -n '__fish_seen_subcommand_from status' -a 'pods pod' -d 'List the pod sandboxes, including their cgroup parent, PID, namespace paths, IPs and runtime handler.'
+complete -c crio -n '__fish_seen_subcommand_from pods pod' -f -l namespace -s n -r -d 'filter by the namespace of the pod'
+complete -c crio -n '__fish_seen_subcommand_from pods pod' -f -l label -s l -r -d 'filter by a label, specified as \'key=value\', can be used multiple times'
+complete -c crio -n '__fish_seen_subcommand_from pods pod' -f -l state -r -d 'filter by the state, for example \'ready\' or \'notready\' for pods and \'running\' or \'exited\' for containers'
+complete -c crio -n '__fish_seen_subcommand_from pods pod' -f -l runtime-handler -r -d 'filter by the runtime handler'
+complete -c crio -n '__fish_seen_subcommand_from pods pod' -f -l output -s o -r -d 'output format of the list, one of: table, json'
+complete -c crio -n '__fish_seen_subcommand_from pulls pull p' -f -l help -s h -d 'show help'
+complete -r -c crio -n '__fish_seen_subcommand_from status' -a 'pulls pull p' -d 'Display the progress of the in-flight image pulls.'
+complete -c crio -n '__fish_seen_subcommand_from prepull pre-pull' -f -l help -s h -d 'show help'
+complete -r -c crio -n '__fish_seen_subcommand_from status' -a 'prepull pre-pull' -d 'Display the state of the images pulled in the background, configured via \'pre_pull_images\'.'
+complete -c crio -n '__fish_seen_subcommand_from prepull pre-pull' -f -l check -d 'exit with an error if not all images are present'
+complete -c crio -n '__fish_seen_subcommand_from reload' -f -l help -s h -d 'show help'
+complete -r -c crio -n '__fish_seen_subcommand_from status' -a 'reload' -d 'Display the result of the last configuration reload, including the changed options.'
 complete -c crio -n '__fish_seen_subcommand_from help h' -f -l help -s h -d 'show help'
 complete -r -c crio -n '__fish_crio_no_subcommand' -a 'help h' -d 'Shows a list of commands or help for one command'
@@ -0,0 +1,355 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cri-o/cri-o/internal/log"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+// Level specifies how verbose the audit record of a method is.
+type Level string
+
+const (
+	// LevelNone disables the audit records for a method.
+	LevelNone Level = "none"
+
+	// LevelMetadata records the caller, the result and the identifiers of
+	// the affected pod sandboxes, containers or images.
+	LevelMetadata Level = "metadata"
+
+	// LevelRequest additionally records the full request.
+	LevelRequest Level = "request"
+)
+
+// DefaultMethods are the mutating and interactive CRI methods which get
+// audited by default.
+var DefaultMethods = []string{
+	"Attach",
+	"CheckpointContainer",
+	"CreateContainer",
+	"Exec",
+	"ExecSync",
+	"PortForward",
+	"PullImage",
+	"RemoveContainer",
+	"RemoveImage",
+	"RemovePodSandbox",
+	"ReopenContainerLog",
+	"RunPodSandbox",
+	"StartContainer",
+	"StopContainer",
+	"StopPodSandbox",
+	"UpdateContainerResources",
+	"UpdateRuntimeConfig",
+}
+
+// Policy maps the CRI method names to their audit level.
+type Policy map[string]Level
+
+// ParsePolicy returns the default policy, updated by the provided entries in
+// the format "Method=level".
+func ParsePolicy(entries []string) (Policy, error) {
+	policy := Policy{}
+	for _, method := range DefaultMethods {
+		policy[method] = LevelMetadata
+	}
+
+	for _, entry := range entries {
+		method, level, ok := strings.Cut(entry, "=")
+		if !ok || method == "" {
+			return nil, fmt.Errorf("invalid audit log policy entry %q, expected format Method=level", entry)
+		}
+		switch Level(level) {
+		case LevelNone, LevelMetadata, LevelRequest:
+		default:
+			return nil, fmt.Errorf(
+				"invalid audit log level %q for method %s, supported are %q, %q and %q",
+				level, method, LevelNone, LevelMetadata, LevelRequest,
+			)
+		}
+		policy[method] = Level(level)
+	}
+
+	return policy, nil
+}
+
+// Level returns the audit level for the provided method.
+func (p Policy) Level(method string) Level {
+	if level, ok := p[method]; ok {
+		return level
+	}
+	return LevelNone
+}
+
+// Record is a single entry of the audit log.
+type Record struct {
+	Time         time.Time   `json:"time"`
+	Method       string      `json:"method"`
+	RequestID    string      `json:"requestId,omitempty"`
+	UserAgent    string      `json:"userAgent,omitempty"`
+	Peer         *PeerInfo   `json:"peer,omitempty"`
+	PodSandboxID string      `json:"podSandboxId,omitempty"`
+	ContainerID  string      `json:"containerId,omitempty"`
+	Image        string      `json:"image,omitempty"`
+	Request      interface{} `json:"request,omitempty"`
+	Error        string      `json:"error,omitempty"`
+	Duration     string      `json:"duration"`
+}
+
+// Logger writes the audit records as JSON lines to a file, which gets
+// rotated if it exceeds the maximum size.
+type Logger struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+	policy     Policy
+	file       *os.File
+	size       int64
+	mutex      sync.Mutex
+}
+
+// New creates a new audit Logger writing to the provided path. A max size of
+// zero disables the rotation.
+func New(path string, maxSize int64, maxBackups int, policy []string) (*Logger, error) {
+	p, err := ParsePolicy(policy)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("create audit log directory: %w", err)
+	}
+	l := &Logger{
+		path:       path,
+		maxSize:    maxSize,
+		maxBackups: maxBackups,
+		policy:     p,
+	}
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Close closes the underlying file.
+func (l *Logger) Close() error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.file == nil {
+		return nil
+	}
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
+
+// Write appends the record to the audit log.
+func (l *Logger) Write(record *Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("marshal audit record: %w", err)
+	}
+	data = append(data, '\n')
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.file == nil {
+		return errors.New("audit log is closed")
+	}
+	if l.maxSize > 0 && l.size > 0 && l.size+int64(len(data)) > l.maxSize {
+		if err := l.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := l.file.Write(data)
+	l.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("write audit record: %w", err)
+	}
+	return nil
+}
+
+// UnaryInterceptor writes an audit record for every unary call with an
+// audit level other than "none".
+func (l *Logger) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		method := filepath.Base(info.FullMethod)
+		level := l.policy.Level(method)
+		if level == LevelNone {
+			return handler(ctx, req)
+		}
+
+		start := time.Now()
+		resp, err := handler(ctx, req)
+
+		record := newRecord(ctx, method, level, req, resp, err)
+		record.Time = start
+		record.Duration = time.Since(start).String()
+		if writeErr := l.Write(record); writeErr != nil {
+			logrus.Warnf("Unable to write audit record for %s: %v", method, writeErr)
+		}
+
+		return resp, err
+	}
+}
+
+// The getters implemented by the CRI requests and responses.
+type (
+	podSandboxIDGetter interface{ GetPodSandboxId() string }
+	containerIDGetter  interface{ GetContainerId() string }
+	imageRefGetter     interface{ GetImageRef() string }
+	imageSpecGetter    interface{ GetImage() *types.ImageSpec }
+)
+
+// newRecord creates a record for the call.
+func newRecord(ctx context.Context, method string, level Level, req, resp interface{}, err error) *Record {
+	record := &Record{Method: method}
+
+	if id, ok := ctx.Value(log.ID{}).(string); ok {
+		record.RequestID = id
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		record.UserAgent = strings.Join(md.Get("user-agent"), " ")
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		if info, ok := p.AuthInfo.(*PeerInfo); ok {
+			record.Peer = info
+		}
+	}
+
+	// PullImage and RemoveImage requests
+	if g, ok := req.(imageSpecGetter); ok {
+		record.Image = g.GetImage().GetImage()
+	}
+	for _, msg := range []interface{}{req, resp} {
+		if g, ok := msg.(podSandboxIDGetter); ok && g.GetPodSandboxId() != "" {
+			record.PodSandboxID = g.GetPodSandboxId()
+		}
+		if g, ok := msg.(containerIDGetter); ok && g.GetContainerId() != "" {
+			record.ContainerID = g.GetContainerId()
+		}
+		if g, ok := msg.(imageRefGetter); ok && g.GetImageRef() != "" && record.Image == "" {
+			record.Image = g.GetImageRef()
+		}
+	}
+
+	if level == LevelRequest {
+		record.Request = scrubRequest(req)
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+	return record
+}
+
+// redacted replaces the secrets of recorded requests.
+const redacted = "[REDACTED]"
+
+// scrubRequest returns the request with its secrets, like registry credentials
+// or environment variable values, being redacted. The provided request does
+// not get modified.
+func scrubRequest(req interface{}) interface{} {
+	switch r := req.(type) {
+	case *types.PullImageRequest:
+		if r.Auth == nil {
+			return r
+		}
+		scrubbed := *r
+		scrubbed.Auth = &types.AuthConfig{
+			Username:      redact(r.Auth.Username),
+			Password:      redact(r.Auth.Password),
+			Auth:          redact(r.Auth.Auth),
+			ServerAddress: r.Auth.ServerAddress,
+			IdentityToken: redact(r.Auth.IdentityToken),
+			RegistryToken: redact(r.Auth.RegistryToken),
+		}
+		return &scrubbed
+
+	case *types.CreateContainerRequest:
+		if len(r.GetConfig().GetEnvs()) == 0 {
+			return r
+		}
+		config := *r.Config
+		config.Envs = make([]*types.KeyValue, 0, len(r.Config.Envs))
+		for _, env := range r.Config.Envs {
+			config.Envs = append(config.Envs, &types.KeyValue{Key: env.Key, Value: redact(env.Value)})
+		}
+		scrubbed := *r
+		scrubbed.Config = &config
+		return &scrubbed
+	}
+	return req
+}
+
+// redact returns the redacted value for non empty secrets.
+func redact(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return redacted
+}
+
+// open opens the audit log file for appending.
+func (l *Logger) open() error {
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("open audit log: %w", err)
+	}
+	stat, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("stat audit log: %w", err)
+	}
+	l.file = file
+	l.size = stat.Size()
+	return nil
+}
+
+// rotate moves the current file to the first backup and shifts the existing
+// backups, removing the oldest one.
+func (l *Logger) rotate() error {
+	if err := l.file.Close(); err != nil {
+		logrus.Warnf("Unable to close audit log %s: %v", l.path, err)
+	}
+	l.file = nil
+
+	if l.maxBackups <= 0 {
+		if err := os.Remove(l.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("remove audit log: %w", err)
+		}
+		return l.open()
+	}
+
+	for i := l.maxBackups - 1; i > 0; i-- {
+		if err := os.Rename(l.backupPath(i), l.backupPath(i+1)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("rotate audit log: %w", err)
+		}
+	}
+	if err := os.Rename(l.path, l.backupPath(1)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("rotate audit log: %w", err)
+	}
+	return l.open()
+}
+
+// backupPath returns the path of the nth backup.
+func (l *Logger) backupPath(n int) string {
+	return fmt.Sprintf("%s.%d", l.path, n)
+}
@@ -0,0 +1,279 @@
+package audit_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cri-o/cri-o/internal/audit"
+	"github.com/cri-o/cri-o/internal/log"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+// The actual test suite
+var _ = t.Describe("Audit", func() {
+	var logPath string
+
+	BeforeEach(func() {
+		logPath = filepath.Join(t.MustTempDir("audit"), "audit.log")
+	})
+
+	readRecords := func(path string) []map[string]interface{} {
+		data, err := os.ReadFile(path)
+		Expect(err).ToNot(HaveOccurred())
+		records := []map[string]interface{}{}
+		for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+			if line == "" {
+				continue
+			}
+			record := map[string]interface{}{}
+			Expect(json.Unmarshal([]byte(line), &record)).To(Succeed())
+			records = append(records, record)
+		}
+		return records
+	}
+
+	t.Describe("ParsePolicy", func() {
+		It("should audit the mutating methods by default", func() {
+			// Given
+			// When
+			policy, err := audit.ParsePolicy(nil)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(policy.Level("ExecSync")).To(Equal(audit.LevelMetadata))
+			Expect(policy.Level("ListContainers")).To(Equal(audit.LevelNone))
+		})
+
+		It("should override the default levels", func() {
+			// Given
+			// When
+			policy, err := audit.ParsePolicy([]string{"ExecSync=request", "PullImage=none", "ListContainers=metadata"})
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(policy.Level("ExecSync")).To(Equal(audit.LevelRequest))
+			Expect(policy.Level("PullImage")).To(Equal(audit.LevelNone))
+			Expect(policy.Level("ListContainers")).To(Equal(audit.LevelMetadata))
+		})
+
+		It("should fail on invalid entries", func() {
+			// Given
+			// When
+			_, errFormat := audit.ParsePolicy([]string{"ExecSync"})
+			_, errLevel := audit.ParsePolicy([]string{"ExecSync=all"})
+
+			// Then
+			Expect(errFormat).To(HaveOccurred())
+			Expect(errLevel).To(HaveOccurred())
+		})
+	})
+
+	t.Describe("Logger", func() {
+		It("should rotate the log if it exceeds the max size", func() {
+			// Given
+			sut, err := audit.New(logPath, 1, 2, nil)
+			Expect(err).ToNot(HaveOccurred())
+			defer sut.Close()
+
+			// When
+			for _, method := range []string{"A", "B", "C", "D"} {
+				Expect(sut.Write(&audit.Record{Method: method})).To(Succeed())
+			}
+
+			// Then
+			Expect(readRecords(logPath)[0]["method"]).To(Equal("D"))
+			Expect(readRecords(logPath + ".1")[0]["method"]).To(Equal("C"))
+			Expect(readRecords(logPath + ".2")[0]["method"]).To(Equal("B"))
+			Expect(logPath + ".3").NotTo(BeAnExistingFile())
+		})
+
+		It("should fail to write if closed", func() {
+			// Given
+			sut, err := audit.New(logPath, 0, 0, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sut.Close()).To(Succeed())
+
+			// When
+			err = sut.Write(&audit.Record{Method: "A"})
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	t.Describe("UnaryInterceptor", func() {
+		const containerID = "container-id"
+
+		call := func(sut *audit.Logger, method string, req interface{}, handlerErr error) {
+			ctx := context.WithValue(context.Background(), log.ID{}, "request-id")
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("user-agent", "kubelet/v1.30"))
+			ctx = peer.NewContext(ctx, &peer.Peer{AuthInfo: &audit.PeerInfo{PID: 1, UID: 2, GID: 3}})
+			info := &grpc.UnaryServerInfo{FullMethod: "/runtime.v1.RuntimeService/" + method}
+			_, err := sut.UnaryInterceptor()(ctx, req, info, func(context.Context, interface{}) (interface{}, error) {
+				return &types.ExecSyncResponse{}, handlerErr
+			})
+			if handlerErr == nil {
+				Expect(err).ToNot(HaveOccurred())
+			} else {
+				Expect(err).To(Equal(handlerErr))
+			}
+		}
+
+		It("should record the caller and the affected container", func() {
+			// Given
+			sut, err := audit.New(logPath, 0, 0, nil)
+			Expect(err).ToNot(HaveOccurred())
+			defer sut.Close()
+
+			// When
+			call(sut, "ExecSync", &types.ExecSyncRequest{ContainerId: containerID, Cmd: []string{"secret"}}, nil)
+
+			// Then
+			records := readRecords(logPath)
+			Expect(records).To(HaveLen(1))
+			Expect(records[0]).To(HaveKeyWithValue("method", "ExecSync"))
+			Expect(records[0]).To(HaveKeyWithValue("requestId", "request-id"))
+			Expect(records[0]).To(HaveKeyWithValue("userAgent", "kubelet/v1.30"))
+			Expect(records[0]).To(HaveKeyWithValue("containerId", containerID))
+			Expect(records[0]).To(HaveKeyWithValue("peer", map[string]interface{}{
+				"pid": float64(1), "uid": float64(2), "gid": float64(3),
+			}))
+			Expect(records[0]).NotTo(HaveKey("request"))
+		})
+
+		It("should record the request and the error if configured", func() {
+			// Given
+			sut, err := audit.New(logPath, 0, 0, []string{"ExecSync=request"})
+			Expect(err).ToNot(HaveOccurred())
+			defer sut.Close()
+
+			// When
+			call(sut, "ExecSync", &types.ExecSyncRequest{ContainerId: containerID, Cmd: []string{"ls"}}, errors.New("error"))
+
+			// Then
+			records := readRecords(logPath)
+			Expect(records).To(HaveLen(1))
+			Expect(records[0]).To(HaveKeyWithValue("error", "error"))
+			Expect(records[0]["request"]).To(HaveKeyWithValue("cmd", []interface{}{"ls"}))
+		})
+
+		It("should record the requested image", func() {
+			// Given
+			sut, err := audit.New(logPath, 0, 0, nil)
+			Expect(err).ToNot(HaveOccurred())
+			defer sut.Close()
+
+			// When
+			call(sut, "PullImage", &types.PullImageRequest{Image: &types.ImageSpec{Image: "quay.io/crio/image:latest"}}, nil)
+
+			// Then
+			records := readRecords(logPath)
+			Expect(records).To(HaveLen(1))
+			Expect(records[0]).To(HaveKeyWithValue("image", "quay.io/crio/image:latest"))
+		})
+
+		It("should redact the registry credentials of the request", func() {
+			// Given
+			sut, err := audit.New(logPath, 0, 0, []string{"PullImage=request"})
+			Expect(err).ToNot(HaveOccurred())
+			defer sut.Close()
+			req := &types.PullImageRequest{
+				Image: &types.ImageSpec{Image: "quay.io/crio/image:latest"},
+				Auth: &types.AuthConfig{
+					Username:      "user-secret",
+					Password:      "password-secret",
+					Auth:          "auth-secret",
+					ServerAddress: "quay.io",
+					IdentityToken: "identity-secret",
+					RegistryToken: "registry-secret",
+				},
+			}
+
+			// When
+			call(sut, "PullImage", req, nil)
+
+			// Then
+			data, err := os.ReadFile(logPath)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(data)).NotTo(ContainSubstring("secret"))
+			records := readRecords(logPath)
+			Expect(records).To(HaveLen(1))
+			Expect(records[0]["request"]).To(HaveKeyWithValue("auth", HaveKeyWithValue("server_address", "quay.io")))
+			Expect(records[0]["request"]).To(HaveKeyWithValue("auth", HaveKeyWithValue("password", "[REDACTED]")))
+			Expect(req.Auth.Password).To(Equal("password-secret"))
+		})
+
+		It("should redact the environment variable values of the request", func() {
+			// Given
+			sut, err := audit.New(logPath, 0, 0, []string{"CreateContainer=request"})
+			Expect(err).ToNot(HaveOccurred())
+			defer sut.Close()
+			req := &types.CreateContainerRequest{
+				PodSandboxId: "pod-id",
+				Config: &types.ContainerConfig{
+					Envs: []*types.KeyValue{{Key: "TOKEN", Value: "token-secret"}},
+				},
+			}
+
+			// When
+			call(sut, "CreateContainer", req, nil)
+
+			// Then
+			data, err := os.ReadFile(logPath)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(data)).NotTo(ContainSubstring("secret"))
+			Expect(string(data)).To(ContainSubstring("TOKEN"))
+			Expect(req.Config.Envs[0].Value).To(Equal("token-secret"))
+		})
+
+		It("should not record methods with level none", func() {
+			// Given
+			sut, err := audit.New(logPath, 0, 0, nil)
+			Expect(err).ToNot(HaveOccurred())
+			defer sut.Close()
+
+			// When
+			call(sut, "ListContainers", &types.ListContainersRequest{}, nil)
+
+			// Then
+			Expect(readRecords(logPath)).To(BeEmpty())
+		})
+	})
+
+	t.Describe("PeerCredentials", func() {
+		It("should provide the credentials of the unix socket peer", func() {
+			// Given
+			socketPath := filepath.Join(t.MustTempDir("audit"), "audit.sock")
+			listener, err := net.Listen("unix", socketPath)
+			Expect(err).ToNot(HaveOccurred())
+			defer listener.Close()
+			client, err := net.Dial("unix", socketPath)
+			Expect(err).ToNot(HaveOccurred())
+			defer client.Close()
+			conn, err := listener.Accept()
+			Expect(err).ToNot(HaveOccurred())
+			defer conn.Close()
+
+			// When
+			_, authInfo, err := audit.NewPeerCredentials().ServerHandshake(conn)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(authInfo).To(BeAssignableToTypeOf(&audit.PeerInfo{}))
+			info, ok := authInfo.(*audit.PeerInfo)
+			Expect(ok).To(BeTrue())
+			Expect(info.PID).To(BeEquivalentTo(os.Getpid()))
+			Expect(info.UID).To(BeEquivalentTo(os.Getuid()))
+		})
+	})
+})
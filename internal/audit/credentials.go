@@ -0,0 +1,75 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"github.com/sirupsen/logrus"
+	"github.com/soheilhy/cmux"
+	"google.golang.org/grpc/credentials"
+)
+
+// PeerInfo contains the credentials of the process connected to the unix
+// socket.
+type PeerInfo struct {
+	credentials.CommonAuthInfo `json:"-"`
+
+	PID int32  `json:"pid"`
+	UID uint32 `json:"uid"`
+	GID uint32 `json:"gid"`
+}
+
+// AuthType returns the authentication type of the peer.
+func (*PeerInfo) AuthType() string {
+	return "peercred"
+}
+
+// peerCredentials are insecure transport credentials, which provide the
+// credentials of the unix socket peer as AuthInfo.
+type peerCredentials struct{}
+
+// NewPeerCredentials returns transport credentials for the gRPC server, which
+// make the unix socket peer credentials available via peer.FromContext.
+func NewPeerCredentials() credentials.TransportCredentials {
+	return &peerCredentials{}
+}
+
+// ClientHandshake is not supported, the credentials are for servers only.
+func (*peerCredentials) ClientHandshake(context.Context, string, net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	return nil, nil, errors.New("peer credentials are not supported on the client side")
+}
+
+// ServerHandshake retrieves the peer credentials of the connection.
+func (*peerCredentials) ServerHandshake(conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	if muxConn, ok := conn.(*cmux.MuxConn); ok {
+		conn = muxConn.Conn
+	}
+	unixConn, ok := conn.(*net.UnixConn)
+	if !ok {
+		return conn, nil, nil
+	}
+	info, err := getPeerInfo(unixConn)
+	if err != nil {
+		// Do not reject the connection only because the credentials are unknown
+		logrus.Debugf("Unable to get peer credentials: %v", err)
+		return conn, nil, nil
+	}
+	info.SecurityLevel = credentials.NoSecurity
+	return conn, info, nil
+}
+
+// Info returns the protocol info of the credentials.
+func (*peerCredentials) Info() credentials.ProtocolInfo {
+	return credentials.ProtocolInfo{SecurityProtocol: "insecure"}
+}
+
+// Clone returns a copy of the credentials.
+func (*peerCredentials) Clone() credentials.TransportCredentials {
+	return &peerCredentials{}
+}
+
+// OverrideServerName is a no-op for peer credentials.
+func (*peerCredentials) OverrideServerName(string) error {
+	return nil
+}
@@ -0,0 +1,30 @@
+package audit
+
+import (
+	"net"
+
+	"golang.org/x/sys/unix"
+)
+
+// getPeerInfo returns the credentials of the process connected to the socket.
+func getPeerInfo(conn *net.UnixConn) (*PeerInfo, error) {
+	rawConn, err := conn.SyscallConn()
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		ucred   *unix.Ucred
+		credErr error
+	)
+	if err := rawConn.Control(func(fd uintptr) {
+		ucred, credErr = unix.GetsockoptUcred(int(fd), unix.SOL_SOCKET, unix.SO_PEERCRED)
+	}); err != nil {
+		return nil, err
+	}
+	if credErr != nil {
+		return nil, credErr
+	}
+
+	return &PeerInfo{PID: ucred.Pid, UID: ucred.Uid, GID: ucred.Gid}, nil
+}
@@ -0,0 +1,14 @@
+//go:build !linux
+// +build !linux
+
+package audit
+
+import (
+	"errors"
+	"net"
+)
+
+// getPeerInfo returns the credentials of the process connected to the socket.
+func getPeerInfo(*net.UnixConn) (*PeerInfo, error) {
+	return nil, errors.New("peer credentials are not supported on this platform")
+}
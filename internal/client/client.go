@@ -2,10 +2,13 @@ package client
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net"
 	"net/http"
+	"net/url"
+	"strings"
 	"syscall"
 	"time"
 
@@ -22,7 +25,13 @@ const (
 type CrioClient interface {
 	DaemonInfo() (types.CrioInfo, error)
 	ContainerInfo(string) (*types.ContainerInfo, error)
+	ContainersInfo(*types.ListFilter) ([]types.ContainerListInfo, error)
+	ContainerStats(string) (*types.ContainerStatsInfo, error)
 	ConfigInfo() (string, error)
+	PodsInfo(*types.ListFilter) ([]types.PodInfo, error)
+	PullsInfo() ([]types.ImagePullInfo, error)
+	PrePullInfo() (*types.PrePullInfo, error)
+	ReloadInfo() (*types.ReloadInfo, error)
 }
 
 type crioClientImpl struct {
@@ -106,6 +115,92 @@ func (c *crioClientImpl) ContainerInfo(id string) (*types.ContainerInfo, error)
 	return &cInfo, nil
 }
 
+// ContainersInfo returns the containers matching the filter by querying the
+// cri-o container list endpoint.
+func (c *crioClientImpl) ContainersInfo(filter *types.ListFilter) ([]types.ContainerListInfo, error) {
+	containers := []types.ContainerListInfo{}
+	if err := c.getList(server.InspectContainersEndpoint, filter, &containers); err != nil {
+		return nil, fmt.Errorf("list containers: %w", err)
+	}
+	return containers, nil
+}
+
+// PodsInfo returns the pod sandboxes matching the filter by querying the
+// cri-o pod list endpoint.
+func (c *crioClientImpl) PodsInfo(filter *types.ListFilter) ([]types.PodInfo, error) {
+	pods := []types.PodInfo{}
+	if err := c.getList(server.InspectPodsEndpoint, filter, &pods); err != nil {
+		return nil, fmt.Errorf("list pods: %w", err)
+	}
+	return pods, nil
+}
+
+// getList decodes the response of a list endpoint queried with the filter.
+func (c *crioClientImpl) getList(endpoint string, filter *types.ListFilter, list any) error {
+	query := url.Values{}
+	if filter != nil {
+		if filter.Namespace != "" {
+			query.Set(server.InspectNamespaceFilter, filter.Namespace)
+		}
+		for key, value := range filter.Labels {
+			query.Add(server.InspectLabelFilter, key+"="+value)
+		}
+		if filter.State != "" {
+			query.Set(server.InspectStateFilter, filter.State)
+		}
+		if filter.RuntimeHandler != "" {
+			query.Set(server.InspectRuntimeHandlerFilter, filter.RuntimeHandler)
+		}
+	}
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	req, err := c.getRequest(endpoint)
+	if err != nil {
+		return err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+		return errors.New(strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(resp.Body).Decode(list)
+}
+
+// ContainerStats returns the block I/O, pressure and hugetlb statistics of
+// the container by querying the cri-o container stats endpoint.
+func (c *crioClientImpl) ContainerStats(id string) (*types.ContainerStatsInfo, error) {
+	req, err := c.getRequest(server.InspectContainersEndpoint + "/" + id + "/stats")
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("get container stats: %s", strings.TrimSpace(string(body)))
+	}
+	stats := types.ContainerStatsInfo{}
+	if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
 // ConfigInfo returns current config as TOML string
 func (c *crioClientImpl) ConfigInfo() (string, error) {
 	req, err := c.getRequest(server.InspectConfigEndpoint)
@@ -123,3 +218,60 @@ func (c *crioClientImpl) ConfigInfo() (string, error) {
 	}
 	return string(body), nil
 }
+
+// PullsInfo returns the in-flight image pulls by querying
+// the cri-o pulls endpoint.
+func (c *crioClientImpl) PullsInfo() ([]types.ImagePullInfo, error) {
+	req, err := c.getRequest(server.InspectPullsEndpoint)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	pulls := []types.ImagePullInfo{}
+	if err := json.NewDecoder(resp.Body).Decode(&pulls); err != nil {
+		return nil, err
+	}
+	return pulls, nil
+}
+
+// PrePullInfo returns the state of the images pulled in the background by
+// querying the cri-o pre-pull endpoint.
+func (c *crioClientImpl) PrePullInfo() (*types.PrePullInfo, error) {
+	req, err := c.getRequest(server.InspectPrePullEndpoint)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	info := types.PrePullInfo{}
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// ReloadInfo returns the result of the last configuration reload by querying
+// the cri-o reload endpoint.
+func (c *crioClientImpl) ReloadInfo() (*types.ReloadInfo, error) {
+	req, err := c.getRequest(server.InspectReloadEndpoint)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	info := types.ReloadInfo{}
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
@@ -9,6 +9,7 @@ import (
 	"sigs.k8s.io/yaml"
 
 	"github.com/intel/goresctrl/pkg/blockio"
+	rspec "github.com/opencontainers/runtime-spec/specs-go"
 )
 
 type Config struct {
@@ -26,6 +27,25 @@ func New() *Config {
 	return c
 }
 
+// Copy returns a copy of the configuration, which can load another
+// configuration file without changing this one.
+func (c *Config) Copy() *Config {
+	res := *c
+	return &res
+}
+
+// Activate applies the already loaded configuration to the system again,
+// without reading the configuration file.
+func (c *Config) Activate() error {
+	if !c.Enabled() {
+		return nil
+	}
+	if err := blockio.SetConfig(c.config, true); err != nil {
+		return fmt.Errorf("configuring blockio failed: %w", err)
+	}
+	return nil
+}
+
 // Enabled returns true if blockio is enabled in the system
 func (c *Config) Enabled() bool {
 	return c.enabled
@@ -84,3 +104,17 @@ func (c *Config) Load(path string) error {
 	c.enabled = true
 	return nil
 }
+
+// OciLinuxBlockIO returns the OCI block IO parameters of a class, which is
+// not set by annotations, if blockio is enabled.
+func (c *Config) OciLinuxBlockIO(containerName, class string) (*rspec.LinuxBlockIO, error) {
+	if !c.Enabled() {
+		return nil, fmt.Errorf("blockio disabled, refusing to set blockio class of container %q to %q", containerName, class)
+	}
+	if c.ReloadRequired() {
+		if err := c.Reload(); err != nil {
+			logrus.Warnf("Reconfiguring blockio for container %s failed: %v", containerName, err)
+		}
+	}
+	return blockio.OciLinuxBlockIO(class)
+}
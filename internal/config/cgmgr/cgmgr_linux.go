@@ -154,6 +154,33 @@ func VerifyMemoryIsEnough(memoryLimit, minMemory int64) error {
 	return nil
 }
 
+// PodOOMCount returns how often the pod cgroup of the container reached its
+// memory limit and invoked the OOM killer. Reaching the memory limit of the
+// container itself is not counted. The count is only available on cgroup v2
+// and always zero on cgroup v1.
+func PodOOMCount(cgMgr CgroupManager, sbParent, containerID string) (uint64, error) {
+	if !node.CgroupIsV2() {
+		return 0, nil
+	}
+	ctrPath, err := cgMgr.ContainerCgroupAbsolutePath(sbParent, containerID)
+	if err != nil {
+		return 0, err
+	}
+	eventsFile := filepath.Join(cgroupMemoryPathV2, filepath.Dir(ctrPath), "memory.events.local")
+	fileData, err := os.ReadFile(eventsFile)
+	if err != nil {
+		return 0, fmt.Errorf("unable to read memory events of pod cgroup: %w", err)
+	}
+	for _, line := range strings.Split(string(fileData), "\n") {
+		key, value, ok := strings.Cut(line, " ")
+		if !ok || key != "oom" {
+			continue
+		}
+		return strconv.ParseUint(strings.TrimSpace(value), 10, 64)
+	}
+	return 0, nil
+}
+
 // MoveProcessToContainerCgroup moves process to the container cgroup
 func MoveProcessToContainerCgroup(containerPid, commandPid int) error {
 	parentCgroupFile := fmt.Sprintf("/proc/%d/cgroup", containerPid)
@@ -47,6 +47,12 @@ func MoveProcessToContainerCgroup(containerPid, commandPid int) error {
 	return nil
 }
 
+// PodOOMCount returns how often the pod cgroup of the container reached its
+// memory limit and invoked the OOM killer.
+func PodOOMCount(cgMgr CgroupManager, sbParent, containerID string) (uint64, error) {
+	return 0, nil
+}
+
 // VerifyMemoryIsEnough verifies that the cgroup memory limit is above a specified minimum memory limit.
 func VerifyMemoryIsEnough(memoryLimit, containerMinMemory int64) error {
 	return nil
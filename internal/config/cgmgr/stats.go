@@ -0,0 +1,50 @@
+package cgmgr
+
+import "strings"
+
+// BlkioStatsAggregator aggregates the block I/O counters of the cgroup v1
+// blkio controller, which are reported per device and operation, to the read
+// and write bytes and operations per device.
+type BlkioStatsAggregator struct {
+	stats []BlkioDeviceStats
+}
+
+// AddServiceBytes adds the bytes transferred by the operation op of the device.
+// Operations other than reads and writes are ignored.
+func (a *BlkioStatsAggregator) AddServiceBytes(major, minor uint64, op string, value uint64) {
+	switch {
+	case strings.EqualFold(op, "read"):
+		a.device(major, minor).ReadBytes += value
+	case strings.EqualFold(op, "write"):
+		a.device(major, minor).WriteBytes += value
+	}
+}
+
+// AddServiced adds the amount of operations op of the device. Operations
+// other than reads and writes are ignored.
+func (a *BlkioStatsAggregator) AddServiced(major, minor uint64, op string, value uint64) {
+	switch {
+	case strings.EqualFold(op, "read"):
+		a.device(major, minor).ReadOps += value
+	case strings.EqualFold(op, "write"):
+		a.device(major, minor).WriteOps += value
+	}
+}
+
+// Stats returns the aggregated stats in the order the devices got added.
+func (a *BlkioStatsAggregator) Stats() []BlkioDeviceStats {
+	if a.stats == nil {
+		return []BlkioDeviceStats{}
+	}
+	return a.stats
+}
+
+func (a *BlkioStatsAggregator) device(major, minor uint64) *BlkioDeviceStats {
+	for i := range a.stats {
+		if a.stats[i].Major == major && a.stats[i].Minor == minor {
+			return &a.stats[i]
+		}
+	}
+	a.stats = append(a.stats, BlkioDeviceStats{Major: major, Minor: minor})
+	return &a.stats[len(a.stats)-1]
+}
@@ -1,8 +1,12 @@
 package cgmgr
 
 import (
+	"errors"
 	"math"
+	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
@@ -10,6 +14,7 @@ import (
 	libctrcgroups "github.com/opencontainers/runc/libcontainer/cgroups"
 	"github.com/opencontainers/runc/libcontainer/cgroups/manager"
 	cgcfgs "github.com/opencontainers/runc/libcontainer/configs"
+	"github.com/sirupsen/logrus"
 )
 
 // This is a universal stats object to be used across different runtime implementations.
@@ -20,6 +25,9 @@ type CgroupStats struct {
 	Memory     *MemoryStats
 	CPU        *CPUStats
 	Pid        *PidsStats
+	Hugetlb    map[string]HugetlbStats
+	Blkio      []BlkioDeviceStats
+	Pressure   *PressureStats
 	SystemNano int64
 }
 
@@ -57,6 +65,48 @@ type PidsStats struct {
 	Limit   uint64
 }
 
+// HugetlbStats contains the hugetlb usage for a single page size.
+type HugetlbStats struct {
+	Usage    uint64
+	MaxUsage uint64
+	Failcnt  uint64
+}
+
+// BlkioDeviceStats contains the block I/O usage for a single device.
+type BlkioDeviceStats struct {
+	Major      uint64
+	Minor      uint64
+	ReadBytes  uint64
+	WriteBytes uint64
+	ReadOps    uint64
+	WriteOps   uint64
+}
+
+// PressureStats contains the pressure stall information (PSI) of a cgroup.
+// It is only available on cgroup v2.
+type PressureStats struct {
+	CPU    *PSIStats
+	Memory *PSIStats
+	IO     *PSIStats
+}
+
+// PSIStats contains the pressure stall information of a single resource.
+type PSIStats struct {
+	// Some is the share of time in which at least some tasks are stalled.
+	Some PSIData
+	// Full is the share of time in which all tasks are stalled.
+	Full PSIData
+}
+
+// PSIData contains the stall averages in percent and the total stall time
+// in microseconds.
+type PSIData struct {
+	Avg10  float64
+	Avg60  float64
+	Avg300 float64
+	Total  uint64
+}
+
 // MemLimitGivenSystem limit returns the memory limit for a given cgroup
 // If the configured memory limit is larger than the total memory on the sys, the
 // physical system memory size is returned
@@ -103,6 +153,20 @@ func libctrManager(cgroup, parent string, systemd bool) (libctrcgroups.Manager,
 	return manager.New(cg)
 }
 
+// statsFromLibctrMgr returns the cgroup stats of the provided libcontainer
+// cgroup manager, including the pressure stall information on cgroup v2.
+func statsFromLibctrMgr(cgMgr libctrcgroups.Manager) (*CgroupStats, error) {
+	stats, err := cgMgr.GetStats()
+	if err != nil {
+		return nil, err
+	}
+	cgStats := libctrStatsToCgroupStats(stats)
+	if node.CgroupIsV2() {
+		cgStats.Pressure = cgroupPressureStats(cgMgr.Path(""))
+	}
+	return cgStats, nil
+}
+
 func libctrStatsToCgroupStats(stats *libctrcgroups.Stats) *CgroupStats {
 	return &CgroupStats{
 		Memory: cgroupMemStats(&stats.MemoryStats),
@@ -111,10 +175,103 @@ func libctrStatsToCgroupStats(stats *libctrcgroups.Stats) *CgroupStats {
 			Current: stats.PidsStats.Current,
 			Limit:   stats.PidsStats.Limit,
 		},
+		Hugetlb:    cgroupHugetlbStats(stats.HugetlbStats),
+		Blkio:      cgroupBlkioStats(&stats.BlkioStats),
 		SystemNano: time.Now().UnixNano(),
 	}
 }
 
+// cgroupBlkioStats aggregates the read and write bytes and operations per
+// device. libcontainer reports the io.stat of cgroup v2 in the same format as
+// the blkio controller of cgroup v1.
+func cgroupBlkioStats(blkioStats *libctrcgroups.BlkioStats) []BlkioDeviceStats {
+	aggregator := &BlkioStatsAggregator{}
+	for _, entry := range blkioStats.IoServiceBytesRecursive {
+		aggregator.AddServiceBytes(entry.Major, entry.Minor, entry.Op, entry.Value)
+	}
+	for _, entry := range blkioStats.IoServicedRecursive {
+		aggregator.AddServiced(entry.Major, entry.Minor, entry.Op, entry.Value)
+	}
+	return aggregator.Stats()
+}
+
+// cgroupPressureStats reads the pressure stall information from the
+// provided cgroup v2 directory. Resources without PSI support are nil.
+func cgroupPressureStats(cgroupPath string) *PressureStats {
+	if cgroupPath == "" {
+		return nil
+	}
+	return &PressureStats{
+		CPU:    readPSIStats(filepath.Join(cgroupPath, "cpu.pressure")),
+		Memory: readPSIStats(filepath.Join(cgroupPath, "memory.pressure")),
+		IO:     readPSIStats(filepath.Join(cgroupPath, "io.pressure")),
+	}
+}
+
+// readPSIStats parses a PSI file, which has the format:
+//
+//	some avg10=0.00 avg60=0.00 avg300=0.00 total=0
+//	full avg10=0.00 avg60=0.00 avg300=0.00 total=0
+func readPSIStats(path string) *PSIStats {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			logrus.Debugf("Unable to read pressure stall information %s: %v", path, err)
+		}
+		return nil
+	}
+
+	res := &PSIStats{}
+	for _, line := range strings.Split(string(content), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		var data *PSIData
+		switch fields[0] {
+		case "some":
+			data = &res.Some
+		case "full":
+			data = &res.Full
+		default:
+			continue
+		}
+		for _, field := range fields[1:] {
+			key, value, ok := strings.Cut(field, "=")
+			if !ok {
+				continue
+			}
+			var err error
+			switch key {
+			case "avg10":
+				data.Avg10, err = strconv.ParseFloat(value, 64)
+			case "avg60":
+				data.Avg60, err = strconv.ParseFloat(value, 64)
+			case "avg300":
+				data.Avg300, err = strconv.ParseFloat(value, 64)
+			case "total":
+				data.Total, err = strconv.ParseUint(value, 10, 64)
+			}
+			if err != nil {
+				logrus.Debugf("Unable to parse %q of pressure stall information %s: %v", field, path, err)
+			}
+		}
+	}
+	return res
+}
+
+func cgroupHugetlbStats(hugetlbStats map[string]libctrcgroups.HugetlbStats) map[string]HugetlbStats {
+	res := make(map[string]HugetlbStats, len(hugetlbStats))
+	for pageSize, stats := range hugetlbStats {
+		res[pageSize] = HugetlbStats{
+			Usage:    stats.Usage,
+			MaxUsage: stats.MaxUsage,
+			Failcnt:  stats.Failcnt,
+		}
+	}
+	return res
+}
+
 func cgroupMemStats(memStats *libctrcgroups.MemoryStats) *MemoryStats {
 	var (
 		workingSetBytes  uint64
@@ -0,0 +1,63 @@
+package cgmgr
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	libctrcgroups "github.com/opencontainers/runc/libcontainer/cgroups"
+)
+
+func TestCgroupPressureStats(t *testing.T) {
+	dir := t.TempDir()
+	content := "some avg10=1.50 avg60=0.75 avg300=0.25 total=1234\nfull avg10=0.50 avg60=0.00 avg300=0.00 total=567\n"
+	if err := os.WriteFile(filepath.Join(dir, "io.pressure"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	stats := cgroupPressureStats(dir)
+	if stats == nil {
+		t.Fatal("expected pressure stats, got nil")
+	}
+	if stats.CPU != nil || stats.Memory != nil {
+		t.Fatalf("expected no cpu and memory pressure, got %+v and %+v", stats.CPU, stats.Memory)
+	}
+	if stats.IO == nil {
+		t.Fatal("expected io pressure, got nil")
+	}
+	if stats.IO.Some != (PSIData{Avg10: 1.5, Avg60: 0.75, Avg300: 0.25, Total: 1234}) {
+		t.Fatalf("unexpected io some pressure: %+v", stats.IO.Some)
+	}
+	if stats.IO.Full != (PSIData{Avg10: 0.5, Total: 567}) {
+		t.Fatalf("unexpected io full pressure: %+v", stats.IO.Full)
+	}
+}
+
+func TestCgroupBlkioStats(t *testing.T) {
+	stats := cgroupBlkioStats(&libctrcgroups.BlkioStats{
+		IoServiceBytesRecursive: []libctrcgroups.BlkioStatEntry{
+			{Major: 8, Minor: 0, Op: "Read", Value: 100},
+			{Major: 8, Minor: 0, Op: "Write", Value: 200},
+			{Major: 8, Minor: 0, Op: "Total", Value: 300},
+			{Major: 253, Minor: 1, Op: "Write", Value: 50},
+		},
+		IoServicedRecursive: []libctrcgroups.BlkioStatEntry{
+			{Major: 8, Minor: 0, Op: "Read", Value: 1},
+			{Major: 8, Minor: 0, Op: "Write", Value: 2},
+			{Major: 253, Minor: 1, Op: "Write", Value: 5},
+		},
+	})
+
+	expected := []BlkioDeviceStats{
+		{Major: 8, Minor: 0, ReadBytes: 100, WriteBytes: 200, ReadOps: 1, WriteOps: 2},
+		{Major: 253, Minor: 1, WriteBytes: 50, WriteOps: 5},
+	}
+	if len(stats) != len(expected) {
+		t.Fatalf("expected %d devices, got %d", len(expected), len(stats))
+	}
+	for i := range expected {
+		if stats[i] != expected[i] {
+			t.Fatalf("expected %+v, got %+v", expected[i], stats[i])
+		}
+	}
+}
@@ -11,6 +11,9 @@ type CgroupStats struct {
 	Memory     *MemoryStats
 	CPU        *CPUStats
 	Pid        *PidsStats
+	Hugetlb    map[string]HugetlbStats
+	Blkio      []BlkioDeviceStats
+	Pressure   *PressureStats
 	SystemNano int64
 }
 
@@ -48,6 +51,48 @@ type PidsStats struct {
 	Limit   uint64
 }
 
+// HugetlbStats contains the hugetlb usage for a single page size.
+type HugetlbStats struct {
+	Usage    uint64
+	MaxUsage uint64
+	Failcnt  uint64
+}
+
+// BlkioDeviceStats contains the block I/O usage for a single device.
+type BlkioDeviceStats struct {
+	Major      uint64
+	Minor      uint64
+	ReadBytes  uint64
+	WriteBytes uint64
+	ReadOps    uint64
+	WriteOps   uint64
+}
+
+// PressureStats contains the pressure stall information (PSI) of a cgroup.
+// It is only available on cgroup v2.
+type PressureStats struct {
+	CPU    *PSIStats
+	Memory *PSIStats
+	IO     *PSIStats
+}
+
+// PSIStats contains the pressure stall information of a single resource.
+type PSIStats struct {
+	// Some is the share of time in which at least some tasks are stalled.
+	Some PSIData
+	// Full is the share of time in which all tasks are stalled.
+	Full PSIData
+}
+
+// PSIData contains the stall averages in percent and the total stall time
+// in microseconds.
+type PSIData struct {
+	Avg10  float64
+	Avg60  float64
+	Avg300 float64
+	Total  uint64
+}
+
 // MemLimitGivenSystem limit returns the memory limit for a given cgroup
 // If the configured memory limit is larger than the total memory on the sys, the
 // physical system memory size is returned
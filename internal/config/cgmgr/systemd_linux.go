@@ -126,11 +126,7 @@ func (m *SystemdManager) ContainerCgroupStats(sbParent, containerID string) (*Cg
 	if err != nil {
 		return nil, err
 	}
-	stats, err := cgMgr.GetStats()
-	if err != nil {
-		return nil, err
-	}
-	return libctrStatsToCgroupStats(stats), nil
+	return statsFromLibctrMgr(cgMgr)
 }
 
 // RemoveContainerCgManager removes the cgroup manager for the container
@@ -264,11 +260,7 @@ func (m *SystemdManager) SandboxCgroupStats(sbParent, sbID string) (*CgroupStats
 	if err != nil {
 		return nil, err
 	}
-	stats, err := cgMgr.GetStats()
-	if err != nil {
-		return nil, err
-	}
-	return libctrStatsToCgroupStats(stats), nil
+	return statsFromLibctrMgr(cgMgr)
 }
 
 // RemoveSandboxCgroupManager removes cgroup manager for the sandbox
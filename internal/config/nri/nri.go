@@ -1,6 +1,7 @@
 package nri
 
 import (
+	"fmt"
 	"time"
 
 	nri "github.com/containerd/nri/pkg/adaptation"
@@ -8,6 +9,10 @@ import (
 	"github.com/containerd/ttrpc"
 )
 
+// DefaultEvictionGracePeriod is the default grace period for containers
+// evicted by a plugin to stop before getting killed.
+const DefaultEvictionGracePeriod = 10 * time.Second
+
 // Config represents the CRI-O NRI configuration.
 type Config struct {
 	Enabled                   bool          `toml:"enable_nri"`
@@ -17,7 +22,11 @@ type Config struct {
 	PluginRegistrationTimeout time.Duration `toml:"nri_plugin_registration_timeout"`
 	PluginRequestTimeout      time.Duration `toml:"nri_plugin_request_timeout"`
 	DisableConnections        bool          `toml:"nri_disable_connections"`
-	withTracing               bool
+	EvictionGracePeriod       time.Duration `toml:"nri_eviction_grace_period"`
+	// PluginPolicies restrict the adjustments of the plugins matching their
+	// index and name joined by a dash, their name or DefaultPluginPolicy.
+	PluginPolicies map[string]*PluginPolicy `toml:"nri_plugin_policies"`
+	withTracing    bool
 }
 
 // New returns the default CRI-O NRI configuration.
@@ -29,12 +38,16 @@ func New() *Config {
 		PluginConfigPath:          nri.DefaultPluginConfigPath,
 		PluginRegistrationTimeout: nri.DefaultPluginRegistrationTimeout,
 		PluginRequestTimeout:      nri.DefaultPluginRequestTimeout,
+		EvictionGracePeriod:       DefaultEvictionGracePeriod,
 	}
 }
 
 // Validate loads and validates the effective runtime NRI configuration.
 func (c *Config) Validate(onExecution bool) error {
-	return nil
+	if c.EvictionGracePeriod < 0 {
+		return fmt.Errorf("invalid NRI eviction grace period %v", c.EvictionGracePeriod)
+	}
+	return validatePluginPolicies(c.PluginPolicies)
 }
 
 func (c *Config) WithTracing(enable bool) *Config {
@@ -75,6 +88,15 @@ func (c *Config) ToOptions() []nri.Option {
 			),
 		)
 	}
+	if c != nil {
+		interceptor := newPluginInterceptor(c.PluginPolicies)
+		opts = append(opts,
+			nri.WithTTRPCOptions(
+				[]ttrpc.ClientOpts{interceptor.clientOpt},
+				[]ttrpc.ServerOpt{interceptor.serverOpt()},
+			),
+		)
+	}
 	return opts
 }
 
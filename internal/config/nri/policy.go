@@ -0,0 +1,350 @@
+package nri
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	nri "github.com/containerd/nri/pkg/adaptation"
+	"github.com/containerd/ttrpc"
+	"github.com/cri-o/cri-o/internal/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/proto"
+)
+
+// The kinds of adjustments a plugin policy can allow.
+const (
+	AdjustmentAnnotations = "annotations"
+	AdjustmentMounts      = "mounts"
+	AdjustmentEnv         = "env"
+	AdjustmentHooks       = "hooks"
+	AdjustmentDevices     = "devices"
+	AdjustmentResources   = "resources"
+	AdjustmentCgroupsPath = "cgroups_path"
+	AdjustmentRlimits     = "rlimits"
+	AdjustmentEvictions   = "evictions"
+)
+
+// DefaultPluginPolicy is the name of the policy which applies to all plugins
+// without a policy of their own.
+const DefaultPluginPolicy = "*"
+
+// validAdjustments are all kinds of adjustments a plugin policy can allow.
+var validAdjustments = map[string]bool{
+	AdjustmentAnnotations: true,
+	AdjustmentMounts:      true,
+	AdjustmentEnv:         true,
+	AdjustmentHooks:       true,
+	AdjustmentDevices:     true,
+	AdjustmentResources:   true,
+	AdjustmentCgroupsPath: true,
+	AdjustmentRlimits:     true,
+	AdjustmentEvictions:   true,
+}
+
+// PluginPolicy restricts the adjustments an NRI plugin is allowed to make.
+type PluginPolicy struct {
+	// AllowedAdjustments are the kinds of adjustments the plugin is allowed
+	// to make. All other adjustments of the plugin get rejected.
+	AllowedAdjustments []string `toml:"allowed_adjustments"`
+}
+
+// validatePluginPolicies checks that the plugin policies only allow known
+// kinds of adjustments.
+func validatePluginPolicies(policies map[string]*PluginPolicy) error {
+	for name, policy := range policies {
+		if name == "" {
+			return errors.New("NRI plugin policy requires a plugin name")
+		}
+		if policy == nil {
+			continue
+		}
+		for _, adjustment := range policy.AllowedAdjustments {
+			if !validAdjustments[adjustment] {
+				return fmt.Errorf("invalid adjustment %q in NRI plugin policy %q", adjustment, name)
+			}
+		}
+	}
+	return nil
+}
+
+// pluginIdentity is the identity a plugin registered itself with.
+type pluginIdentity struct {
+	mutex sync.RWMutex
+	idx   string
+	name  string
+}
+
+func (p *pluginIdentity) set(idx, name string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.idx = idx
+	p.name = name
+}
+
+func (p *pluginIdentity) get() (idx, name string) {
+	p.mutex.RLock()
+	defer p.mutex.RUnlock()
+	return p.idx, p.name
+}
+
+func (p *pluginIdentity) String() string {
+	idx, name := p.get()
+	return idx + "-" + name
+}
+
+// pluginInterceptor rejects the adjustments of plugins which are not allowed by
+// their policies and records which plugins requested container evictions.
+// Evictions get recorded in the EvictingPlugins of the request context.
+//
+// NRI merges the responses of all plugins, so the adjustments get checked on
+// the ttrpc connections of every single plugin instead. NRI creates the client
+// connection to a plugin right before its server connection, so both get
+// attributed to the same plugin by handing over its identity from one to the
+// other. The hand-over slot only holds a single identity, which serializes
+// the creation of the connections of concurrently connecting plugins.
+//
+// The identity of a plugin is the index and name it registers itself with.
+// Plugins connecting through the NRI socket are free to choose any identity,
+// so policies only restrict them reliably if access to the socket is limited
+// to trusted plugins or external connections are disabled.
+type pluginInterceptor struct {
+	policies map[string]map[string]bool
+	pending  chan *pluginIdentity
+}
+
+func newPluginInterceptor(policies map[string]*PluginPolicy) *pluginInterceptor {
+	i := &pluginInterceptor{
+		policies: make(map[string]map[string]bool, len(policies)),
+		pending:  make(chan *pluginIdentity, 1),
+	}
+	for name, policy := range policies {
+		allowed := map[string]bool{}
+		if policy != nil {
+			for _, adjustment := range policy.AllowedAdjustments {
+				allowed[adjustment] = true
+			}
+		}
+		i.policies[name] = allowed
+	}
+	return i
+}
+
+// clientOpt installs the interceptor for the responses of a plugin. It blocks
+// until the server connection of the previous plugin took over its identity.
+func (i *pluginInterceptor) clientOpt(c *ttrpc.Client) {
+	id := &pluginIdentity{}
+	i.pending <- id
+	ttrpc.WithChainUnaryClientInterceptor(i.clientInterceptor(id))(c)
+}
+
+// serverOpt installs the interceptor for the requests of a plugin.
+func (i *pluginInterceptor) serverOpt() ttrpc.ServerOpt {
+	return perServerOpt(func() ttrpc.ServerOpt {
+		var id *pluginIdentity
+		select {
+		case id = <-i.pending:
+		default:
+			id = &pluginIdentity{}
+		}
+		return ttrpc.WithChainUnaryServerInterceptor(i.serverInterceptor(id))
+	})
+}
+
+// perServerOpt returns a server option which applies a new option created
+// for every server. The configuration type of the servers is not exported,
+// so it gets inferred from the option type.
+func perServerOpt[O ~func(C) error, C any](newOpt func() O) O {
+	return func(c C) error {
+		return newOpt()(c)
+	}
+}
+
+func (i *pluginInterceptor) serverInterceptor(id *pluginIdentity) ttrpc.UnaryServerInterceptor {
+	return func(ctx context.Context, unmarshal ttrpc.Unmarshaler, _ *ttrpc.UnaryServerInfo, method ttrpc.Method) (interface{}, error) {
+		return method(ctx, func(req interface{}) error {
+			if err := unmarshal(req); err != nil {
+				return err
+			}
+			switch r := req.(type) {
+			case *nri.RegisterPluginRequest:
+				id.set(r.PluginIdx, r.PluginName)
+			case *nri.UpdateContainersRequest:
+				return i.check(ctx, id, "UpdateContainers", updateAdjustments(r.Update, r.Evict))
+			}
+			return nil
+		})
+	}
+}
+
+func (i *pluginInterceptor) clientInterceptor(id *pluginIdentity) ttrpc.UnaryClientInterceptor {
+	return func(ctx context.Context, req *ttrpc.Request, resp *ttrpc.Response, _ *ttrpc.UnaryClientInfo, invoker ttrpc.Invoker) error {
+		if err := invoker(ctx, req, resp); err != nil {
+			return err
+		}
+		if resp.Status != nil && resp.Status.Code != int32(codes.OK) {
+			return nil
+		}
+
+		var adjustments []string
+		switch req.Method {
+		case "CreateContainer":
+			r := &nri.CreateContainerResponse{}
+			if err := proto.Unmarshal(resp.Payload, r); err != nil {
+				return err
+			}
+			adjustments = append(containerAdjustments(r.Adjust), updateAdjustments(r.Update, r.Evict)...)
+		case "UpdateContainer":
+			r := &nri.UpdateContainerResponse{}
+			if err := proto.Unmarshal(resp.Payload, r); err != nil {
+				return err
+			}
+			if err := i.check(ctx, id, req.Method, updateAdjustments(r.Update, r.Evict)); err != nil {
+				return err
+			}
+			EvictingPluginsFromContext(ctx).record(id.String(), r.Evict)
+			return nil
+		case "StopContainer":
+			r := &nri.StopContainerResponse{}
+			if err := proto.Unmarshal(resp.Payload, r); err != nil {
+				return err
+			}
+			adjustments = updateAdjustments(r.Update, nil)
+		default:
+			return nil
+		}
+		return i.check(ctx, id, req.Method, adjustments)
+	}
+}
+
+// check returns an error if the policy of the plugin does not allow all the
+// adjustments.
+func (i *pluginInterceptor) check(ctx context.Context, id *pluginIdentity, method string, adjustments []string) error {
+	idx, name := id.get()
+	allowed, ok := i.policies[idx+"-"+name]
+	if !ok {
+		allowed, ok = i.policies[name]
+	}
+	if !ok {
+		allowed, ok = i.policies[DefaultPluginPolicy]
+	}
+	if !ok {
+		return nil
+	}
+
+	denied := []string{}
+	for _, adjustment := range adjustments {
+		if !allowed[adjustment] {
+			denied = append(denied, adjustment)
+		}
+	}
+	if len(denied) == 0 {
+		return nil
+	}
+	sort.Strings(denied)
+
+	err := fmt.Errorf("NRI plugin %q is not allowed to make %s adjustments", id, strings.Join(denied, ", "))
+	log.Warnf(ctx, "Rejecting %s of NRI plugin: %v", method, err)
+	return err
+}
+
+// containerAdjustments returns the kinds of the adjustments.
+func containerAdjustments(adjust *nri.ContainerAdjustment) []string {
+	if adjust == nil {
+		return nil
+	}
+	res := []string{}
+	if len(adjust.Annotations) > 0 {
+		res = append(res, AdjustmentAnnotations)
+	}
+	if len(adjust.Mounts) > 0 {
+		res = append(res, AdjustmentMounts)
+	}
+	if len(adjust.Env) > 0 {
+		res = append(res, AdjustmentEnv)
+	}
+	if adjust.Hooks.Hooks() != nil {
+		res = append(res, AdjustmentHooks)
+	}
+	if len(adjust.Rlimits) > 0 {
+		res = append(res, AdjustmentRlimits)
+	}
+	if linux := adjust.Linux; linux != nil {
+		if len(linux.Devices) > 0 {
+			res = append(res, AdjustmentDevices)
+		}
+		if linux.Resources != nil {
+			res = append(res, AdjustmentResources)
+		}
+		if linux.CgroupsPath != "" {
+			res = append(res, AdjustmentCgroupsPath)
+		}
+	}
+	return res
+}
+
+// updateAdjustments returns the kinds of the adjustments made by updating
+// and evicting containers.
+func updateAdjustments(updates []*nri.ContainerUpdate, evictions []*nri.ContainerEviction) []string {
+	res := []string{}
+	for _, update := range updates {
+		if update.GetLinux().GetResources() != nil {
+			res = append(res, AdjustmentResources)
+			break
+		}
+	}
+	if len(evictions) > 0 {
+		res = append(res, AdjustmentEvictions)
+	}
+	return res
+}
+
+// EvictingPlugins records the plugins which requested the eviction of
+// containers while handling a single request.
+type EvictingPlugins struct {
+	mutex   sync.Mutex
+	plugins map[string]string
+}
+
+type evictingPluginsKey struct{}
+
+// WithEvictingPlugins returns a context which records the plugins requesting
+// the eviction of containers in the returned EvictingPlugins.
+func WithEvictingPlugins(ctx context.Context) (context.Context, *EvictingPlugins) {
+	e := &EvictingPlugins{plugins: map[string]string{}}
+	return context.WithValue(ctx, evictingPluginsKey{}, e), e
+}
+
+// EvictingPluginsFromContext returns the EvictingPlugins of the context or nil.
+func EvictingPluginsFromContext(ctx context.Context) *EvictingPlugins {
+	e, ok := ctx.Value(evictingPluginsKey{}).(*EvictingPlugins)
+	if !ok {
+		return nil
+	}
+	return e
+}
+
+// Get returns the plugin which requested the eviction of the container, or
+// an empty string if the plugin is unknown.
+func (e *EvictingPlugins) Get(containerID string) string {
+	if e == nil {
+		return ""
+	}
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	return e.plugins[containerID]
+}
+
+func (e *EvictingPlugins) record(plugin string, evictions []*nri.ContainerEviction) {
+	if e == nil {
+		return
+	}
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	for _, eviction := range evictions {
+		e.plugins[eviction.GetContainerId()] = plugin
+	}
+}
@@ -0,0 +1,331 @@
+package nri
+
+import (
+	"context"
+	"net"
+	"time"
+
+	nri "github.com/containerd/nri/pkg/adaptation"
+	"github.com/containerd/ttrpc"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/protobuf/proto"
+)
+
+// register registers the plugin through the server interceptor.
+func register(e *pluginInterceptor, id *pluginIdentity, idx, name string) {
+	_, err := callServer(e, id, &nri.RegisterPluginRequest{PluginIdx: idx, PluginName: name})
+	Expect(err).NotTo(HaveOccurred())
+}
+
+// callServer passes the request of a plugin through the server interceptor.
+func callServer(e *pluginInterceptor, id *pluginIdentity, req proto.Message) (interface{}, error) {
+	unmarshal := func(v interface{}) error {
+		proto.Merge(v.(proto.Message), req)
+		return nil
+	}
+	method := func(_ context.Context, unmarshal func(interface{}) error) (interface{}, error) {
+		v := req.ProtoReflect().New().Interface()
+		if err := unmarshal(v); err != nil {
+			return nil, err
+		}
+		return v, nil
+	}
+	return e.serverInterceptor(id)(context.Background(), unmarshal, &ttrpc.UnaryServerInfo{}, method)
+}
+
+// callClient passes the response of a plugin through the client interceptor.
+func callClient(ctx context.Context, e *pluginInterceptor, id *pluginIdentity, method string, resp proto.Message) error {
+	invoker := func(_ context.Context, _ *ttrpc.Request, r *ttrpc.Response) error {
+		payload, err := proto.Marshal(resp)
+		if err != nil {
+			return err
+		}
+		r.Payload = payload
+		return nil
+	}
+	return e.clientInterceptor(id)(ctx,
+		&ttrpc.Request{Service: "nri.pkg.api.v1alpha1.Plugin", Method: method},
+		&ttrpc.Response{}, &ttrpc.UnaryClientInfo{}, invoker)
+}
+
+// The actual test suite
+var _ = t.Describe("PluginPolicy", func() {
+	t.Describe("Validate", func() {
+		It("should succeed with valid adjustments", func() {
+			// Given
+			sut := New()
+			sut.PluginPolicies = map[string]*PluginPolicy{
+				"10-device-injector": {AllowedAdjustments: []string{AdjustmentDevices, AdjustmentMounts}},
+				DefaultPluginPolicy:  {},
+			}
+
+			// When
+			err := sut.Validate(false)
+
+			// Then
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should fail with an invalid adjustment", func() {
+			// Given
+			sut := New()
+			sut.PluginPolicies = map[string]*PluginPolicy{
+				"device-injector": {AllowedAdjustments: []string{"privileged"}},
+			}
+
+			// When
+			err := sut.Validate(false)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("privileged"))
+		})
+
+		It("should fail with a negative eviction grace period", func() {
+			// Given
+			sut := New()
+			sut.EvictionGracePeriod = -time.Second
+
+			// When
+			err := sut.Validate(false)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail without a plugin name", func() {
+			// Given
+			sut := New()
+			sut.PluginPolicies = map[string]*PluginPolicy{"": {}}
+
+			// When
+			err := sut.Validate(false)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	t.Describe("ToOptions", func() {
+		It("should add the plugin interceptor", func() {
+			// Given
+			sut := New()
+			sut.PluginPolicies = map[string]*PluginPolicy{DefaultPluginPolicy: {}}
+
+			// When
+			opts := sut.ToOptions()
+
+			// Then
+			Expect(opts).To(HaveLen(4))
+		})
+	})
+
+	t.Describe("pluginInterceptor", func() {
+		var (
+			sut *pluginInterceptor
+			id  *pluginIdentity
+		)
+
+		BeforeEach(func() {
+			sut = newPluginInterceptor(map[string]*PluginPolicy{
+				"10-device-injector": {AllowedAdjustments: []string{AdjustmentDevices, AdjustmentMounts}},
+				"evictor":            {AllowedAdjustments: []string{AdjustmentEvictions}},
+				"readonly":           {},
+			})
+			id = &pluginIdentity{}
+		})
+
+		It("should hand over the identity from the client to the server connection", func() {
+			// Given
+			clientConn, serverConn := net.Pipe()
+			defer serverConn.Close()
+			client := ttrpc.NewClient(clientConn, sut.clientOpt)
+			defer client.Close()
+			Expect(sut.pending).To(HaveLen(1))
+
+			// When
+			server, err := ttrpc.NewServer(sut.serverOpt())
+
+			// Then
+			Expect(err).NotTo(HaveOccurred())
+			defer server.Close()
+			Expect(sut.pending).To(BeEmpty())
+		})
+
+		It("should serialize the connections of concurrently connecting plugins", func() {
+			// Given
+			firstConn, firstPeer := net.Pipe()
+			defer firstPeer.Close()
+			first := ttrpc.NewClient(firstConn, sut.clientOpt)
+			defer first.Close()
+			secondConn, secondPeer := net.Pipe()
+			defer secondPeer.Close()
+			connected := make(chan *ttrpc.Client)
+			go func() {
+				connected <- ttrpc.NewClient(secondConn, sut.clientOpt)
+			}()
+			Consistently(connected).ShouldNot(Receive())
+
+			// When
+			server, err := ttrpc.NewServer(sut.serverOpt())
+
+			// Then
+			Expect(err).NotTo(HaveOccurred())
+			defer server.Close()
+			var second *ttrpc.Client
+			Eventually(connected).Should(Receive(&second))
+			defer second.Close()
+			Expect(sut.pending).To(HaveLen(1))
+		})
+
+		It("should allow the adjustments of the policy", func() {
+			// Given
+			register(sut, id, "10", "device-injector")
+			adjust := &nri.ContainerAdjustment{}
+			adjust.AddDevice(&nri.LinuxDevice{Path: "/dev/foo", Type: "c"})
+			adjust.AddMount(&nri.Mount{Source: "/foo", Destination: "/foo"})
+
+			// When
+			err := callClient(context.Background(), sut, id, "CreateContainer", &nri.CreateContainerResponse{Adjust: adjust})
+
+			// Then
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should reject the adjustments not allowed by the policy", func() {
+			// Given
+			register(sut, id, "10", "device-injector")
+			adjust := &nri.ContainerAdjustment{}
+			adjust.AddDevice(&nri.LinuxDevice{Path: "/dev/foo", Type: "c"})
+			adjust.AddHooks(&nri.Hooks{Prestart: []*nri.Hook{{Path: "/bin/hook"}}})
+			adjust.AddEnv("FOO", "bar")
+
+			// When
+			err := callClient(context.Background(), sut, id, "CreateContainer", &nri.CreateContainerResponse{Adjust: adjust})
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(`"10-device-injector"`))
+			Expect(err.Error()).To(ContainSubstring("env, hooks adjustments"))
+		})
+
+		It("should match the policy by the plugin name", func() {
+			// Given
+			register(sut, id, "20", "readonly")
+			adjust := &nri.ContainerAdjustment{}
+			adjust.AddAnnotation("foo", "bar")
+
+			// When
+			err := callClient(context.Background(), sut, id, "CreateContainer", &nri.CreateContainerResponse{Adjust: adjust})
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("annotations"))
+		})
+
+		It("should not restrict plugins without a policy", func() {
+			// Given
+			register(sut, id, "10", "other")
+			adjust := &nri.ContainerAdjustment{}
+			adjust.SetLinuxCgroupsPath("/foo")
+
+			// When
+			err := callClient(context.Background(), sut, id, "CreateContainer", &nri.CreateContainerResponse{Adjust: adjust})
+
+			// Then
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should apply the default policy to plugins without a policy", func() {
+			// Given
+			sut.policies[DefaultPluginPolicy] = map[string]bool{}
+			register(sut, id, "10", "other")
+			adjust := &nri.ContainerAdjustment{}
+			adjust.AddRlimit("RLIMIT_NOFILE", 1024, 1024)
+
+			// When
+			err := callClient(context.Background(), sut, id, "CreateContainer", &nri.CreateContainerResponse{Adjust: adjust})
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("rlimits"))
+		})
+
+		It("should reject resource updates not allowed by the policy", func() {
+			// Given
+			register(sut, id, "10", "device-injector")
+			update := &nri.ContainerUpdate{ContainerId: "ctr"}
+			update.SetLinuxCPUShares(1024)
+
+			// When
+			err := callClient(context.Background(), sut, id, "StopContainer", &nri.StopContainerResponse{
+				Update: []*nri.ContainerUpdate{update},
+			})
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("resources"))
+		})
+
+		It("should allow unsolicited evictions of the policy", func() {
+			// Given
+			register(sut, id, "30", "evictor")
+
+			// When
+			_, err := callServer(sut, id, &nri.UpdateContainersRequest{
+				Evict: []*nri.ContainerEviction{{ContainerId: "ctr", Reason: "test"}},
+			})
+
+			// Then
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should reject unsolicited evictions not allowed by the policy", func() {
+			// Given
+			register(sut, id, "10", "device-injector")
+
+			// When
+			_, err := callServer(sut, id, &nri.UpdateContainersRequest{
+				Evict: []*nri.ContainerEviction{{ContainerId: "ctr", Reason: "test"}},
+			})
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("evictions"))
+		})
+
+		It("should record the plugins requesting evictions in the request context", func() {
+			// Given
+			register(sut, id, "30", "evictor")
+			ctx, plugins := WithEvictingPlugins(context.Background())
+			_, otherPlugins := WithEvictingPlugins(context.Background())
+
+			// When
+			err := callClient(ctx, sut, id, "UpdateContainer", &nri.UpdateContainerResponse{
+				Evict: []*nri.ContainerEviction{{ContainerId: "ctr", Reason: "test"}},
+			})
+
+			// Then
+			Expect(err).NotTo(HaveOccurred())
+			Expect(plugins.Get("ctr")).To(Equal("30-evictor"))
+			Expect(plugins.Get("other")).To(BeEmpty())
+			Expect(otherPlugins.Get("ctr")).To(BeEmpty())
+		})
+
+		It("should not record rejected evictions", func() {
+			// Given
+			register(sut, id, "10", "device-injector")
+			ctx, plugins := WithEvictingPlugins(context.Background())
+
+			// When
+			err := callClient(ctx, sut, id, "UpdateContainer", &nri.UpdateContainerResponse{
+				Evict: []*nri.ContainerEviction{{ContainerId: "ctr", Reason: "test"}},
+			})
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(plugins.Get("ctr")).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,26 @@
+package nri
+
+import (
+	"testing"
+
+	. "github.com/cri-o/cri-o/test/framework"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// TestNRI runs the created specs
+func TestNRI(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunFrameworkSpecs(t, "NRIConfig")
+}
+
+var t *TestFramework
+
+var _ = BeforeSuite(func() {
+	t = NewTestFramework(NilFunc, NilFunc)
+	t.Setup()
+})
+
+var _ = AfterSuite(func() {
+	t.Teardown()
+})
@@ -38,6 +38,25 @@ func New() *Config {
 	return c
 }
 
+// Copy returns a copy of the configuration, which can load another
+// configuration file without changing this one.
+func (c *Config) Copy() *Config {
+	res := *c
+	return &res
+}
+
+// Activate applies the already loaded configuration to the system again,
+// without reading the configuration file.
+func (c *Config) Activate() error {
+	if !c.Enabled() {
+		return nil
+	}
+	if err := rdt.SetConfig(c.config, true); err != nil {
+		return fmt.Errorf("configuring RDT failed: %w", err)
+	}
+	return nil
+}
+
 // Supported returns true if RDT is enabled in the host system
 func (c *Config) Supported() bool {
 	return c.supported
@@ -102,3 +121,15 @@ func (c *Config) ContainerClassFromAnnotations(containerName string, containerAn
 	}
 	return cls, nil
 }
+
+// ValidateClass checks that a class, which is not set by annotations, can be
+// assigned to a container.
+func (c *Config) ValidateClass(containerName, cls string) error {
+	if !c.Enabled() {
+		return fmt.Errorf("RDT disabled, refusing to set RDT class of container %q to %q", containerName, cls)
+	}
+	if _, ok := rdt.GetClass(cls); !ok {
+		return fmt.Errorf("RDT class %q does not exist in configuration", cls)
+	}
+	return nil
+}
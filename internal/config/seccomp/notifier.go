@@ -8,7 +8,9 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -21,16 +23,30 @@ import (
 	json "github.com/json-iterator/go"
 	"github.com/opencontainers/runtime-spec/specs-go"
 	libseccomp "github.com/seccomp/libseccomp-golang"
+	"github.com/sirupsen/logrus"
 	"golang.org/x/sys/unix"
 )
 
+// maxSyscallNr is the upper bound of the native syscall numbers which get
+// traced in record mode.
+const maxSyscallNr = 1024
+
+// recordExcludedSyscalls are the syscalls which cannot be traced in record
+// mode, because the OCI runtime requires them after installing the filter.
+// They are always part of the recorded profile.
+var recordExcludedSyscalls = []string{"write"}
+
 // Notifier wraps a seccomp notifier instance for a container.
 type Notifier struct {
 	listener       net.Listener
 	syscalls       sync.Map
+	architectures  sync.Map
 	timer          *time.Timer
 	timeLock       sync.Mutex
 	stopContainers bool
+	record         bool
+	profilePath    string
+	profileLock    sync.Mutex
 }
 
 // StopContainers returns if the notifier should stop containers or not.
@@ -38,8 +54,25 @@ func (n *Notifier) StopContainers() bool {
 	return n.stopContainers
 }
 
-// Close can be used to close the notifier listener.
+// Record returns if the notifier records the used syscalls into a seccomp
+// profile instead of blocking them.
+func (n *Notifier) Record() bool {
+	return n.record
+}
+
+// ProfilePath returns the path of the recorded seccomp profile.
+func (n *Notifier) ProfilePath() string {
+	return n.profilePath
+}
+
+// Close can be used to close the notifier listener. It also writes the
+// recorded seccomp profile if the notifier runs in record mode.
 func (n *Notifier) Close() error {
+	if n.record {
+		if err := n.WriteProfile(); err != nil {
+			logrus.Errorf("Unable to write recorded seccomp profile: %v", err)
+		}
+	}
 	return n.listener.Close()
 }
 
@@ -53,6 +86,92 @@ func (n *Notifier) AddSyscall(syscall string) {
 	}
 }
 
+// addArchitecture adds an architecture to the notifier result.
+func (n *Notifier) addArchitecture(arch seccomp.Arch) {
+	n.architectures.Store(arch, struct{}{})
+}
+
+// Profile returns a minimal seccomp profile which allows all syscalls used by
+// the container and blocks every other one.
+func (n *Notifier) Profile() *seccomp.Seccomp {
+	names := append([]string{}, recordExcludedSyscalls...)
+	n.syscalls.Range(func(syscall, _ any) bool {
+		if s, ok := syscall.(string); ok && !slices.Contains(names, s) {
+			names = append(names, s)
+		}
+		return true
+	})
+	sort.Strings(names)
+
+	architectures := []seccomp.Arch{}
+	n.architectures.Range(func(arch, _ any) bool {
+		if a, ok := arch.(seccomp.Arch); ok {
+			architectures = append(architectures, a)
+		}
+		return true
+	})
+	sort.Slice(architectures, func(i, j int) bool {
+		return architectures[i] < architectures[j]
+	})
+
+	return &seccomp.Seccomp{
+		DefaultAction: seccomp.ActErrno,
+		Architectures: architectures,
+		Syscalls: []*seccomp.Syscall{{
+			Names:  names,
+			Action: seccomp.ActAllow,
+		}},
+	}
+}
+
+// WriteProfile writes the recorded seccomp profile to the profile path.
+func (n *Notifier) WriteProfile() error {
+	if n.profilePath == "" {
+		return nil
+	}
+
+	n.profileLock.Lock()
+	defer n.profileLock.Unlock()
+
+	data, err := json.MarshalIndent(n.Profile(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal seccomp profile: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(n.profilePath), 0o700); err != nil {
+		return fmt.Errorf("create seccomp profile dir: %w", err)
+	}
+	tmpPath := n.profilePath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o600); err != nil {
+		return fmt.Errorf("write seccomp profile: %w", err)
+	}
+	if err := os.Rename(tmpPath, n.profilePath); err != nil {
+		return fmt.Errorf("rename seccomp profile: %w", err)
+	}
+	return nil
+}
+
+// loadProfile adds the syscalls and architectures of a previously recorded
+// profile to the notifier result.
+func (n *Notifier) loadProfile() error {
+	data, err := os.ReadFile(n.profilePath)
+	if err != nil {
+		return err
+	}
+	profile := &seccomp.Seccomp{}
+	if err := json.Unmarshal(data, profile); err != nil {
+		return fmt.Errorf("decode seccomp profile: %w", err)
+	}
+	for _, arch := range profile.Architectures {
+		n.addArchitecture(arch)
+	}
+	for _, syscall := range profile.Syscalls {
+		for _, name := range syscall.Names {
+			n.AddSyscall(name)
+		}
+	}
+	return nil
+}
+
 // UsedSyscalls returns a string representation of the used syscalls, sorted by
 // their name.
 func (n *Notifier) UsedSyscalls() string {
@@ -102,6 +221,8 @@ func (n *Notification) Syscall() string {
 	return n.syscall
 }
 
+// injectNotifier modifies the profile to notify about the blocked syscalls,
+// or about the allowed syscalls if the notifier should record them.
 func (c *Config) injectNotifier(
 	ctx context.Context,
 	msgChan chan Notification,
@@ -114,12 +235,34 @@ func (c *Config) injectNotifier(
 	if containerID == "" || sandboxAnnotations == nil || msgChan == nil {
 		return nil, nil
 	}
-	if _, ok := sandboxAnnotations[annotations.SeccompNotifierActionAnnotation]; !ok {
+	action, ok := sandboxAnnotations[annotations.SeccompNotifierActionAnnotation]
+	if !ok {
 		return nil, nil
 	}
 
 	log.Infof(ctx, "Injecting seccomp notifier into seccomp profile of container %s", containerID)
 
+	if action == annotations.SeccompNotifierActionRecord {
+		if err := injectRecordNotifier(profile); err != nil {
+			return nil, fmt.Errorf("inject record notifier: %w", err)
+		}
+	} else {
+		injectBlockNotifier(ctx, profile)
+	}
+
+	profile.ListenerPath = filepath.Join(c.NotifierPath(), containerID)
+
+	notifier, err := NewNotifier(ctx, msgChan, containerID, profile.ListenerPath, c.ProfileRecordDir(), sandboxAnnotations)
+	if err != nil {
+		return nil, fmt.Errorf("unable to run notifier: %w", err)
+	}
+
+	return notifier, nil
+}
+
+// injectBlockNotifier overrides the blocking actions of the profile to notify
+// about the syscalls instead.
+func injectBlockNotifier(ctx context.Context, profile *specs.LinuxSeccomp) {
 	isActionToOverride := func(action specs.LinuxSeccompAction) bool {
 		if action == specs.ActErrno ||
 			action == specs.ActKill ||
@@ -146,29 +289,101 @@ func (c *Config) injectNotifier(
 			profile.Syscalls[i].Action = specs.ActNotify
 		}
 	}
+}
 
-	profile.ListenerPath = filepath.Join(c.NotifierPath(), containerID)
+// injectRecordNotifier modifies the profile to notify about the syscalls it
+// allows, while keeping all rules blocking syscalls. If the profile allows
+// syscalls by default, then notify rules get added for all syscalls without a
+// rule of their own.
+func injectRecordNotifier(profile *specs.LinuxSeccomp) error {
+	isActionToRecord := func(action specs.LinuxSeccompAction) bool {
+		return action == specs.ActAllow || action == specs.ActLog
+	}
 
-	notifier, err := NewNotifier(ctx, msgChan, containerID, profile.ListenerPath, sandboxAnnotations)
-	if err != nil {
-		return nil, fmt.Errorf("unable to run notifier: %w", err)
+	ruled := map[string]bool{}
+	syscalls := make([]specs.LinuxSyscall, 0, len(profile.Syscalls)+1)
+	for _, syscall := range profile.Syscalls {
+		for _, name := range syscall.Names {
+			ruled[name] = true
+		}
+		if !isActionToRecord(syscall.Action) {
+			syscalls = append(syscalls, syscall)
+			continue
+		}
+
+		allowed, notified := []string{}, []string{}
+		for _, name := range syscall.Names {
+			if slices.Contains(recordExcludedSyscalls, name) {
+				allowed = append(allowed, name)
+			} else {
+				notified = append(notified, name)
+			}
+		}
+		if len(allowed) > 0 {
+			rule := syscall
+			rule.Names = allowed
+			syscalls = append(syscalls, rule)
+		}
+		if len(notified) > 0 {
+			rule := syscall
+			rule.Names = notified
+			rule.Action = specs.ActNotify
+			syscalls = append(syscalls, rule)
+		}
 	}
 
-	return notifier, nil
+	if isActionToRecord(profile.DefaultAction) {
+		names := []string{}
+		for nr := 0; nr < maxSyscallNr; nr++ {
+			name, err := libseccomp.ScmpSyscall(nr).GetName()
+			if err != nil || ruled[name] || slices.Contains(recordExcludedSyscalls, name) || slices.Contains(names, name) {
+				continue
+			}
+			names = append(names, name)
+		}
+		if len(names) == 0 {
+			return errors.New("unable to resolve any native syscall")
+		}
+		syscalls = append(syscalls, specs.LinuxSyscall{
+			Names:  names,
+			Action: specs.ActNotify,
+		})
+	}
+
+	profile.Syscalls = syscalls
+	return nil
 }
 
 // NewNotifier starts the notifier for the provided arguments.
 func NewNotifier(
 	ctx context.Context,
 	msgChan chan Notification,
-	containerID, listenerPath string,
+	containerID, listenerPath, profileRecordDir string,
 	annotationMap map[string]string,
 ) (*Notifier, error) {
+	action, ok := annotationMap[annotations.SeccompNotifierActionAnnotation]
+	if !ok {
+		return nil, fmt.Errorf("%s annotation not set on container", annotations.SeccompNotifierActionAnnotation)
+	}
+
+	notifier := &Notifier{
+		stopContainers: action == annotations.SeccompNotifierActionStop,
+		record:         action == annotations.SeccompNotifierActionRecord,
+	}
+	if notifier.record && profileRecordDir != "" {
+		notifier.profilePath = filepath.Join(profileRecordDir, containerID+".json")
+		// Continue a recording after a restart of the server
+		if err := notifier.loadProfile(); err != nil && !errors.Is(err, os.ErrNotExist) {
+			log.Warnf(ctx, "Unable to load recorded seccomp profile %s: %v", notifier.profilePath, err)
+		}
+	}
+
 	log.Infof(ctx, "Waiting for seccomp file descriptor on container %s", containerID)
 	listener, err := net.Listen("unix", listenerPath)
 	if err != nil {
 		return nil, fmt.Errorf("listen for seccomp socket: %w", err)
 	}
+	notifier.listener = listener
 
 	go func() {
 		for {
@@ -204,22 +419,90 @@ func NewNotifier(
 			}
 
 			log.Infof(ctx, "Received new seccomp fd: %v", newFd)
-			go handler(ctx, containerID, msgChan, libseccomp.ScmpFd(newFd))
+			if notifier.record {
+				go notifier.recorder(ctx, containerID, libseccomp.ScmpFd(newFd))
+			} else {
+				go handler(ctx, containerID, msgChan, libseccomp.ScmpFd(newFd))
+			}
 		}
 	}()
 
-	action, ok := annotationMap[annotations.SeccompNotifierActionAnnotation]
-	if !ok {
-		return nil, fmt.Errorf("%s annotation not set on container", annotations.SeccompNotifierActionAnnotation)
+	return notifier, nil
+}
+
+// recorder allows and records all notified syscalls until every process of
+// the container exited, and writes the recorded profile afterwards.
+func (n *Notifier) recorder(ctx context.Context, containerID string, fd libseccomp.ScmpFd) {
+	defer unix.Close(int(fd))
+	for {
+		// The kernel signals a hangup if no process uses the filter any more
+		fds := []unix.PollFd{{Fd: int32(fd), Events: unix.POLLIN}}
+		if _, err := unix.Poll(fds, -1); err != nil {
+			if errors.Is(err, unix.EINTR) {
+				continue
+			}
+			log.Errorf(ctx, "Unable to poll seccomp notifications: %v", err)
+			break
+		}
+		if fds[0].Revents&unix.POLLIN == 0 {
+			break
+		}
+
+		req, err := libseccomp.NotifReceive(fd)
+		if err != nil {
+			// The process may have been gone in the meantime
+			log.Debugf(ctx, "Unable to receive notification: %v", err)
+			continue
+		}
+
+		syscall, err := req.Data.Syscall.GetNameByArch(req.Data.Arch)
+		if err != nil {
+			log.Errorf(ctx, "Unable to decode syscall %v: %v", req.Data.Syscall, err)
+		} else {
+			n.AddSyscall(syscall)
+		}
+		if arch, ok := architectures[req.Data.Arch]; ok {
+			n.addArchitecture(arch)
+		}
+
+		resp := &libseccomp.ScmpNotifResp{
+			ID:    req.ID,
+			Error: 0,
+			Val:   uint64(0),
+			Flags: libseccomp.NotifRespFlagContinue,
+		}
+		if err := libseccomp.NotifRespond(fd, resp); err != nil {
+			log.Debugf(ctx, "Unable to send notification response: %v", err)
+		}
 	}
 
-	return &Notifier{
-		listener:       listener,
-		syscalls:       sync.Map{},
-		timer:          nil,
-		timeLock:       sync.Mutex{},
-		stopContainers: action == annotations.SeccompNotifierActionStop,
-	}, nil
+	log.Infof(ctx, "Writing recorded seccomp profile for container %s to %s", containerID, n.profilePath)
+	if err := n.WriteProfile(); err != nil {
+		log.Errorf(ctx, "Unable to write recorded seccomp profile: %v", err)
+	}
+}
+
+// architectures maps the libseccomp architectures to the profile ones.
+var architectures = map[libseccomp.ScmpArch]seccomp.Arch{
+	libseccomp.ArchX86:         seccomp.ArchX86,
+	libseccomp.ArchAMD64:       seccomp.ArchX86_64,
+	libseccomp.ArchX32:         seccomp.ArchX32,
+	libseccomp.ArchARM:         seccomp.ArchARM,
+	libseccomp.ArchARM64:       seccomp.ArchAARCH64,
+	libseccomp.ArchMIPS:        seccomp.ArchMIPS,
+	libseccomp.ArchMIPS64:      seccomp.ArchMIPS64,
+	libseccomp.ArchMIPS64N32:   seccomp.ArchMIPS64N32,
+	libseccomp.ArchMIPSEL:      seccomp.ArchMIPSEL,
+	libseccomp.ArchMIPSEL64:    seccomp.ArchMIPSEL64,
+	libseccomp.ArchMIPSEL64N32: seccomp.ArchMIPSEL64N32,
+	libseccomp.ArchPPC:         seccomp.ArchPPC,
+	libseccomp.ArchPPC64:       seccomp.ArchPPC64,
+	libseccomp.ArchPPC64LE:     seccomp.ArchPPC64LE,
+	libseccomp.ArchS390:        seccomp.ArchS390,
+	libseccomp.ArchS390X:       seccomp.ArchS390X,
+	libseccomp.ArchPARISC:      seccomp.ArchPARISC,
+	libseccomp.ArchPARISC64:    seccomp.ArchPARISC64,
+	libseccomp.ArchRISCV64:     seccomp.ArchRISCV64,
 }
 
 func handler(
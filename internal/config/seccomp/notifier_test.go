@@ -0,0 +1,191 @@
+//go:build linux && cgo
+// +build linux,cgo
+
+package seccomp_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/containers/common/pkg/seccomp"
+	seccompcfg "github.com/cri-o/cri-o/internal/config/seccomp"
+	"github.com/cri-o/cri-o/pkg/annotations"
+	json "github.com/json-iterator/go"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/opencontainers/runtime-tools/generate"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+// The actual test suite
+var _ = t.Describe("Notifier", func() {
+	const containerID = "ctr"
+
+	var (
+		listenerDir, recordDir string
+		sut                    *seccompcfg.Notifier
+	)
+
+	BeforeEach(func() {
+		listenerDir = t.MustTempDir("listener")
+		recordDir = t.MustTempDir("record")
+		sut = nil
+	})
+
+	AfterEach(func() {
+		if sut != nil {
+			Expect(sut.Close()).To(Succeed())
+		}
+	})
+
+	newNotifier := func(action string) *seccompcfg.Notifier {
+		notifier, err := seccompcfg.NewNotifier(
+			context.Background(),
+			make(chan seccompcfg.Notification),
+			containerID,
+			filepath.Join(listenerDir, containerID),
+			recordDir,
+			map[string]string{annotations.SeccompNotifierActionAnnotation: action},
+		)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(notifier).NotTo(BeNil())
+		return notifier
+	}
+
+	readProfile := func() *seccomp.Seccomp {
+		data, err := os.ReadFile(filepath.Join(recordDir, containerID+".json"))
+		Expect(err).NotTo(HaveOccurred())
+		profile := &seccomp.Seccomp{}
+		Expect(json.Unmarshal(data, profile)).To(Succeed())
+		return profile
+	}
+
+	t.Describe("Setup", func() {
+		It("should keep enforcing the profile for the record action", func() {
+			// Given
+			config := seccompcfg.New()
+			config.SetNotifierPath(listenerDir)
+			config.SetProfileRecordDir(recordDir)
+			generator, err := generate.New("linux")
+			Expect(err).NotTo(HaveOccurred())
+
+			// When
+			sut, _, err = config.Setup(
+				context.Background(),
+				nil,
+				make(chan seccompcfg.Notification),
+				containerID,
+				"name",
+				map[string]string{annotations.SeccompNotifierActionAnnotation: annotations.SeccompNotifierActionRecord},
+				nil,
+				&generator,
+				&types.SecurityProfile{ProfileType: types.SecurityProfile_RuntimeDefault},
+			)
+
+			// Then
+			Expect(err).NotTo(HaveOccurred())
+			Expect(sut).NotTo(BeNil())
+			profile := generator.Config.Linux.Seccomp
+			Expect(profile.DefaultAction).To(Equal(specs.ActErrno))
+			notified := false
+			for _, syscall := range profile.Syscalls {
+				switch syscall.Action {
+				case specs.ActAllow:
+					Expect(syscall.Names).To(Equal([]string{"write"}))
+				case specs.ActNotify:
+					notified = true
+				}
+			}
+			Expect(notified).To(BeTrue())
+		})
+	})
+
+	t.Describe("Record", func() {
+		It("should be false for the stop action", func() {
+			// When
+			sut = newNotifier(annotations.SeccompNotifierActionStop)
+
+			// Then
+			Expect(sut.Record()).To(BeFalse())
+			Expect(sut.StopContainers()).To(BeTrue())
+			Expect(sut.ProfilePath()).To(BeEmpty())
+		})
+
+		It("should be true for the record action", func() {
+			// When
+			sut = newNotifier(annotations.SeccompNotifierActionRecord)
+
+			// Then
+			Expect(sut.Record()).To(BeTrue())
+			Expect(sut.StopContainers()).To(BeFalse())
+			Expect(sut.ProfilePath()).To(Equal(filepath.Join(recordDir, containerID+".json")))
+		})
+	})
+
+	t.Describe("Profile", func() {
+		It("should allow the recorded syscalls", func() {
+			// Given
+			sut = newNotifier(annotations.SeccompNotifierActionRecord)
+			sut.AddSyscall("read")
+			sut.AddSyscall("openat")
+			sut.AddSyscall("read")
+
+			// When
+			profile := sut.Profile()
+
+			// Then
+			Expect(profile.DefaultAction).To(Equal(seccomp.ActErrno))
+			Expect(profile.Syscalls).To(HaveLen(1))
+			Expect(profile.Syscalls[0].Action).To(Equal(seccomp.ActAllow))
+			Expect(profile.Syscalls[0].Names).To(Equal([]string{"openat", "read", "write"}))
+		})
+	})
+
+	t.Describe("Close", func() {
+		It("should write the recorded profile", func() {
+			// Given
+			notifier := newNotifier(annotations.SeccompNotifierActionRecord)
+			notifier.AddSyscall("read")
+
+			// When
+			Expect(notifier.Close()).To(Succeed())
+
+			// Then
+			profile := readProfile()
+			Expect(profile.Syscalls).To(HaveLen(1))
+			Expect(profile.Syscalls[0].Names).To(Equal([]string{"read", "write"}))
+		})
+
+		It("should continue a previous recording", func() {
+			// Given
+			notifier := newNotifier(annotations.SeccompNotifierActionRecord)
+			notifier.AddSyscall("read")
+			Expect(notifier.Close()).To(Succeed())
+
+			// When
+			notifier = newNotifier(annotations.SeccompNotifierActionRecord)
+			notifier.AddSyscall("close")
+			Expect(notifier.Close()).To(Succeed())
+
+			// Then
+			profile := readProfile()
+			Expect(profile.Syscalls[0].Names).To(Equal([]string{"close", "read", "write"}))
+		})
+
+		It("should not write a profile for the stop action", func() {
+			// Given
+			notifier := newNotifier(annotations.SeccompNotifierActionStop)
+			notifier.AddSyscall("read")
+
+			// When
+			Expect(notifier.Close()).To(Succeed())
+
+			// Then
+			entries, err := os.ReadDir(recordDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(entries).To(BeEmpty())
+		})
+	})
+})
@@ -79,9 +79,10 @@ func removeStringFromSlice(s []string, i int) []string {
 
 // Config is the global seccomp configuration type
 type Config struct {
-	enabled      bool
-	profile      *seccomp.Seccomp
-	notifierPath string
+	enabled          bool
+	profile          *seccomp.Seccomp
+	notifierPath     string
+	profileRecordDir string
 }
 
 // New creates a new default seccomp configuration instance
@@ -103,6 +104,18 @@ func (c *Config) NotifierPath() string {
 	return c.notifierPath
 }
 
+// SetProfileRecordDir sets the directory for the profiles recorded by the
+// seccomp notifier.
+func (c *Config) SetProfileRecordDir(dir string) {
+	c.profileRecordDir = dir
+}
+
+// ProfileRecordDir returns the directory for the profiles recorded by the
+// seccomp notifier.
+func (c *Config) ProfileRecordDir() string {
+	return c.profileRecordDir
+}
+
 // LoadProfile can be used to load a seccomp profile from the provided path.
 // This method will not fail if seccomp is disabled.
 func (c *Config) LoadProfile(profilePath string) error {
@@ -54,6 +54,17 @@ func (c *Config) NotifierPath() string {
 	return ""
 }
 
+// SetProfileRecordDir sets the directory for the profiles recorded by the
+// seccomp notifier.
+func (c *Config) SetProfileRecordDir(dir string) {
+}
+
+// ProfileRecordDir returns the directory for the profiles recorded by the
+// seccomp notifier.
+func (c *Config) ProfileRecordDir() string {
+	return ""
+}
+
 // LoadProfile can be used to load a seccomp profile from the provided path.
 // This method will not fail if seccomp is disabled.
 func (c *Config) LoadProfile(profilePath string) error {
@@ -69,7 +80,7 @@ func (c *Config) LoadDefaultProfile() error {
 func NewNotifier(
 	ctx context.Context,
 	msgChan chan Notification,
-	containerID, listenerPath string,
+	containerID, listenerPath, profileRecordDir string,
 	annotationMap map[string]string,
 ) (*Notifier, error) {
 	return nil, nil
@@ -91,6 +102,22 @@ func (*Notifier) StopContainers() bool {
 	return false
 }
 
+func (*Notifier) Record() bool {
+	return false
+}
+
+func (*Notifier) ProfilePath() string {
+	return ""
+}
+
+func (*Notifier) Profile() *seccomp.Seccomp {
+	return nil
+}
+
+func (*Notifier) WriteProfile() error {
+	return nil
+}
+
 func (*Notifier) OnExpired(callback func()) {
 }
 
@@ -116,6 +116,9 @@ func mergeConfig(config *libconfig.Config, ctx *cli.Context) error {
 	if ctx.IsSet("default-runtime") {
 		config.DefaultRuntime = ctx.String("default-runtime")
 	}
+	if ctx.IsSet("runtime-health-check-interval") {
+		config.RuntimeHealthCheckInterval = ctx.Duration("runtime-health-check-interval")
+	}
 
 	if ctx.IsSet("decryption-keys-path") {
 		config.DecryptionKeysPath = ctx.String("decryption-keys-path")
@@ -175,6 +178,9 @@ func mergeConfig(config *libconfig.Config, ctx *cli.Context) error {
 	if ctx.IsSet("seccomp-profile") {
 		config.SeccompProfile = ctx.String("seccomp-profile")
 	}
+	if ctx.IsSet("seccomp-profile-record-dir") {
+		config.SeccompProfileRecordDir = ctx.String("seccomp-profile-record-dir")
+	}
 	if ctx.IsSet("apparmor-profile") {
 		config.ApparmorProfile = ctx.String("apparmor-profile")
 	}
@@ -325,6 +331,18 @@ func mergeConfig(config *libconfig.Config, ctx *cli.Context) error {
 	if ctx.IsSet("stream-idle-timeout") {
 		config.StreamIdleTimeout = ctx.String("stream-idle-timeout")
 	}
+	if ctx.IsSet("audit-log-path") {
+		config.AuditLogPath = ctx.String("audit-log-path")
+	}
+	if ctx.IsSet("audit-log-max-size") {
+		config.AuditLogMaxSize = ctx.Int64("audit-log-max-size")
+	}
+	if ctx.IsSet("audit-log-max-backups") {
+		config.AuditLogMaxBackups = ctx.Int("audit-log-max-backups")
+	}
+	if ctx.IsSet("audit-log-policy") {
+		config.AuditLogPolicy = StringSliceTrySplit(ctx, "audit-log-policy")
+	}
 	if ctx.IsSet("version-file") {
 		config.VersionFile = ctx.String("version-file")
 	}
@@ -397,9 +415,30 @@ func mergeConfig(config *libconfig.Config, ctx *cli.Context) error {
 	if ctx.IsSet("nri-plugin-request-timeout") {
 		config.NRI.PluginRequestTimeout = ctx.Duration("nri-plugin-request-timeout")
 	}
+	if ctx.IsSet("nri-eviction-grace-period") {
+		config.NRI.EvictionGracePeriod = ctx.Duration("nri-eviction-grace-period")
+	}
 	if ctx.IsSet("big-files-temporary-dir") {
 		config.BigFilesTemporaryDir = ctx.String("big-files-temporary-dir")
 	}
+	if ctx.IsSet("max-concurrent-image-pulls") {
+		config.MaxConcurrentImagePulls = ctx.Int("max-concurrent-image-pulls")
+	}
+	if ctx.IsSet("max-concurrent-image-pulls-per-registry") {
+		config.MaxConcurrentImagePullsPerRegistry = ctx.Int("max-concurrent-image-pulls-per-registry")
+	}
+	if ctx.IsSet("image-gc-interval") {
+		config.ImageGCInterval = ctx.Duration("image-gc-interval")
+	}
+	if ctx.IsSet("image-gc-max-age") {
+		config.ImageGCMaxAge = ctx.Duration("image-gc-max-age")
+	}
+	if ctx.IsSet("image-gc-keep-per-repository") {
+		config.ImageGCKeepPerRepository = ctx.Int("image-gc-keep-per-repository")
+	}
+	if ctx.IsSet("image-gc-max-store-size") {
+		config.ImageGCMaxStoreSize = ctx.Int64("image-gc-max-store-size")
+	}
 	if ctx.IsSet("separate-pull-cgroup") {
 		config.SeparatePullCgroup = ctx.String("separate-pull-cgroup")
 	}
@@ -421,9 +460,15 @@ func mergeConfig(config *libconfig.Config, ctx *cli.Context) error {
 	if ctx.IsSet("pinned-images") {
 		config.PinnedImages = StringSliceTrySplit(ctx, "pinned-images")
 	}
+	if ctx.IsSet("pre-pull-images") {
+		config.PrePullImages = StringSliceTrySplit(ctx, "pre-pull-images")
+	}
 	if ctx.IsSet("disable-hostport-mapping") {
 		config.DisableHostPortMapping = ctx.Bool("disable-hostport-mapping")
 	}
+	if ctx.IsSet("hostport-mapping-backend") {
+		config.HostPortMappingBackend = libconfig.HostPortMappingBackendType(ctx.String("hostport-mapping-backend"))
+	}
 	if ctx.IsSet("timezone") {
 		config.Timezone = ctx.String("timezone")
 	}
@@ -648,6 +693,12 @@ func getCrioFlags(defConf *libconfig.Config) []cli.Flag {
 			Value:   defConf.DefaultRuntime,
 			EnvVars: []string{"CONTAINER_DEFAULT_RUNTIME"},
 		},
+		&cli.DurationFlag{
+			Name:    "runtime-health-check-interval",
+			Usage:   "Interval in which CRI-O probes the configured runtime handlers for their binary, version and supported features. Pod sandboxes of unhealthy runtime handlers fail to be created. 0 disables the runtime handler health checks.",
+			EnvVars: []string{"CONTAINER_RUNTIME_HEALTH_CHECK_INTERVAL"},
+			Value:   defConf.RuntimeHealthCheckInterval,
+		},
 		&cli.StringSliceFlag{
 			Name:    "runtimes",
 			Usage:   "OCI runtimes, format is 'runtime_name:runtime_path:runtime_root:runtime_type:privileged_without_host_devices:runtime_config_path:container_min_memory'.",
@@ -659,6 +710,13 @@ func getCrioFlags(defConf *libconfig.Config) []cli.Flag {
 			EnvVars:   []string{"CONTAINER_SECCOMP_PROFILE"},
 			TakesFile: true,
 		},
+		&cli.StringFlag{
+			Name:      "seccomp-profile-record-dir",
+			Usage:     "Directory where the seccomp profiles recorded by the seccomp notifier 'record' action are written to.",
+			Value:     defConf.SeccompProfileRecordDir,
+			EnvVars:   []string{"CONTAINER_SECCOMP_PROFILE_RECORD_DIR"},
+			TakesFile: true,
+		},
 		&cli.StringFlag{
 			Name:    "apparmor-profile",
 			Usage:   "Name of the apparmor profile to be used as the runtime's default. This only takes effect if the user does not specify a profile via the Kubernetes Pod's metadata annotation.",
@@ -915,12 +973,53 @@ func getCrioFlags(defConf *libconfig.Config) []cli.Flag {
 			Usage: `Timeout for a plugin to handle an NRI request.`,
 			Value: defConf.NRI.PluginRequestTimeout,
 		},
+		&cli.DurationFlag{
+			Name:  "nri-eviction-grace-period",
+			Usage: `Grace period for containers evicted by an NRI plugin to stop before getting killed.`,
+			Value: defConf.NRI.EvictionGracePeriod,
+		},
 		&cli.StringFlag{
 			Name:    "big-files-temporary-dir",
 			Usage:   `Path to the temporary directory to use for storing big files, used to store image blobs and data streams related to containers image management.`,
 			EnvVars: []string{"CONTAINER_BIG_FILES_TEMPORARY_DIR"},
 			Value:   defConf.BigFilesTemporaryDir,
 		},
+		&cli.IntFlag{
+			Name:    "max-concurrent-image-pulls",
+			Usage:   "Maximum number of image pulls running at the same time. Further pulls are queued. 0 means unlimited.",
+			EnvVars: []string{"CONTAINER_MAX_CONCURRENT_IMAGE_PULLS"},
+			Value:   defConf.MaxConcurrentImagePulls,
+		},
+		&cli.IntFlag{
+			Name:    "max-concurrent-image-pulls-per-registry",
+			Usage:   "Maximum number of image pulls from a single registry running at the same time. Further pulls are queued. 0 means unlimited.",
+			EnvVars: []string{"CONTAINER_MAX_CONCURRENT_IMAGE_PULLS_PER_REGISTRY"},
+			Value:   defConf.MaxConcurrentImagePullsPerRegistry,
+		},
+		&cli.DurationFlag{
+			Name:    "image-gc-interval",
+			Usage:   "Interval in which CRI-O garbage collects unused images. Pinned images and images in use by containers are never removed. 0 disables the image garbage collection.",
+			EnvVars: []string{"CONTAINER_IMAGE_GC_INTERVAL"},
+			Value:   defConf.ImageGCInterval,
+		},
+		&cli.DurationFlag{
+			Name:    "image-gc-max-age",
+			Usage:   "Remove images which have not been used by any container for this duration. 0 means no age limit.",
+			EnvVars: []string{"CONTAINER_IMAGE_GC_MAX_AGE"},
+			Value:   defConf.ImageGCMaxAge,
+		},
+		&cli.IntFlag{
+			Name:    "image-gc-keep-per-repository",
+			Usage:   "Only keep this amount of the most recently used images per repository. 0 means no limit.",
+			EnvVars: []string{"CONTAINER_IMAGE_GC_KEEP_PER_REPOSITORY"},
+			Value:   defConf.ImageGCKeepPerRepository,
+		},
+		&cli.Int64Flag{
+			Name:    "image-gc-max-store-size",
+			Usage:   "Remove the least recently used images until all images take less than this amount of bytes. 0 means no limit.",
+			EnvVars: []string{"CONTAINER_IMAGE_GC_MAX_STORE_SIZE"},
+			Value:   defConf.ImageGCMaxStoreSize,
+		},
 		&cli.BoolFlag{
 			Name:    "read-only",
 			Usage:   "Setup all unprivileged containers to run as read-only. Automatically mounts the containers' tmpfs on '/run', '/tmp' and '/var/tmp'.",
@@ -1077,6 +1176,31 @@ func getCrioFlags(defConf *libconfig.Config) []cli.Flag {
 			EnvVars: []string{"STREAM_IDLE_TIMEOUT"},
 			Value:   defConf.StreamIdleTimeout,
 		},
+		&cli.StringFlag{
+			Name:      "audit-log-path",
+			Usage:     "Path to the audit log, which records the mutating and interactive CRI calls including the credentials of the caller. An empty path disables the audit log.",
+			EnvVars:   []string{"CONTAINER_AUDIT_LOG_PATH"},
+			Value:     defConf.AuditLogPath,
+			TakesFile: true,
+		},
+		&cli.Int64Flag{
+			Name:    "audit-log-max-size",
+			Usage:   "Size in bytes after which the audit log gets rotated. If set to 0, then the audit log does not get rotated.",
+			EnvVars: []string{"CONTAINER_AUDIT_LOG_MAX_SIZE"},
+			Value:   defConf.AuditLogMaxSize,
+		},
+		&cli.IntFlag{
+			Name:    "audit-log-max-backups",
+			Usage:   "Number of rotated audit logs to keep.",
+			EnvVars: []string{"CONTAINER_AUDIT_LOG_MAX_BACKUPS"},
+			Value:   defConf.AuditLogMaxBackups,
+		},
+		&cli.StringSliceFlag{
+			Name:    "audit-log-policy",
+			Usage:   "Audit level per CRI method in the format 'Method=level'. Supported levels are 'none', 'metadata' and 'request'.",
+			EnvVars: []string{"CONTAINER_AUDIT_LOG_POLICY"},
+			Value:   cli.NewStringSlice(defConf.AuditLogPolicy...),
+		},
 		&cli.StringFlag{
 			Name:        "registries-conf",
 			Usage:       "path to the registries.conf file.",
@@ -1183,12 +1307,24 @@ func getCrioFlags(defConf *libconfig.Config) []cli.Flag {
 			EnvVars: []string{"CONTAINER_PINNED_IMAGES"},
 			Value:   cli.NewStringSlice(defConf.PinnedImages...),
 		},
+		&cli.StringSliceFlag{
+			Name:    "pre-pull-images",
+			Usage:   "A list of images which are pulled in the background on startup and on config reload.",
+			EnvVars: []string{"CONTAINER_PRE_PULL_IMAGES"},
+			Value:   cli.NewStringSlice(defConf.PrePullImages...),
+		},
 		&cli.BoolFlag{
 			Name:    "disable-hostport-mapping",
 			Usage:   "If true, CRI-O would disable the hostport mapping.",
 			EnvVars: []string{"DISABLE_HOSTPORT_MAPPING"},
 			Value:   defConf.DisableHostPortMapping,
 		},
+		&cli.StringFlag{
+			Name:    "hostport-mapping-backend",
+			Usage:   "The backend used to program the container hostport mappings. Supported values: 'iptables', 'nftables'.",
+			EnvVars: []string{"CONTAINER_HOSTPORT_MAPPING_BACKEND"},
+			Value:   string(defConf.HostPortMappingBackend),
+		},
 		&cli.StringFlag{
 			Name:    "timezone",
 			Aliases: []string{"tz"},
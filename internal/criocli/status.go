@@ -1,10 +1,17 @@
 package criocli
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"slices"
 	"strings"
+	"text/tabwriter"
+	"time"
 
 	"github.com/cri-o/cri-o/internal/client"
+	"github.com/cri-o/cri-o/pkg/types"
+	json "github.com/json-iterator/go"
 
 	"github.com/urfave/cli/v2"
 )
@@ -13,8 +20,47 @@ const (
 	defaultSocket = "/var/run/crio/crio.sock"
 	idArg         = "id"
 	socketArg     = "socket"
+	checkArg      = "check"
+	statsArg      = "stats"
+
+	namespaceArg      = "namespace"
+	labelArg          = "label"
+	stateArg          = "state"
+	runtimeHandlerArg = "runtime-handler"
+	outputArg         = "output"
+
+	outputTable = "table"
+	outputJSON  = "json"
 )
 
+// listFlags are the flags of the subcommands listing pods and containers.
+var listFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:    namespaceArg,
+		Aliases: []string{"n"},
+		Usage:   "filter by the namespace of the pod",
+	},
+	&cli.StringSliceFlag{
+		Name:    labelArg,
+		Aliases: []string{"l"},
+		Usage:   "filter by a label, specified as 'key=value', can be used multiple times",
+	},
+	&cli.StringFlag{
+		Name:  stateArg,
+		Usage: "filter by the state, for example 'ready' or 'notready' for pods and 'running' or 'exited' for containers",
+	},
+	&cli.StringFlag{
+		Name:  runtimeHandlerArg,
+		Usage: "filter by the runtime handler",
+	},
+	&cli.StringFlag{
+		Name:    outputArg,
+		Aliases: []string{"o"},
+		Usage:   "output format of the list, one of: table, json",
+		Value:   outputTable,
+	},
+}
+
 var StatusCommand = &cli.Command{
 	Name:  "status",
 	Usage: "Display status information",
@@ -36,18 +82,45 @@ var StatusCommand = &cli.Command{
 	}, {
 		Action:  containers,
 		Aliases: []string{"container", "cs", "s"},
-		Flags: []cli.Flag{&cli.StringFlag{
+		Flags: append([]cli.Flag{&cli.StringFlag{
 			Name:    idArg,
 			Aliases: []string{"i"},
 			Usage:   "the container ID",
-		}},
+		}, &cli.BoolFlag{
+			Name:  statsArg,
+			Usage: "display the block I/O, pressure and hugetlb statistics of the container",
+		}}, listFlags...),
 		Name:  "containers",
-		Usage: "Display detailed information about the provided container ID.",
+		Usage: "Display detailed information about the provided container ID or list all containers.",
 	}, {
 		Action:  info,
 		Aliases: []string{"i"},
 		Name:    "info",
 		Usage:   "Retrieve generic information about CRI-O, such as the cgroup and storage driver.",
+	}, {
+		Action:  pods,
+		Aliases: []string{"pod"},
+		Flags:   listFlags,
+		Name:    "pods",
+		Usage:   "List the pod sandboxes, including their cgroup parent, PID, namespace paths, IPs and runtime handler.",
+	}, {
+		Action:  pulls,
+		Aliases: []string{"pull", "p"},
+		Name:    "pulls",
+		Usage:   "Display the progress of the in-flight image pulls.",
+	}, {
+		Action:  prePull,
+		Aliases: []string{"pre-pull"},
+		Flags: []cli.Flag{&cli.BoolFlag{
+			Name:  checkArg,
+			Usage: "exit with an error if not all images are present",
+		}},
+		Name:  "prepull",
+		Usage: "Display the state of the images pulled in the background, configured via 'pre_pull_images'.",
+	}, {
+		Action: reload,
+		Name:   "reload",
+		Usage:  "Display the result of the last configuration reload, including the changed options.",
 	}},
 }
 
@@ -74,7 +147,7 @@ func containers(c *cli.Context) error {
 
 	id := c.String(idArg)
 	if id == "" {
-		return fmt.Errorf("the argument --%s cannot be empty", idArg)
+		return listContainers(c, crioClient)
 	}
 
 	info, err := crioClient.ContainerInfo(c.String(idArg))
@@ -104,6 +177,151 @@ func containers(c *cli.Context) error {
 	fmt.Printf("sandbox: %s\n", info.Sandbox)
 	fmt.Printf("ips: %s\n", strings.Join(info.IPs, ", "))
 
+	if !c.Bool(statsArg) {
+		return nil
+	}
+	stats, err := crioClient.ContainerStats(id)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("blkio:\n")
+	for _, blkio := range stats.Blkio {
+		fmt.Printf("  %d:%d: read %d bytes (%d ops), write %d bytes (%d ops)\n",
+			blkio.Major, blkio.Minor, blkio.ReadBytes, blkio.ReadOps, blkio.WriteBytes, blkio.WriteOps)
+	}
+	if stats.Pressure != nil {
+		fmt.Printf("pressure:\n")
+		printPSI("cpu", stats.Pressure.CPU)
+		printPSI("memory", stats.Pressure.Memory)
+		printPSI("io", stats.Pressure.IO)
+	}
+	fmt.Printf("hugetlb:\n")
+	for pageSize, hugetlb := range stats.Hugetlb {
+		fmt.Printf("  %s: usage %d, max usage %d, failures %d\n",
+			pageSize, hugetlb.Usage, hugetlb.MaxUsage, hugetlb.Failcnt)
+	}
+
+	return nil
+}
+
+func listContainers(c *cli.Context, crioClient client.CrioClient) error {
+	filter, err := listFilter(c)
+	if err != nil {
+		return err
+	}
+
+	containers, err := crioClient.ContainersInfo(filter)
+	if err != nil {
+		return err
+	}
+
+	if c.String(outputArg) == outputJSON {
+		return printJSON(containers)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tPOD\tNAMESPACE\tSTATE\tRUNTIME HANDLER\tPID\tIPS\tCGROUP PATH\tNAMESPACE PATHS\tMOUNTS")
+	for _, ctr := range containers {
+		mounts := []string{}
+		for _, mount := range ctr.Mounts {
+			m := mount.HostPath + ":" + mount.ContainerPath
+			if mount.Readonly {
+				m += ":ro"
+			}
+			mounts = append(mounts, m)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%d\t%s\t%s\t%s\t%s\n",
+			ctr.ID, ctr.Name, ctr.PodName, ctr.PodNamespace, ctr.State, ctr.RuntimeHandler, ctr.Pid,
+			strings.Join(ctr.IPs, ","), ctr.CgroupPath, formatNamespacePaths(ctr.NamespacePaths), strings.Join(mounts, ","))
+	}
+	return w.Flush()
+}
+
+func printPSI(resource string, psi *types.PSIInfo) {
+	if psi == nil {
+		return
+	}
+	fmt.Printf("  %s:\n", resource)
+	for _, data := range []struct {
+		name string
+		data types.PSIDataInfo
+	}{{"some", psi.Some}, {"full", psi.Full}} {
+		fmt.Printf("    %s: avg10=%.2f avg60=%.2f avg300=%.2f total=%d\n",
+			data.name, data.data.Avg10, data.data.Avg60, data.data.Avg300, data.data.Total)
+	}
+}
+
+func pods(c *cli.Context) error {
+	crioClient, err := crioClient(c)
+	if err != nil {
+		return err
+	}
+
+	filter, err := listFilter(c)
+	if err != nil {
+		return err
+	}
+
+	pods, err := crioClient.PodsInfo(filter)
+	if err != nil {
+		return err
+	}
+
+	if c.String(outputArg) == outputJSON {
+		return printJSON(pods)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tNAMESPACE\tSTATE\tRUNTIME HANDLER\tPID\tIPS\tCGROUP PARENT\tNAMESPACE PATHS\tCONTAINERS")
+	for _, pod := range pods {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d\t%s\t%s\t%s\t%d\n",
+			pod.ID, pod.Name, pod.Namespace, pod.State, pod.RuntimeHandler, pod.Pid,
+			strings.Join(pod.IPs, ","), pod.CgroupParent, formatNamespacePaths(pod.NamespacePaths), len(pod.Containers))
+	}
+	return w.Flush()
+}
+
+// listFilter returns the filter and validates the output format of the
+// subcommands listing pods and containers.
+func listFilter(c *cli.Context) (*types.ListFilter, error) {
+	switch output := c.String(outputArg); output {
+	case outputTable, outputJSON:
+	default:
+		return nil, fmt.Errorf("unsupported output format %q, expected %s or %s", output, outputTable, outputJSON)
+	}
+
+	filter := &types.ListFilter{
+		Namespace:      c.String(namespaceArg),
+		Labels:         map[string]string{},
+		State:          c.String(stateArg),
+		RuntimeHandler: c.String(runtimeHandlerArg),
+	}
+	for _, label := range c.StringSlice(labelArg) {
+		key, value, ok := strings.Cut(label, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid label %q, expected key=value", label)
+		}
+		filter.Labels[key] = value
+	}
+	return filter, nil
+}
+
+// formatNamespacePaths returns the namespace paths sorted by their names.
+func formatNamespacePaths(paths map[string]string) string {
+	res := make([]string, 0, len(paths))
+	for name, path := range paths {
+		res = append(res, name+"="+path)
+	}
+	slices.Sort(res)
+	return strings.Join(res, ",")
+}
+
+func printJSON(value any) error {
+	b, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(b))
 	return nil
 }
 
@@ -135,6 +353,102 @@ func info(c *cli.Context) error {
 	return nil
 }
 
+func pulls(c *cli.Context) error {
+	crioClient, err := crioClient(c)
+	if err != nil {
+		return err
+	}
+
+	pulls, err := crioClient.PullsInfo()
+	if err != nil {
+		return err
+	}
+
+	if len(pulls) == 0 {
+		fmt.Printf("no image pulls in progress\n")
+		return nil
+	}
+
+	for _, pull := range pulls {
+		fmt.Printf("image: %s\n", pull.Image)
+		fmt.Printf("started: %v\n", time.Unix(0, pull.StartTime))
+		fmt.Printf("last progress: %v\n", time.Unix(0, pull.LastProgressTime))
+		fmt.Printf("layers:\n")
+		for _, layer := range pull.Layers {
+			state := "pulling"
+			if layer.Done {
+				state = "done"
+			}
+			if layer.Size > 0 {
+				fmt.Printf("  %s: %d/%d bytes (%s)\n", layer.Digest, layer.Offset, layer.Size, state)
+			} else {
+				fmt.Printf("  %s: %d bytes (%s)\n", layer.Digest, layer.Offset, state)
+			}
+		}
+	}
+
+	return nil
+}
+
+func prePull(c *cli.Context) error {
+	crioClient, err := crioClient(c)
+	if err != nil {
+		return err
+	}
+
+	info, err := crioClient.PrePullInfo()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("ready: %v\n", info.Ready)
+	fmt.Printf("images:\n")
+	for _, image := range info.Images {
+		fmt.Printf("  %s: %s (attempts: %d)\n", image.Image, image.State, image.Attempts)
+		if image.LastError != "" {
+			fmt.Printf("    last error: %s\n", image.LastError)
+		}
+	}
+
+	if c.Bool(checkArg) && !info.Ready {
+		return errors.New("not all images are present")
+	}
+	return nil
+}
+
+func reload(c *cli.Context) error {
+	crioClient, err := crioClient(c)
+	if err != nil {
+		return err
+	}
+
+	info, err := crioClient.ReloadInfo()
+	if err != nil {
+		return err
+	}
+
+	if info.Time == 0 {
+		fmt.Printf("configuration not reloaded yet\n")
+		return nil
+	}
+
+	fmt.Printf("time: %v\n", time.Unix(0, info.Time))
+	fmt.Printf("success: %v\n", info.Success)
+	if info.Error != "" {
+		fmt.Printf("error: %s\n", info.Error)
+	}
+	fmt.Printf("changes:\n")
+	for _, change := range info.Changes {
+		fmt.Printf("  %s: %s -> %s\n", change.Option, change.OldValue, change.NewValue)
+	}
+	fmt.Printf("restart required:\n")
+	for _, option := range info.RestartRequired {
+		fmt.Printf("  %s\n", option)
+	}
+
+	return nil
+}
+
 func crioClient(c *cli.Context) (client.CrioClient, error) {
 	return client.New(c.String(socketArg))
 }
@@ -0,0 +1,162 @@
+package hostport
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type fakeNftTable struct {
+	chains map[string][]string
+	sets   map[string]map[string]string
+}
+
+// fakeNftables is an in-memory nftables implementation, which understands the
+// subset of the nft syntax used by the nftHostportManager.
+type fakeNftables struct {
+	tables map[string]*fakeNftTable
+}
+
+func newFakeNftables() *fakeNftables {
+	return &fakeNftables{tables: make(map[string]*fakeNftTable)}
+}
+
+// Run applies the script transactionally: if any command fails, the
+// state before the run will be restored.
+func (f *fakeNftables) Run(script []byte) error {
+	backup := f.copyTables()
+	scanner := bufio.NewScanner(bytes.NewReader(script))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if err := f.runLine(line); err != nil {
+			f.tables = backup
+			return fmt.Errorf("%q: %w", line, err)
+		}
+	}
+	return scanner.Err()
+}
+
+func (f *fakeNftables) runLine(line string) error {
+	words := strings.Fields(line)
+	if len(words) < 4 {
+		return errors.New("unexpected command")
+	}
+	op, kind, tableName := words[0], words[1], words[2]+" "+words[3]
+
+	if op == "add" && kind == "table" {
+		if _, ok := f.tables[tableName]; !ok {
+			f.tables[tableName] = &fakeNftTable{
+				chains: make(map[string][]string),
+				sets:   make(map[string]map[string]string),
+			}
+		}
+		return nil
+	}
+
+	table, ok := f.tables[tableName]
+	if !ok {
+		return fmt.Errorf("table %s does not exist", tableName)
+	}
+	if len(words) < 5 {
+		return errors.New("unexpected command")
+	}
+	name := words[4]
+
+	switch op + " " + kind {
+	case "add chain":
+		if _, ok := table.chains[name]; !ok {
+			table.chains[name] = []string{}
+		}
+	case "flush chain":
+		if _, ok := table.chains[name]; !ok {
+			return fmt.Errorf("chain %s does not exist", name)
+		}
+		table.chains[name] = []string{}
+	case "add rule":
+		if _, ok := table.chains[name]; !ok {
+			return fmt.Errorf("chain %s does not exist", name)
+		}
+		table.chains[name] = append(table.chains[name], strings.Join(words[5:], " "))
+	case "add map", "add set":
+		if _, ok := table.sets[name]; !ok {
+			table.sets[name] = make(map[string]string)
+		}
+	case "add element", "delete element":
+		set, ok := table.sets[name]
+		if !ok {
+			return fmt.Errorf("set %s does not exist", name)
+		}
+		for _, element := range parseFakeNftElements(words[5:]) {
+			key, value, _ := strings.Cut(element, " : ")
+			existing, exists := set[key]
+			switch {
+			case op == "delete":
+				if !exists {
+					return fmt.Errorf("element %s does not exist in set %s", key, name)
+				}
+				delete(set, key)
+			case !exists:
+				set[key] = value
+			case existing != value:
+				// like the kernel, adding an existing element only
+				// succeeds with the same value
+				return fmt.Errorf("element %s already exists in set %s", key, name)
+			}
+		}
+	default:
+		return errors.New("unsupported command")
+	}
+	return nil
+}
+
+// parseFakeNftElements parses the words of a "{ e1, e2 }" element list.
+func parseFakeNftElements(words []string) []string {
+	list := strings.TrimSpace(strings.Join(words, " "))
+	list = strings.TrimSuffix(strings.TrimPrefix(list, "{"), "}")
+	elements := []string{}
+	for _, element := range strings.Split(list, ",") {
+		if element = strings.TrimSpace(element); element != "" {
+			elements = append(elements, element)
+		}
+	}
+	return elements
+}
+
+func (f *fakeNftables) copyTables() map[string]*fakeNftTable {
+	tables := make(map[string]*fakeNftTable, len(f.tables))
+	for name, table := range f.tables {
+		c := &fakeNftTable{
+			chains: make(map[string][]string, len(table.chains)),
+			sets:   make(map[string]map[string]string, len(table.sets)),
+		}
+		for chain, rules := range table.chains {
+			c.chains[chain] = append([]string{}, rules...)
+		}
+		for set, elements := range table.sets {
+			c.sets[set] = make(map[string]string, len(elements))
+			for k, v := range elements {
+				c.sets[set][k] = v
+			}
+		}
+		tables[name] = c
+	}
+	return tables
+}
+
+// getSet returns the elements of the set (or map) in the provided table.
+func (f *fakeNftables) getSet(tableName, setName string) (map[string]string, error) {
+	table, ok := f.tables[tableName]
+	if !ok {
+		return nil, fmt.Errorf("table %s does not exist", tableName)
+	}
+	set, ok := table.sets[setName]
+	if !ok {
+		return nil, fmt.Errorf("set %s does not exist in table %s", setName, tableName)
+	}
+	return set, nil
+}
@@ -131,6 +131,30 @@ func portMappingToHostport(portMapping *PortMapping, family ipFamily) hostport {
 	}
 }
 
+// udpHostports returns the host ports of all UDP port mappings.
+func udpHostports(hostportMappings []*PortMapping) []int {
+	ports := []int{}
+	for _, pm := range hostportMappings {
+		if pm.Protocol == v1.ProtocolUDP {
+			ports = append(ports, int(pm.HostPort))
+		}
+	}
+	return ports
+}
+
+// deleteUDPConntrackEntries removes the UDP conntrack entries for the given
+// destination ports, logging any failure.
+func deleteUDPConntrackEntries(ports []int, isIPv6 bool) {
+	logrus.Infof("Starting to delete udp conntrack entries: %v, isIPv6 - %v", ports, isIPv6)
+	// https://www.iana.org/assignments/protocol-numbers/protocol-numbers.xhtml
+	const protocolUDPNumber = 17
+	for _, port := range ports {
+		if err := deleteConntrackEntriesForDstPort(uint16(port), protocolUDPNumber, getNetlinkFamily(isIPv6)); err != nil {
+			logrus.Errorf("Failed to clear udp conntrack for port %d, error: %v", port, err)
+		}
+	}
+}
+
 // ensureKubeHostportChains ensures the KUBE-HOSTPORTS chain is setup correctly
 func ensureKubeHostportChains(iptables utiliptables.Interface, natInterfaceName string) error {
 	logrus.Info("Ensuring kubelet hostport chains")
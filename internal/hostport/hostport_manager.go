@@ -49,18 +49,20 @@ type HostPortManager interface {
 }
 
 type hostportManager struct {
-	hostPortMap map[hostport]closeable
-	iptables    utiliptables.Interface
-	portOpener  hostportOpener
-	mu          sync.Mutex
+	hostPortMap     map[hostport]closeable
+	podPortMappings map[string]*desiredPodPortMapping
+	iptables        utiliptables.Interface
+	portOpener      hostportOpener
+	mu              sync.Mutex
 }
 
 // NewHostportManager creates a new HostPortManager
 func NewHostportManager(iptables utiliptables.Interface) HostPortManager {
 	h := &hostportManager{
-		hostPortMap: make(map[hostport]closeable),
-		iptables:    iptables,
-		portOpener:  openLocalPort,
+		hostPortMap:     make(map[hostport]closeable),
+		podPortMappings: make(map[string]*desiredPodPortMapping),
+		iptables:        iptables,
+		portOpener:      openLocalPort,
 	}
 
 	return h
@@ -105,85 +107,7 @@ func (hm *hostportManager) Add(id string, podPortMapping *PodPortMapping, natInt
 		hm.hostPortMap[hostport] = socket
 	}
 
-	natChains := bytes.NewBuffer(nil)
-	natRules := bytes.NewBuffer(nil)
-	writeLine(natChains, "*nat")
-
-	existingChains, existingRules, err := getExistingHostportIPTablesRules(hm.iptables)
-	if err != nil {
-		// clean up opened host port if encounter any error
-		return utilerrors.NewAggregate([]error{err, hm.closeHostports(hostportMappings)})
-	}
-
-	newChains := []utiliptables.Chain{}
-	conntrackPortsToRemove := []int{}
-	for _, pm := range hostportMappings {
-		protocol := strings.ToLower(string(pm.Protocol))
-		hpChain := getHostportChain(kubeHostportChainPrefix, id, pm)
-		masqChain := getHostportChain(crioMasqueradeChainPrefix, id, pm)
-		newChains = append(newChains, hpChain, masqChain)
-		if pm.Protocol == v1.ProtocolUDP {
-			conntrackPortsToRemove = append(conntrackPortsToRemove, int(pm.HostPort))
-		}
-
-		// Add new hostport chain
-		writeLine(natChains, utiliptables.MakeChainLine(hpChain))
-		writeLine(natChains, utiliptables.MakeChainLine(masqChain))
-
-		// Prepend the new chains to KUBE-HOSTPORTS and CRIO-HOSTPORTS-MASQ
-		// This avoids any leaking iptables rules that take up the same port
-		writeLine(natRules, "-I", string(kubeHostportsChain),
-			"-m", "comment", "--comment", fmt.Sprintf(`"%s hostport %d"`, podFullName, pm.HostPort),
-			"-m", protocol, "-p", protocol, "--dport", strconv.Itoa(int(pm.HostPort)),
-			"-j", string(hpChain),
-		)
-		writeLine(natRules, "-I", string(crioMasqueradeChain),
-			"-m", "comment", "--comment", fmt.Sprintf(`"%s hostport %d"`, podFullName, pm.HostPort),
-			"-j", string(masqChain),
-		)
-
-		// DNAT to the podIP:containerPort
-		hostPortBinding := net.JoinHostPort(podIP, strconv.Itoa(int(pm.ContainerPort)))
-		if pm.HostIP == "" || pm.HostIP == "0.0.0.0" || pm.HostIP == "::" {
-			writeLine(natRules, "-A", string(hpChain),
-				"-m", "comment", "--comment", fmt.Sprintf(`"%s hostport %d"`, podFullName, pm.HostPort),
-				"-m", protocol, "-p", protocol,
-				"-j", "DNAT", "--to-destination="+hostPortBinding)
-		} else {
-			writeLine(natRules, "-A", string(hpChain),
-				"-m", "comment", "--comment", fmt.Sprintf(`"%s hostport %d"`, podFullName, pm.HostPort),
-				"-m", protocol, "-p", protocol, "-d", pm.HostIP,
-				"-j", "DNAT", "--to-destination="+hostPortBinding)
-		}
-
-		// SNAT hairpin traffic. There is no "ctorigaddrtype" so we can't
-		// _exactly_ match only the traffic that was definitely DNATted by our
-		// rule as opposed to someone else's. But if the traffic has been DNATted
-		// and has src=dst=podIP then _someone_ needs to masquerade it, and the
-		// worst case here is just that "-j MASQUERADE" gets called twice.
-		writeLine(natRules, "-A", string(masqChain),
-			"-m", "comment", "--comment", fmt.Sprintf(`"%s hostport %d"`, podFullName, pm.HostPort),
-			"-m", "conntrack", "--ctorigdstport", strconv.Itoa(int(pm.HostPort)),
-			"-m", protocol, "-p", protocol, "--dport", strconv.Itoa(int(pm.ContainerPort)),
-			"-s", podIP, "-d", podIP,
-			"-j", "MASQUERADE")
-	}
-
-	// getHostportChain should be able to provide unique hostport chain name using hash
-	// if there is a chain conflict or multiple Adds have been triggered for a single pod,
-	// filtering should be able to avoid further problem
-	filterChains(existingChains, newChains)
-	existingRules = filterRules(existingRules, newChains)
-
-	for _, chain := range existingChains {
-		writeLine(natChains, chain)
-	}
-	for _, rule := range existingRules {
-		writeLine(natRules, rule)
-	}
-	writeLine(natRules, "COMMIT")
-
-	if err = hm.syncIPTables(append(natChains.Bytes(), natRules.Bytes()...)); err != nil {
+	if err := hm.syncHostportRules(id, podPortMapping, hostportMappings); err != nil {
 		// clean up opened host port if encounter any error
 		return utilerrors.NewAggregate([]error{err, hm.closeHostports(hostportMappings)})
 	}
@@ -192,14 +116,12 @@ func (hm *hostportManager) Add(id string, podPortMapping *PodPortMapping, natInt
 	// the IP tables rule, it can be the case that the packets received by the node after iptables rule removal will
 	// create a new conntrack entry without any DNAT. That will result in blackhole of the traffic even after correct
 	// iptables rules have been added back.
-	logrus.Infof("Starting to delete udp conntrack entries: %v, isIPv6 - %v", conntrackPortsToRemove, isIPv6)
-	// https://www.iana.org/assignments/protocol-numbers/protocol-numbers.xhtml
-	const protocolUDPNumber = 17
-	for _, port := range conntrackPortsToRemove {
-		err = deleteConntrackEntriesForDstPort(uint16(port), protocolUDPNumber, getNetlinkFamily(isIPv6))
-		if err != nil {
-			logrus.Errorf("Failed to clear udp conntrack for port %d, error: %v", port, err)
-		}
+	deleteUDPConntrackEntries(udpHostports(hostportMappings), isIPv6)
+
+	// Remember the mapping to be able to restore the rules if they get removed externally
+	hm.podPortMappings[id] = &desiredPodPortMapping{
+		podPortMapping:   podPortMapping,
+		natInterfaceName: natInterfaceName,
 	}
 	return nil
 }
@@ -218,6 +140,9 @@ func (hm *hostportManager) Remove(id string, podPortMapping *PodPortMapping) (er
 	hm.mu.Lock()
 	defer hm.mu.Unlock()
 
+	// The rules of the pod must not be restored anymore
+	delete(hm.podPortMappings, id)
+
 	var existingChains map[utiliptables.Chain]string
 	var existingRules []string
 	existingChains, existingRules, err = getExistingHostportIPTablesRules(hm.iptables)
@@ -273,6 +198,88 @@ func (hm *hostportManager) Remove(id string, podPortMapping *PodPortMapping) (er
 	return hm.closeHostports(hostportMappings)
 }
 
+// syncHostportRules (re)creates the hostport chains and rules of the pod,
+// while keeping the rules of all other pods untouched.
+func (hm *hostportManager) syncHostportRules(id string, podPortMapping *PodPortMapping, hostportMappings []*PortMapping) error {
+	podFullName := getPodFullName(podPortMapping)
+	podIP := podPortMapping.IP.String()
+
+	natChains := bytes.NewBuffer(nil)
+	natRules := bytes.NewBuffer(nil)
+	writeLine(natChains, "*nat")
+
+	existingChains, existingRules, err := getExistingHostportIPTablesRules(hm.iptables)
+	if err != nil {
+		return err
+	}
+
+	newChains := []utiliptables.Chain{}
+	for _, pm := range hostportMappings {
+		protocol := strings.ToLower(string(pm.Protocol))
+		hpChain := getHostportChain(kubeHostportChainPrefix, id, pm)
+		masqChain := getHostportChain(crioMasqueradeChainPrefix, id, pm)
+		newChains = append(newChains, hpChain, masqChain)
+
+		// Add new hostport chain
+		writeLine(natChains, utiliptables.MakeChainLine(hpChain))
+		writeLine(natChains, utiliptables.MakeChainLine(masqChain))
+
+		// Prepend the new chains to KUBE-HOSTPORTS and CRIO-HOSTPORTS-MASQ
+		// This avoids any leaking iptables rules that take up the same port
+		writeLine(natRules, "-I", string(kubeHostportsChain),
+			"-m", "comment", "--comment", fmt.Sprintf(`"%s hostport %d"`, podFullName, pm.HostPort),
+			"-m", protocol, "-p", protocol, "--dport", strconv.Itoa(int(pm.HostPort)),
+			"-j", string(hpChain),
+		)
+		writeLine(natRules, "-I", string(crioMasqueradeChain),
+			"-m", "comment", "--comment", fmt.Sprintf(`"%s hostport %d"`, podFullName, pm.HostPort),
+			"-j", string(masqChain),
+		)
+
+		// DNAT to the podIP:containerPort
+		hostPortBinding := net.JoinHostPort(podIP, strconv.Itoa(int(pm.ContainerPort)))
+		if pm.HostIP == "" || pm.HostIP == "0.0.0.0" || pm.HostIP == "::" {
+			writeLine(natRules, "-A", string(hpChain),
+				"-m", "comment", "--comment", fmt.Sprintf(`"%s hostport %d"`, podFullName, pm.HostPort),
+				"-m", protocol, "-p", protocol,
+				"-j", "DNAT", "--to-destination="+hostPortBinding)
+		} else {
+			writeLine(natRules, "-A", string(hpChain),
+				"-m", "comment", "--comment", fmt.Sprintf(`"%s hostport %d"`, podFullName, pm.HostPort),
+				"-m", protocol, "-p", protocol, "-d", pm.HostIP,
+				"-j", "DNAT", "--to-destination="+hostPortBinding)
+		}
+
+		// SNAT hairpin traffic. There is no "ctorigaddrtype" so we can't
+		// _exactly_ match only the traffic that was definitely DNATted by our
+		// rule as opposed to someone else's. But if the traffic has been DNATted
+		// and has src=dst=podIP then _someone_ needs to masquerade it, and the
+		// worst case here is just that "-j MASQUERADE" gets called twice.
+		writeLine(natRules, "-A", string(masqChain),
+			"-m", "comment", "--comment", fmt.Sprintf(`"%s hostport %d"`, podFullName, pm.HostPort),
+			"-m", "conntrack", "--ctorigdstport", strconv.Itoa(int(pm.HostPort)),
+			"-m", protocol, "-p", protocol, "--dport", strconv.Itoa(int(pm.ContainerPort)),
+			"-s", podIP, "-d", podIP,
+			"-j", "MASQUERADE")
+	}
+
+	// getHostportChain should be able to provide unique hostport chain name using hash
+	// if there is a chain conflict or multiple Adds have been triggered for a single pod,
+	// filtering should be able to avoid further problem
+	filterChains(existingChains, newChains)
+	existingRules = filterRules(existingRules, newChains)
+
+	for _, chain := range existingChains {
+		writeLine(natChains, chain)
+	}
+	for _, rule := range existingRules {
+		writeLine(natRules, rule)
+	}
+	writeLine(natRules, "COMMIT")
+
+	return hm.syncIPTables(append(natChains.Bytes(), natRules.Bytes()...))
+}
+
 // syncIPTables executes iptables-restore with given lines
 func (hm *hostportManager) syncIPTables(lines []byte) error {
 	logrus.Infof("Restoring iptables rules: %s", lines)
@@ -283,10 +290,20 @@ func (hm *hostportManager) syncIPTables(lines []byte) error {
 	return nil
 }
 
-// openHostports opens all given hostports using the given hostportOpener
+// openHostports opens all given hostports using the hostportOpener of the manager
+func (hm *hostportManager) openHostports(podPortMapping *PodPortMapping) (map[hostport]closeable, error) {
+	return openHostports(hm.portOpener, podPortMapping, hm.getIPFamily())
+}
+
+// closeHostports tries to close all the listed host ports
+func (hm *hostportManager) closeHostports(hostportMappings []*PortMapping) error {
+	return closeHostports(hm.hostPortMap, hostportMappings, hm.getIPFamily())
+}
+
+// openHostports opens all given hostports of the provided IP family using the given hostportOpener
 // If encounter any error, clean up and return the error
 // If all ports are opened successfully, return the hostport and socket mapping
-func (hm *hostportManager) openHostports(podPortMapping *PodPortMapping) (map[hostport]closeable, error) {
+func openHostports(portOpener hostportOpener, podPortMapping *PodPortMapping, family ipFamily) (map[hostport]closeable, error) {
 	var retErr error
 	ports := make(map[hostport]closeable)
 	for _, pm := range podPortMapping.PortMappings {
@@ -300,12 +317,12 @@ func (hm *hostportManager) openHostports(podPortMapping *PodPortMapping) (map[ho
 		}
 
 		// HostIP IP family is not handled by this port opener
-		if pm.HostIP != "" && utilnet.IsIPv6String(pm.HostIP) != hm.iptables.IsIPv6() {
+		if pm.HostIP != "" && utilnet.IsIPv6String(pm.HostIP) != (family == IPv6) {
 			continue
 		}
 
-		hp := portMappingToHostport(pm, hm.getIPFamily())
-		socket, err := hm.portOpener(&hp)
+		hp := portMappingToHostport(pm, family)
+		socket, err := portOpener(&hp)
 		if err != nil {
 			retErr = fmt.Errorf("cannot open hostport %d for pod %s: %w", pm.HostPort, getPodFullName(podPortMapping), err)
 			break
@@ -325,18 +342,19 @@ func (hm *hostportManager) openHostports(podPortMapping *PodPortMapping) (map[ho
 	return ports, nil
 }
 
-// closeHostports tries to close all the listed host ports
-func (hm *hostportManager) closeHostports(hostportMappings []*PortMapping) error {
+// closeHostports tries to close all the listed host ports of the provided IP family
+// and removes them from the hostPortMap
+func closeHostports(hostPortMap map[hostport]closeable, hostportMappings []*PortMapping, family ipFamily) error {
 	errList := []error{}
 	for _, pm := range hostportMappings {
-		hp := portMappingToHostport(pm, hm.getIPFamily())
-		if socket, ok := hm.hostPortMap[hp]; ok {
+		hp := portMappingToHostport(pm, family)
+		if socket, ok := hostPortMap[hp]; ok {
 			logrus.Infof("Closing host port %s", hp.String())
 			if err := socket.Close(); err != nil {
 				errList = append(errList, fmt.Errorf("failed to close host port %s: %w", hp.String(), err))
 				continue
 			}
-			delete(hm.hostPortMap, hp)
+			delete(hostPortMap, hp)
 		} else {
 			logrus.Infof("Host port %s does not have an open socket", hp.String())
 		}
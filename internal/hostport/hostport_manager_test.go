@@ -128,9 +128,10 @@ func TestOpenCloseHostports(t *testing.T) {
 	iptables.protocol = utiliptables.ProtocolIPv4
 	portOpener := newFakeSocketManager()
 	manager := &hostportManager{
-		hostPortMap: make(map[hostport]closeable),
-		iptables:    iptables,
-		portOpener:  portOpener.openFakeSocket,
+		hostPortMap:     make(map[hostport]closeable),
+		podPortMappings: make(map[string]*desiredPodPortMapping),
+		iptables:        iptables,
+		portOpener:      portOpener.openFakeSocket,
 	}
 
 	// open all hostports defined in the test cases
@@ -229,9 +230,10 @@ func TestHostportManager(t *testing.T) {
 	iptables.protocol = utiliptables.ProtocolIPv4
 	portOpener := newFakeSocketManager()
 	manager := &hostportManager{
-		hostPortMap: make(map[hostport]closeable),
-		iptables:    iptables,
-		portOpener:  portOpener.openFakeSocket,
+		hostPortMap:     make(map[hostport]closeable),
+		podPortMappings: make(map[string]*desiredPodPortMapping),
+		iptables:        iptables,
+		portOpener:      portOpener.openFakeSocket,
 	}
 	testCases := []struct {
 		mapping     *PodPortMapping
@@ -555,9 +557,10 @@ func TestHostportManagerIPv6(t *testing.T) {
 	iptables.protocol = utiliptables.ProtocolIPv6
 	portOpener := newFakeSocketManager()
 	manager := &hostportManager{
-		hostPortMap: make(map[hostport]closeable),
-		iptables:    iptables,
-		portOpener:  portOpener.openFakeSocket,
+		hostPortMap:     make(map[hostport]closeable),
+		podPortMappings: make(map[string]*desiredPodPortMapping),
+		iptables:        iptables,
+		portOpener:      portOpener.openFakeSocket,
 	}
 	testCases := []struct {
 		mapping     *PodPortMapping
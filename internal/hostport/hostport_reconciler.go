@@ -0,0 +1,160 @@
+package hostport
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	utiliptables "github.com/cri-o/cri-o/internal/iptables"
+	"github.com/cri-o/cri-o/server/metrics"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/wait"
+	utilnet "k8s.io/utils/net"
+)
+
+const (
+	// the canary chain used to detect flushes of the nat table
+	crioHostportsCanaryChain utiliptables.Chain = "CRIO-HOSTPORTS-CANARY"
+
+	// the interval to check for missing hostport rules
+	reconcileInterval = time.Minute
+)
+
+// HostPortReconciler is an interface for restoring the hostport mappings of
+// the pods, if they got removed by an external agent.
+// nolint:golint // same naming as the HostPortManager
+type HostPortReconciler interface {
+	// Reconcile periodically checks the hostport rules and restores the
+	// missing ones. It blocks until stopCh gets closed.
+	Reconcile(stopCh <-chan struct{})
+}
+
+// HostPortRestorer is an interface for handing the port mappings of pods,
+// which have been added before a restart, back to the HostPortManager.
+// nolint:golint // same naming as the HostPortManager
+type HostPortRestorer interface {
+	// Restore remembers the port mapping of a pod without programming it again.
+	// id should be the same identifier which has been used for adding it.
+	Restore(id string, podPortMapping *PodPortMapping, natInterfaceName string)
+}
+
+// desiredPodPortMapping is a successfully added port mapping, which should be
+// present until the pod gets removed.
+type desiredPodPortMapping struct {
+	podPortMapping   *PodPortMapping
+	natInterfaceName string
+}
+
+// Reconcile detects flushes of the nat table by using a canary chain as well as
+// any other drift by comparing the iptables-save output with the desired rules.
+func (hm *hostportManager) Reconcile(stopCh <-chan struct{}) {
+	if !hm.iptables.Present() {
+		logrus.Infof("Skipping IPv%s hostport reconciliation because iptables is not available", hm.getIPFamily())
+		return
+	}
+	go hm.iptables.Monitor(crioHostportsCanaryChain, []utiliptables.Table{utiliptables.TableNAT}, hm.reconcile, reconcileInterval, stopCh)
+	wait.Until(hm.reconcile, reconcileInterval, stopCh)
+}
+
+// Restore remembers the port mapping of a pod added before a restart, so that
+// its rules get restored by the reconciliation if they go missing.
+func (hm *hostportManager) Restore(id string, podPortMapping *PodPortMapping, natInterfaceName string) {
+	if podPortMapping == nil || podPortMapping.HostNetwork || podPortMapping.IP.To16() == nil {
+		return
+	}
+	isIPv6 := utilnet.IsIPv6(podPortMapping.IP)
+	if isIPv6 != hm.iptables.IsIPv6() || len(gatherHostportMappings(podPortMapping, isIPv6)) == 0 {
+		return
+	}
+
+	hm.mu.Lock()
+	defer hm.mu.Unlock()
+	hm.podPortMappings[id] = &desiredPodPortMapping{
+		podPortMapping:   podPortMapping,
+		natInterfaceName: natInterfaceName,
+	}
+}
+
+// reconcile restores the rules of all pods which have been modified externally.
+func (hm *hostportManager) reconcile() {
+	hm.mu.Lock()
+	defer hm.mu.Unlock()
+
+	if len(hm.podPortMappings) == 0 {
+		return
+	}
+
+	ids, err := hm.driftedPods()
+	if err != nil {
+		logrus.Warnf("Unable to check hostport rules: %v", err)
+		return
+	}
+
+	for _, id := range ids {
+		desired := hm.podPortMappings[id]
+		podFullName := getPodFullName(desired.podPortMapping)
+		logrus.Warnf("Restoring hostport rules of pod %s", podFullName)
+
+		if err := ensureKubeHostportChains(hm.iptables, desired.natInterfaceName); err != nil {
+			logrus.Errorf("Unable to restore hostport chains of pod %s: %v", podFullName, err)
+			continue
+		}
+		hostportMappings := gatherHostportMappings(desired.podPortMapping, hm.iptables.IsIPv6())
+		if err := hm.syncHostportRules(id, desired.podPortMapping, hostportMappings); err != nil {
+			logrus.Errorf("Unable to restore hostport rules of pod %s: %v", podFullName, err)
+			continue
+		}
+		// Traffic may have been tracked without DNAT while the rules were missing
+		deleteUDPConntrackEntries(udpHostports(hostportMappings), hm.iptables.IsIPv6())
+		metrics.Instance().MetricHostportRepairsInc(string(hm.getIPFamily()))
+	}
+}
+
+// driftedPods returns the sorted IDs of the pods which miss any of their
+// hostport chains or rules.
+func (hm *hostportManager) driftedPods() ([]string, error) {
+	existingChains, existingRules, err := getExistingHostportIPTablesRules(hm.iptables)
+	if err != nil {
+		return nil, err
+	}
+
+	// Collect the chains which are jumped to and the ones containing any rule
+	jumpTargets := make(map[utiliptables.Chain]bool)
+	populatedChains := make(map[utiliptables.Chain]bool)
+	for _, rule := range existingRules {
+		fields := strings.Fields(rule)
+		if len(fields) < 2 {
+			continue
+		}
+		populatedChains[utiliptables.Chain(fields[1])] = true
+		for i := len(fields) - 2; i >= 2; i-- {
+			if fields[i] == "-j" {
+				jumpTargets[utiliptables.Chain(fields[i+1])] = true
+				break
+			}
+		}
+	}
+
+	_, hasHostportsChain := existingChains[kubeHostportsChain]
+	_, hasMasqueradeChain := existingChains[crioMasqueradeChain]
+
+	ids := []string{}
+	for id, desired := range hm.podPortMappings {
+		drifted := !hasHostportsChain || !hasMasqueradeChain
+		for _, pm := range gatherHostportMappings(desired.podPortMapping, hm.iptables.IsIPv6()) {
+			for _, chain := range []utiliptables.Chain{
+				getHostportChain(kubeHostportChainPrefix, id, pm),
+				getHostportChain(crioMasqueradeChainPrefix, id, pm),
+			} {
+				if _, ok := existingChains[chain]; !ok || !jumpTargets[chain] || !populatedChains[chain] {
+					drifted = true
+				}
+			}
+		}
+		if drifted {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
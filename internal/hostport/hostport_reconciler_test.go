@@ -0,0 +1,133 @@
+package hostport
+
+import (
+	"net"
+	"testing"
+
+	utiliptables "github.com/cri-o/cri-o/internal/iptables"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestHostportManagerReconcile(t *testing.T) {
+	iptables := newFakeIPTables()
+	portOpener := newFakeSocketManager()
+	manager := &hostportManager{
+		hostPortMap:     make(map[hostport]closeable),
+		podPortMappings: make(map[string]*desiredPodPortMapping),
+		iptables:        iptables,
+		portOpener:      portOpener.openFakeSocket,
+	}
+	mapping := &PodPortMapping{
+		Name:      "pod1",
+		Namespace: "ns1",
+		IP:        net.ParseIP("10.1.1.2"),
+		PortMappings: []*PortMapping{
+			{HostPort: 8080, ContainerPort: 80, Protocol: v1.ProtocolTCP},
+			{HostPort: 8081, ContainerPort: 81, Protocol: v1.ProtocolUDP},
+		},
+	}
+	assert.NoError(t, manager.Add("id1", mapping, "cbr0"))
+
+	// Nothing to do without any drift
+	ids, err := manager.driftedPods()
+	assert.NoError(t, err)
+	assert.Empty(t, ids)
+
+	// Simulate an external flush of the nat table
+	delete(iptables.tables, string(utiliptables.TableNAT))
+	ids, err = manager.driftedPods()
+	assert.Error(t, err)
+	assert.Empty(t, ids)
+	_, err = iptables.EnsureChain(utiliptables.TableNAT, utiliptables.ChainPrerouting)
+	assert.NoError(t, err)
+	ids, err = manager.driftedPods()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"id1"}, ids)
+
+	manager.reconcile()
+	ids, err = manager.driftedPods()
+	assert.NoError(t, err)
+	assert.Empty(t, ids)
+	_, chain, err := iptables.getChain(utiliptables.TableNAT, utiliptables.ChainPrerouting)
+	assert.NoError(t, err)
+	assert.Len(t, chain.rules, 1)
+	_, chain, err = iptables.getChain(utiliptables.TableNAT, kubeHostportsChain)
+	assert.NoError(t, err)
+	assert.Len(t, chain.rules, 2)
+
+	// Simulate the removal of a single pod chain
+	hpChain := getHostportChain(kubeHostportChainPrefix, "id1", mapping.PortMappings[1])
+	assert.NoError(t, iptables.FlushChain(utiliptables.TableNAT, hpChain))
+	ids, err = manager.driftedPods()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"id1"}, ids)
+
+	manager.reconcile()
+	_, chain, err = iptables.getChain(utiliptables.TableNAT, hpChain)
+	assert.NoError(t, err)
+	assert.Len(t, chain.rules, 1)
+	_, chain, err = iptables.getChain(utiliptables.TableNAT, kubeHostportsChain)
+	assert.NoError(t, err)
+	assert.Len(t, chain.rules, 2)
+
+	// The ports are still held by the manager
+	assert.Len(t, manager.hostPortMap, 2)
+	assert.Len(t, portOpener.mem, 2)
+
+	// Removed pods are not restored anymore
+	assert.NoError(t, manager.Remove("id1", mapping))
+	assert.Empty(t, manager.podPortMappings)
+	delete(iptables.tables, string(utiliptables.TableNAT))
+	manager.reconcile()
+	_, ok := iptables.tables[string(utiliptables.TableNAT)]
+	assert.False(t, ok)
+}
+
+func TestHostportManagerRestore(t *testing.T) {
+	iptables := newFakeIPTables()
+	portOpener := newFakeSocketManager()
+	manager := &hostportManager{
+		hostPortMap:     make(map[hostport]closeable),
+		podPortMappings: make(map[string]*desiredPodPortMapping),
+		iptables:        iptables,
+		portOpener:      portOpener.openFakeSocket,
+	}
+	mapping := &PodPortMapping{
+		Name:      "pod1",
+		Namespace: "ns1",
+		IP:        net.ParseIP("10.1.1.2"),
+		PortMappings: []*PortMapping{
+			{HostPort: 8080, ContainerPort: 80, Protocol: v1.ProtocolTCP},
+		},
+	}
+
+	// Mappings without hostports or of the other IP family are ignored
+	manager.Restore("id2", &PodPortMapping{Name: "pod2", IP: net.ParseIP("10.1.1.3")}, "")
+	manager.Restore("id3", &PodPortMapping{
+		Name:         "pod3",
+		IP:           net.ParseIP("2001:beef::3"),
+		PortMappings: mapping.PortMappings,
+	}, "")
+	assert.Empty(t, manager.podPortMappings)
+
+	// A mapping added before a restart gets reconciled after restoring it
+	manager.Restore("id1", mapping, "")
+	assert.Len(t, manager.podPortMappings, 1)
+	_, err := iptables.EnsureChain(utiliptables.TableNAT, utiliptables.ChainPrerouting)
+	assert.NoError(t, err)
+	ids, err := manager.driftedPods()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"id1"}, ids)
+
+	manager.reconcile()
+	ids, err = manager.driftedPods()
+	assert.NoError(t, err)
+	assert.Empty(t, ids)
+	_, chain, err := iptables.getChain(utiliptables.TableNAT, kubeHostportsChain)
+	assert.NoError(t, err)
+	assert.Len(t, chain.rules, 1)
+
+	// The ports are not reopened by restoring the mapping
+	assert.Empty(t, portOpener.mem)
+}
@@ -3,6 +3,7 @@ package hostport
 import (
 	"errors"
 	"strings"
+	"sync"
 
 	utiliptables "github.com/cri-o/cri-o/internal/iptables"
 	utilexec "k8s.io/utils/exec"
@@ -56,3 +57,41 @@ func (mh *metaHostportManager) Remove(id string, podPortMapping *PodPortMapping)
 	}
 	return nil
 }
+
+// NewMetaNftablesHostportManager creates a new HostPortManager which uses
+// nftables for IPv4 as well as IPv6.
+func NewMetaNftablesHostportManager() HostPortManager {
+	nft := newNftables(utilexec.New())
+	return &metaHostportManager{
+		ipv4HostportManager: newNftablesHostportManager(nft, IPv4),
+		ipv6HostportManager: newNftablesHostportManager(nft, IPv6),
+	}
+}
+
+// Reconcile restores the hostport mappings of the IPv4 and IPv6 managers, if
+// they support it.
+func (mh *metaHostportManager) Reconcile(stopCh <-chan struct{}) {
+	var wg sync.WaitGroup
+	for _, manager := range []HostPortManager{mh.ipv4HostportManager, mh.ipv6HostportManager} {
+		if reconciler, ok := manager.(HostPortReconciler); ok {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				reconciler.Reconcile(stopCh)
+			}()
+		}
+	}
+	wg.Wait()
+}
+
+// Restore hands the port mapping of a pod back to the manager of its IP
+// family, if it supports it.
+func (mh *metaHostportManager) Restore(id string, podPortMapping *PodPortMapping, natInterfaceName string) {
+	manager := mh.ipv4HostportManager
+	if utilnet.IsIPv6(podPortMapping.IP) {
+		manager = mh.ipv6HostportManager
+	}
+	if restorer, ok := manager.(HostPortRestorer); ok {
+		restorer.Restore(id, podPortMapping, natInterfaceName)
+	}
+}
@@ -23,14 +23,16 @@ func TestMetaHostportManager(t *testing.T) {
 
 	manager := metaHostportManager{
 		ipv4HostportManager: &hostportManager{
-			hostPortMap: make(map[hostport]closeable),
-			iptables:    iptables,
-			portOpener:  portOpener.openFakeSocket,
+			hostPortMap:     make(map[hostport]closeable),
+			podPortMappings: make(map[string]*desiredPodPortMapping),
+			iptables:        iptables,
+			portOpener:      portOpener.openFakeSocket,
 		},
 		ipv6HostportManager: &hostportManager{
-			hostPortMap: make(map[hostport]closeable),
-			iptables:    ip6tables,
-			portOpener:  port6Opener.openFakeSocket,
+			hostPortMap:     make(map[hostport]closeable),
+			podPortMappings: make(map[string]*desiredPodPortMapping),
+			iptables:        ip6tables,
+			portOpener:      port6Opener.openFakeSocket,
 		},
 	}
 
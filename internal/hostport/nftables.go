@@ -0,0 +1,40 @@
+package hostport
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	utilexec "k8s.io/utils/exec"
+)
+
+const nftBinary = "nft"
+
+// nftables is the interface used by the nftables based HostPortManager to
+// program the kernel.
+type nftables interface {
+	// Run applies the provided nft script as a single atomic transaction.
+	Run(script []byte) error
+}
+
+type nftRunner struct {
+	exec utilexec.Interface
+}
+
+// newNftables returns a new nftables interface which shells out to the nft binary.
+func newNftables(exec utilexec.Interface) nftables {
+	if _, err := exec.LookPath(nftBinary); err != nil {
+		logrus.Warnf("Unable to find %s binary in $PATH, hostport mappings will fail: %v", nftBinary, err)
+	}
+	return &nftRunner{exec: exec}
+}
+
+func (r *nftRunner) Run(script []byte) error {
+	logrus.Debugf("Running nft script: %s", script)
+	cmd := r.exec.Command(nftBinary, "-f", "-")
+	cmd.SetStdin(bytes.NewReader(script))
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to run %s: %w: %s", nftBinary, err, out)
+	}
+	return nil
+}
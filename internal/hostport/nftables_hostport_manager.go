@@ -0,0 +1,361 @@
+package hostport
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	utilnet "k8s.io/utils/net"
+)
+
+const (
+	// the nftables table which holds all hostport related objects
+	nftHostportsTable = "crio-hostports"
+
+	// map from host IP, protocol and host port to pod IP and container port
+	nftHostIPHostportsMap = "hostip-hostports"
+	// map from protocol and host port to pod IP and container port
+	nftHostportsMap = "hostports"
+	// set of pod IP . pod IP tuples which require hairpin masquerading
+	nftHairpinsSet = "hairpins"
+	// set of interfaces which localhost uses to talk to the pods
+	nftSNATInterfacesSet = "snat-interfaces"
+
+	// the regular chain doing the actual DNAT
+	nftHostportsChain = "hostports"
+)
+
+// nftHostportManager programs the hostport mappings into its own nftables
+// table. Unlike the iptables based manager, it does not reconcile the table,
+// so mappings removed externally, for example by flushing the whole ruleset,
+// are only restored when the pod gets added again.
+type nftHostportManager struct {
+	hostPortMap map[hostport]closeable
+	podIPs      map[string]net.IP
+	// the map elements programmed for the pods by their element keys, which
+	// also protects the elements of SCTP host ports, as no sockets get
+	// opened for them
+	elements   map[string]*nftMapElement
+	nft        nftables
+	family     ipFamily
+	portOpener hostportOpener
+	mu         sync.Mutex
+}
+
+// nftMapElement is a map element programmed for a pod.
+type nftMapElement struct {
+	owner   string
+	mapName string
+	key     string
+	value   string
+}
+
+// newNftablesHostportManager creates a new HostPortManager for the provided
+// IP family, which programs the hostport mappings natively using nftables.
+func newNftablesHostportManager(nft nftables, family ipFamily) HostPortManager {
+	return &nftHostportManager{
+		hostPortMap: make(map[hostport]closeable),
+		podIPs:      make(map[string]net.IP),
+		elements:    make(map[string]*nftMapElement),
+		nft:         nft,
+		family:      family,
+		portOpener:  openLocalPort,
+	}
+}
+
+func (hm *nftHostportManager) Add(id string, podPortMapping *PodPortMapping, natInterfaceName string) error {
+	if podPortMapping == nil || podPortMapping.HostNetwork {
+		return nil
+	}
+	podFullName := getPodFullName(podPortMapping)
+	// IP.To16() returns nil if IP is not a valid IPv4 or IPv6 address
+	if podPortMapping.IP.To16() == nil {
+		return fmt.Errorf("invalid or missing IP of pod %s", podFullName)
+	}
+	isIPv6 := utilnet.IsIPv6(podPortMapping.IP)
+
+	// skip if there is no hostport needed
+	hostportMappings := gatherHostportMappings(podPortMapping, isIPv6)
+	if len(hostportMappings) == 0 {
+		return nil
+	}
+
+	if isIPv6 != hm.isIPv6() {
+		return fmt.Errorf("HostPortManager IP family mismatch: %v, isIPv6 - %v", podPortMapping.IP, isIPv6)
+	}
+
+	// Ensure atomicity for port opening and nftables operations
+	hm.mu.Lock()
+	defer hm.mu.Unlock()
+
+	for _, pm := range hostportMappings {
+		if element, ok := hm.elements[hm.elementKey(pm)]; ok && element.owner != id {
+			return fmt.Errorf("cannot open hostport %d for pod %s: already used by another pod", pm.HostPort, podFullName)
+		}
+	}
+
+	ports, err := openHostports(hm.portOpener, podPortMapping, hm.family)
+	if err != nil {
+		return err
+	}
+	for hostport, socket := range ports {
+		hm.hostPortMap[hostport] = socket
+	}
+
+	script := bytes.NewBuffer(nil)
+	hm.writeObjects(script)
+	hm.writeChains(script)
+	if natInterfaceName != "" && natInterfaceName != "lo" {
+		hm.writeElement(script, "add", nftSNATInterfacesSet, strconv.Quote(natInterfaceName))
+	}
+
+	// Replace any previous entry of this pod, for example if the pod IP changed.
+	hm.deleteElements(script, id, hm.podIPs[id])
+
+	elements := hm.mapElements(id, podPortMapping.IP, hostportMappings)
+	for _, element := range elements {
+		hm.writeElement(script, "add", element.mapName, element.key+" : "+element.value)
+	}
+	hm.writeElement(script, "add", nftHairpinsSet, hm.hairpinElement(podPortMapping.IP))
+
+	if err := hm.nft.Run(script.Bytes()); err != nil {
+		// clean up opened host port if encounter any error
+		return utilerrors.NewAggregate([]error{
+			fmt.Errorf("failed to add hostport mappings for pod %s: %w", podFullName, err),
+			closeHostports(hm.hostPortMap, hostportMappings, hm.family),
+		})
+	}
+	hm.podIPs[id] = podPortMapping.IP
+	hm.setElements(id, elements)
+
+	// Remove conntrack entries just after adding the new nftables elements,
+	// for the same reasons as the iptables based implementation.
+	deleteUDPConntrackEntries(udpHostports(hostportMappings), isIPv6)
+	return nil
+}
+
+func (hm *nftHostportManager) Remove(id string, podPortMapping *PodPortMapping) error {
+	if podPortMapping == nil || podPortMapping.HostNetwork {
+		return nil
+	}
+
+	hostportMappings := gatherHostportMappings(podPortMapping, hm.isIPv6())
+	if len(hostportMappings) == 0 {
+		return nil
+	}
+
+	// Ensure atomicity for port closing and nftables operations
+	hm.mu.Lock()
+	defer hm.mu.Unlock()
+
+	// Remove may not have the IP information, fallback to the one used on Add.
+	podIP := podPortMapping.IP
+	if podIP.To16() == nil {
+		podIP = hm.podIPs[id]
+	}
+
+	script := bytes.NewBuffer(nil)
+	hm.writeObjects(script)
+	hm.deleteElements(script, id, podIP)
+	if err := hm.nft.Run(script.Bytes()); err != nil {
+		return fmt.Errorf("failed to remove hostport mappings for pod %s: %w", getPodFullName(podPortMapping), err)
+	}
+	delete(hm.podIPs, id)
+	hm.setElements(id, nil)
+
+	// clean up opened pod host ports
+	return closeHostports(hm.hostPortMap, hostportMappings, hm.family)
+}
+
+// Restore remembers the IP and the elements of a pod added before a restart.
+// The IP is required to remove its hairpin element without the IP information.
+func (hm *nftHostportManager) Restore(id string, podPortMapping *PodPortMapping, _ string) {
+	if podPortMapping == nil || podPortMapping.HostNetwork || podPortMapping.IP.To16() == nil {
+		return
+	}
+	if utilnet.IsIPv6(podPortMapping.IP) != hm.isIPv6() {
+		return
+	}
+
+	hm.mu.Lock()
+	defer hm.mu.Unlock()
+	hm.podIPs[id] = podPortMapping.IP
+	hm.setElements(id, hm.mapElements(id, podPortMapping.IP, gatherHostportMappings(podPortMapping, hm.isIPv6())))
+}
+
+// mapElements returns the map elements of the port mappings of the pod.
+func (hm *nftHostportManager) mapElements(id string, podIP net.IP, hostportMappings []*PortMapping) []*nftMapElement {
+	elements := make([]*nftMapElement, 0, len(hostportMappings))
+	for _, pm := range hostportMappings {
+		mapName, key := hm.mapKey(pm)
+		elements = append(elements, &nftMapElement{
+			owner:   id,
+			mapName: mapName,
+			key:     key,
+			value:   fmt.Sprintf("%s . %d", podIP, pm.ContainerPort),
+		})
+	}
+	return elements
+}
+
+// setElements replaces the recorded map elements of the pod.
+func (hm *nftHostportManager) setElements(id string, elements []*nftMapElement) {
+	for key, element := range hm.elements {
+		if element.owner == id {
+			delete(hm.elements, key)
+		}
+	}
+	for _, element := range elements {
+		hm.elements[element.mapName+" "+element.key] = element
+	}
+}
+
+// writeObjects ensures the table, maps and sets exist. Adding already existing
+// objects is a no-op in nftables.
+func (hm *nftHostportManager) writeObjects(script *bytes.Buffer) {
+	addrType := hm.addrType()
+	writeLine(script, "add", "table", hm.nftFamily(), nftHostportsTable)
+	writeLine(script, "add", "map", hm.nftFamily(), nftHostportsTable, nftHostIPHostportsMap,
+		"{", "type", addrType, ".", "inet_proto", ".", "inet_service", ":", addrType, ".", "inet_service", ";", "}")
+	writeLine(script, "add", "map", hm.nftFamily(), nftHostportsTable, nftHostportsMap,
+		"{", "type", "inet_proto", ".", "inet_service", ":", addrType, ".", "inet_service", ";", "}")
+	writeLine(script, "add", "set", hm.nftFamily(), nftHostportsTable, nftHairpinsSet,
+		"{", "type", addrType, ".", addrType, ";", "}")
+	writeLine(script, "add", "set", hm.nftFamily(), nftHostportsTable, nftSNATInterfacesSet,
+		"{", "type", "ifname", ";", "}")
+}
+
+// writeChains (re)creates the chains and their rules. The chains get flushed
+// within the same transaction, which makes the result independent of their
+// previous content.
+func (hm *nftHostportManager) writeChains(script *bytes.Buffer) {
+	// the address family name is the payload expression keyword for the IP header as well
+	family := hm.nftFamily()
+	localhost := "127.0.0.0/8"
+	if hm.isIPv6() {
+		localhost = "::1"
+	}
+
+	chains := []struct {
+		name, spec string
+		rules      [][]string
+	}{
+		{
+			name: nftHostportsChain,
+			rules: [][]string{
+				{"dnat", "to", family, "daddr", ".", "meta", "l4proto", ".", "th", "dport", "map", "@" + nftHostIPHostportsMap},
+				{"dnat", "to", "meta", "l4proto", ".", "th", "dport", "map", "@" + nftHostportsMap},
+			},
+		},
+		{
+			name: "prerouting",
+			spec: "{ type nat hook prerouting priority dstnat ; }",
+			rules: [][]string{
+				{"fib", "daddr", "type", "local", "jump", nftHostportsChain},
+			},
+		},
+		{
+			name: "output",
+			spec: "{ type nat hook output priority -100 ; }",
+			rules: [][]string{
+				{"fib", "daddr", "type", "local", "jump", nftHostportsChain},
+			},
+		},
+		{
+			name: "postrouting",
+			spec: "{ type nat hook postrouting priority srcnat ; }",
+			rules: [][]string{
+				// SNAT hairpin traffic, which has been DNATed and has src=dst=podIP
+				{"ct", "status", "dnat", family, "saddr", ".", family, "daddr", "@" + nftHairpinsSet, "masquerade"},
+				// SNAT traffic from localhost
+				{"ct", "status", "dnat", "oifname", "@" + nftSNATInterfacesSet, family, "saddr", localhost, "masquerade"},
+			},
+		},
+	}
+
+	for _, chain := range chains {
+		args := []string{"add", "chain", family, nftHostportsTable, chain.name}
+		if chain.spec != "" {
+			args = append(args, chain.spec)
+		}
+		writeLine(script, args...)
+		writeLine(script, "flush", "chain", family, nftHostportsTable, chain.name)
+		for _, rule := range chain.rules {
+			writeLine(script, append([]string{"add", "rule", family, nftHostportsTable, chain.name}, rule...)...)
+		}
+	}
+}
+
+// deleteElements removes all recorded elements of the pod. Deleting a
+// non-existing element fails the whole transaction, which is why every
+// element is added right before, in case it has been removed externally.
+// Adding an existing element only succeeds with the same value, so only the
+// elements recorded with their programmed values get deleted.
+func (hm *nftHostportManager) deleteElements(script *bytes.Buffer, id string, podIP net.IP) {
+	keys := make([]string, 0, len(hm.elements))
+	for key, element := range hm.elements {
+		if element.owner == id {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		element := hm.elements[key]
+		hm.writeElement(script, "add", element.mapName, element.key+" : "+element.value)
+		hm.writeElement(script, "delete", element.mapName, element.key)
+	}
+	if podIP.To16() != nil {
+		element := hm.hairpinElement(podIP)
+		hm.writeElement(script, "add", nftHairpinsSet, element)
+		hm.writeElement(script, "delete", nftHairpinsSet, element)
+	}
+}
+
+// writeElement writes a single add or delete element statement.
+func (hm *nftHostportManager) writeElement(script *bytes.Buffer, op, setName, element string) {
+	writeLine(script, op, "element", hm.nftFamily(), nftHostportsTable, setName, "{", element, "}")
+}
+
+// mapKey returns the map and the key of the element used for the provided port mapping.
+func (hm *nftHostportManager) mapKey(pm *PortMapping) (mapName, key string) {
+	protocol := strings.ToLower(string(pm.Protocol))
+	if pm.HostIP == "" || pm.HostIP == "0.0.0.0" || pm.HostIP == "::" {
+		return nftHostportsMap, fmt.Sprintf("%s . %d", protocol, pm.HostPort)
+	}
+	return nftHostIPHostportsMap, fmt.Sprintf("%s . %s . %d", pm.HostIP, protocol, pm.HostPort)
+}
+
+// elementKey returns a unique key of the map element used for the port mapping.
+func (hm *nftHostportManager) elementKey(pm *PortMapping) string {
+	mapName, key := hm.mapKey(pm)
+	return mapName + " " + key
+}
+
+func (hm *nftHostportManager) hairpinElement(podIP net.IP) string {
+	return fmt.Sprintf("%s . %s", podIP, podIP)
+}
+
+func (hm *nftHostportManager) isIPv6() bool {
+	return hm.family == IPv6
+}
+
+// nftFamily returns the nftables address family of the managed table.
+func (hm *nftHostportManager) nftFamily() string {
+	if hm.isIPv6() {
+		return "ip6"
+	}
+	return "ip"
+}
+
+// addrType returns the nftables data type of an address.
+func (hm *nftHostportManager) addrType() string {
+	if hm.isIPv6() {
+		return "ipv6_addr"
+	}
+	return "ipv4_addr"
+}
@@ -0,0 +1,349 @@
+package hostport
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestNftablesHostportManager(t *testing.T) {
+	nft := newFakeNftables()
+	portOpener := newFakeSocketManager()
+	manager := &nftHostportManager{
+		hostPortMap: make(map[hostport]closeable),
+		podIPs:      make(map[string]net.IP),
+		elements:    make(map[string]*nftMapElement),
+		nft:         nft,
+		family:      IPv4,
+		portOpener:  portOpener.openFakeSocket,
+	}
+	testCases := []struct {
+		id          string
+		mapping     *PodPortMapping
+		expectError bool
+	}{
+		// open HostPorts 8080/TCP, 8081/UDP and 8083/SCTP
+		{
+			id: "id1",
+			mapping: &PodPortMapping{
+				Name:      "pod1",
+				Namespace: "ns1",
+				IP:        net.ParseIP("10.1.1.2"),
+				PortMappings: []*PortMapping{
+					{HostPort: 8080, ContainerPort: 80, Protocol: v1.ProtocolTCP},
+					{HostPort: 8081, ContainerPort: 81, Protocol: v1.ProtocolUDP},
+					{HostPort: 8083, ContainerPort: 83, Protocol: v1.ProtocolSCTP},
+				},
+			},
+		},
+		// fail to open HostPort due to conflict 8081/UDP
+		{
+			id: "id2",
+			mapping: &PodPortMapping{
+				Name:      "pod2",
+				Namespace: "ns1",
+				IP:        net.ParseIP("10.1.1.3"),
+				PortMappings: []*PortMapping{
+					{HostPort: 8082, ContainerPort: 80, Protocol: v1.ProtocolTCP},
+					{HostPort: 8081, ContainerPort: 81, Protocol: v1.ProtocolUDP},
+				},
+			},
+			expectError: true,
+		},
+		// fail to map HostPort due to conflict 8083/SCTP
+		{
+			id: "id6",
+			mapping: &PodPortMapping{
+				Name:      "pod6",
+				Namespace: "ns1",
+				IP:        net.ParseIP("10.1.1.6"),
+				PortMappings: []*PortMapping{
+					{HostPort: 8083, ContainerPort: 83, Protocol: v1.ProtocolSCTP},
+				},
+			},
+			expectError: true,
+		},
+		// open port 443 on a specific host IP
+		{
+			id: "id3",
+			mapping: &PodPortMapping{
+				Name:      "pod3",
+				Namespace: "ns1",
+				IP:        net.ParseIP("10.1.1.4"),
+				PortMappings: []*PortMapping{
+					{HostPort: 8443, ContainerPort: 443, Protocol: v1.ProtocolTCP, HostIP: "192.168.0.1"},
+				},
+			},
+		},
+		// fail on IP family mismatch
+		{
+			id: "id4",
+			mapping: &PodPortMapping{
+				Name:      "pod4",
+				Namespace: "ns1",
+				IP:        net.ParseIP("2001:beef::2"),
+				PortMappings: []*PortMapping{
+					{HostPort: 8444, ContainerPort: 444, Protocol: v1.ProtocolTCP},
+				},
+			},
+			expectError: true,
+		},
+		// skip host network pods
+		{
+			id: "id5",
+			mapping: &PodPortMapping{
+				Name:        "pod5",
+				Namespace:   "ns1",
+				HostNetwork: true,
+				PortMappings: []*PortMapping{
+					{HostPort: 8445, ContainerPort: 445, Protocol: v1.ProtocolTCP},
+				},
+			},
+		},
+	}
+
+	// Add Hostports
+	for _, tc := range testCases {
+		err := manager.Add(tc.id, tc.mapping, "cbr0")
+		if tc.expectError {
+			assert.Error(t, err)
+			continue
+		}
+		assert.NoError(t, err)
+	}
+
+	// Check the nftables state
+	hostports, err := nft.getSet("ip crio-hostports", nftHostportsMap)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"tcp . 8080":  "10.1.1.2 . 80",
+		"udp . 8081":  "10.1.1.2 . 81",
+		"sctp . 8083": "10.1.1.2 . 83",
+	}, hostports)
+
+	hostIPHostports, err := nft.getSet("ip crio-hostports", nftHostIPHostportsMap)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"192.168.0.1 . tcp . 8443": "10.1.1.4 . 443",
+	}, hostIPHostports)
+
+	hairpins, err := nft.getSet("ip crio-hostports", nftHairpinsSet)
+	assert.NoError(t, err)
+	assert.Len(t, hairpins, 2)
+	assert.Contains(t, hairpins, "10.1.1.2 . 10.1.1.2")
+	assert.Contains(t, hairpins, "10.1.1.4 . 10.1.1.4")
+
+	snatInterfaces, err := nft.getSet("ip crio-hostports", nftSNATInterfacesSet)
+	assert.NoError(t, err)
+	assert.Contains(t, snatInterfaces, `"cbr0"`)
+
+	table := nft.tables["ip crio-hostports"]
+	assert.Len(t, table.chains[nftHostportsChain], 2)
+	assert.Len(t, table.chains["prerouting"], 1)
+	assert.Len(t, table.chains["output"], 1)
+	assert.Len(t, table.chains["postrouting"], 2)
+
+	// Check the opened ports, SCTP ports are not opened
+	assert.Len(t, manager.hostPortMap, 3)
+
+	// Adding the same pod again with a new IP replaces the elements and
+	// does not duplicate the rules
+	err = manager.Remove("id1", testCases[0].mapping)
+	assert.NoError(t, err)
+	testCases[0].mapping.IP = net.ParseIP("10.1.1.5")
+	err = manager.Add("id1", testCases[0].mapping, "cbr0")
+	assert.NoError(t, err)
+	assert.Equal(t, "10.1.1.5 . 80", hostports["tcp . 8080"])
+	assert.NotContains(t, hairpins, "10.1.1.2 . 10.1.1.2")
+	assert.Len(t, table.chains[nftHostportsChain], 2)
+
+	// Re-adding a pod without removing it replaces the hairpin element of
+	// its previous IP
+	sctpMapping := &PodPortMapping{
+		Name:      "pod1",
+		Namespace: "ns1",
+		IP:        net.ParseIP("10.1.1.7"),
+		PortMappings: []*PortMapping{
+			{HostPort: 8083, ContainerPort: 83, Protocol: v1.ProtocolSCTP},
+		},
+	}
+	err = manager.Add("id1", sctpMapping, "cbr0")
+	assert.NoError(t, err)
+	assert.Equal(t, "10.1.1.7 . 83", hostports["sctp . 8083"])
+	assert.Contains(t, hairpins, "10.1.1.7 . 10.1.1.7")
+	assert.NotContains(t, hairpins, "10.1.1.5 . 10.1.1.5")
+
+	// Remove all added hostports, without providing the pod IP
+	for _, tc := range testCases {
+		if !tc.expectError {
+			err := manager.Remove(tc.id, &PodPortMapping{
+				Name:         tc.mapping.Name,
+				Namespace:    tc.mapping.Namespace,
+				PortMappings: tc.mapping.PortMappings,
+				HostNetwork:  tc.mapping.HostNetwork,
+			})
+			assert.NoError(t, err)
+		}
+	}
+
+	// Removing twice is fine
+	err = manager.Remove("id3", testCases[2].mapping)
+	assert.NoError(t, err)
+
+	hostports, err = nft.getSet("ip crio-hostports", nftHostportsMap)
+	assert.NoError(t, err)
+	assert.Empty(t, hostports)
+	hostIPHostports, err = nft.getSet("ip crio-hostports", nftHostIPHostportsMap)
+	assert.NoError(t, err)
+	assert.Empty(t, hostIPHostports)
+	hairpins, err = nft.getSet("ip crio-hostports", nftHairpinsSet)
+	assert.NoError(t, err)
+	assert.Empty(t, hairpins)
+
+	// Check that all ports got closed
+	assert.Empty(t, manager.hostPortMap)
+	for _, port := range portOpener.mem {
+		assert.True(t, port.closed)
+	}
+}
+
+func TestNftablesHostportManagerIPv6(t *testing.T) {
+	nft := newFakeNftables()
+	portOpener := newFakeSocketManager()
+	manager := &nftHostportManager{
+		hostPortMap: make(map[hostport]closeable),
+		podIPs:      make(map[string]net.IP),
+		elements:    make(map[string]*nftMapElement),
+		nft:         nft,
+		family:      IPv6,
+		portOpener:  portOpener.openFakeSocket,
+	}
+	mapping := &PodPortMapping{
+		Name:      "pod1",
+		Namespace: "ns1",
+		IP:        net.ParseIP("2001:beef::2"),
+		PortMappings: []*PortMapping{
+			{HostPort: 8080, ContainerPort: 80, Protocol: v1.ProtocolTCP},
+			{HostPort: 8081, ContainerPort: 81, Protocol: v1.ProtocolUDP, HostIP: "2001:beef::1"},
+			// filtered out because of the IP family
+			{HostPort: 8082, ContainerPort: 82, Protocol: v1.ProtocolTCP, HostIP: "192.168.0.1"},
+		},
+	}
+
+	assert.NoError(t, manager.Add("id1", mapping, ""))
+
+	hostports, err := nft.getSet("ip6 crio-hostports", nftHostportsMap)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"tcp . 8080": "2001:beef::2 . 80"}, hostports)
+	hostIPHostports, err := nft.getSet("ip6 crio-hostports", nftHostIPHostportsMap)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"2001:beef::1 . udp . 8081": "2001:beef::2 . 81"}, hostIPHostports)
+	snatInterfaces, err := nft.getSet("ip6 crio-hostports", nftSNATInterfacesSet)
+	assert.NoError(t, err)
+	assert.Empty(t, snatInterfaces)
+	assert.Len(t, manager.hostPortMap, 2)
+
+	assert.NoError(t, manager.Remove("id1", mapping))
+	assert.Empty(t, hostports)
+	assert.Empty(t, hostIPHostports)
+	assert.Empty(t, manager.hostPortMap)
+}
+
+func TestNftablesHostportManagerRestore(t *testing.T) {
+	nft := newFakeNftables()
+	manager := &nftHostportManager{
+		hostPortMap: make(map[hostport]closeable),
+		podIPs:      make(map[string]net.IP),
+		elements:    make(map[string]*nftMapElement),
+		nft:         nft,
+		family:      IPv4,
+		portOpener:  newFakeSocketManager().openFakeSocket,
+	}
+	mapping := &PodPortMapping{
+		Name:      "pod1",
+		Namespace: "ns1",
+		IP:        net.ParseIP("10.1.1.2"),
+		PortMappings: []*PortMapping{
+			{HostPort: 8083, ContainerPort: 83, Protocol: v1.ProtocolSCTP},
+		},
+	}
+	assert.NoError(t, manager.Add("id1", mapping, ""))
+
+	// Simulate a restart of the manager, which keeps the nftables state
+	restarted := &nftHostportManager{
+		hostPortMap: make(map[hostport]closeable),
+		podIPs:      make(map[string]net.IP),
+		elements:    make(map[string]*nftMapElement),
+		nft:         nft,
+		family:      IPv4,
+		portOpener:  newFakeSocketManager().openFakeSocket,
+	}
+	restarted.Restore("id1", mapping, "")
+
+	// The restored SCTP host port can not be taken over by another pod
+	err := restarted.Add("id2", &PodPortMapping{
+		Name:         "pod2",
+		Namespace:    "ns1",
+		IP:           net.ParseIP("10.1.1.3"),
+		PortMappings: mapping.PortMappings,
+	}, "")
+	assert.Error(t, err)
+	hostports, err := nft.getSet("ip crio-hostports", nftHostportsMap)
+	assert.NoError(t, err)
+	assert.Equal(t, "10.1.1.2 . 83", hostports["sctp . 8083"])
+
+	// The restored pod gets removed without providing its IP
+	err = restarted.Remove("id1", &PodPortMapping{
+		Name:         mapping.Name,
+		Namespace:    mapping.Namespace,
+		PortMappings: mapping.PortMappings,
+	})
+	assert.NoError(t, err)
+	hairpins, err := nft.getSet("ip crio-hostports", nftHairpinsSet)
+	assert.NoError(t, err)
+	assert.Empty(t, hairpins)
+	assert.Empty(t, restarted.elements)
+}
+
+func TestNftablesHostportManagerReAdd(t *testing.T) {
+	nft := newFakeNftables()
+	manager := &nftHostportManager{
+		hostPortMap: make(map[hostport]closeable),
+		podIPs:      make(map[string]net.IP),
+		elements:    make(map[string]*nftMapElement),
+		nft:         nft,
+		family:      IPv4,
+		portOpener:  newFakeSocketManager().openFakeSocket,
+	}
+	mapping := &PodPortMapping{
+		Name:      "pod1",
+		Namespace: "ns1",
+		IP:        net.ParseIP("10.1.1.2"),
+		PortMappings: []*PortMapping{
+			{HostPort: 8080, ContainerPort: 80, Protocol: v1.ProtocolTCP},
+			{HostPort: 8443, ContainerPort: 443, Protocol: v1.ProtocolTCP, HostIP: "192.168.0.1"},
+		},
+	}
+
+	// Add, remove and add the same pod again
+	assert.NoError(t, manager.Add("id1", mapping, ""))
+	assert.NoError(t, manager.Remove("id1", mapping))
+	hostports, err := nft.getSet("ip crio-hostports", nftHostportsMap)
+	assert.NoError(t, err)
+	assert.Empty(t, hostports)
+	assert.NoError(t, manager.Add("id1", mapping, ""))
+
+	assert.Equal(t, map[string]string{"tcp . 8080": "10.1.1.2 . 80"}, hostports)
+	hostIPHostports, err := nft.getSet("ip crio-hostports", nftHostIPHostportsMap)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"192.168.0.1 . tcp . 8443": "10.1.1.2 . 443"}, hostIPHostports)
+
+	// Elements removed externally do not fail the removal
+	delete(hostports, "tcp . 8080")
+	assert.NoError(t, manager.Remove("id1", mapping))
+	assert.Empty(t, hostports)
+	assert.Empty(t, hostIPHostports)
+	assert.Empty(t, manager.elements)
+}
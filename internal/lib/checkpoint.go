@@ -10,6 +10,7 @@ import (
 
 	metadata "github.com/checkpoint-restore/checkpointctl/lib"
 	"github.com/checkpoint-restore/go-criu/v7/stats"
+	"github.com/containers/image/v5/docker/reference"
 	"github.com/containers/podman/v4/pkg/checkpoint/crutils"
 	"github.com/containers/storage/pkg/archive"
 	"github.com/cri-o/cri-o/internal/log"
@@ -29,6 +30,14 @@ type ContainerCheckpointOptions struct {
 	// TargetFile tells the API to read (or write) the checkpoint image
 	// from (or to) the filename set in TargetFile
 	TargetFile string
+	// TargetImage tells the API to write the checkpoint as OCI image with
+	// the name set in TargetImage into the local storage
+	TargetImage string
+	// PreDumps is the number of memory pre-dumps written while the container
+	// keeps running. The final checkpoint only writes the memory pages
+	// changed since the last pre-dump, which shortens the time the container
+	// is frozen.
+	PreDumps int
 }
 
 // ContainerCheckpoint checkpoints a running container.
@@ -53,6 +62,16 @@ func (c *ContainerServer) ContainerCheckpoint(
 		return "", fmt.Errorf("container %s is not running", ctr.ID())
 	}
 
+	if opts.TargetImage != "" {
+		if _, err := reference.ParseNormalizedNamed(opts.TargetImage); err != nil {
+			return "", fmt.Errorf("invalid checkpoint image name %q: %w", opts.TargetImage, err)
+		}
+	}
+
+	if err := c.preDumpContainer(ctx, ctr, specgen.Config, opts.PreDumps); err != nil {
+		return "", err
+	}
+
 	// At this point the container needs to be paused. As we first checkpoint
 	// the processes in the container and the container will continue to run
 	// after checkpointing, there is a chance that the changed files we include
@@ -66,45 +85,92 @@ func (c *ContainerServer) ContainerCheckpoint(
 	if err = c.runtime.PauseContainer(ctx, ctr); err != nil {
 		return "", fmt.Errorf("failed to pause container %q before checkpointing: %w", ctr.ID(), err)
 	}
-	defer func() {
-		if err := c.runtime.UpdateContainerStatus(ctx, ctr); err != nil {
-			log.Errorf(ctx, "Failed to update container status: %q: %v", ctr.ID(), err)
-		}
-		if ctr.State().Status == oci.ContainerStatePaused {
-			err := c.runtime.UnpauseContainer(ctx, ctr)
-			if err != nil {
-				log.Errorf(ctx, "Failed to unpause container: %q: %v", ctr.ID(), err)
-			}
+	defer c.resumeCheckpointedContainer(ctx, ctr)
+
+	if err := c.checkpointPausedContainer(ctx, ctr, specgen.Config, opts); err != nil {
+		return "", err
+	}
+
+	return ctr.ID(), nil
+}
+
+// preDumpContainer writes the memory pre-dumps of a running container.
+func (c *ContainerServer) preDumpContainer(ctx context.Context, ctr *oci.Container, specgen *rspec.Spec, preDumps int) error {
+	if preDumps <= 0 {
+		return nil
+	}
+
+	// Remove the leftovers of a previously failed checkpoint
+	if err := os.RemoveAll(ctr.PreDumpPath()); err != nil {
+		return fmt.Errorf("failed to remove pre-dump directory of container %s: %w", ctr.ID(), err)
+	}
+	for i := 1; i <= preDumps; i++ {
+		if err := c.runtime.PreDumpContainer(ctx, ctr, specgen); err != nil {
+			return fmt.Errorf("failed to pre-dump container %s: %w", ctr.ID(), err)
 		}
-		// container state needs to be written _after_ unpausing
-		if err = c.ContainerStateToDisk(ctx, ctr); err != nil {
-			log.Warnf(ctx, "Unable to write containers %s state to disk: %v", ctr.ID(), err)
+		log.Debugf(ctx, "Wrote pre-dump %d of %d for container %s", i, preDumps, ctr.ID())
+	}
+	return nil
+}
+
+// resumeCheckpointedContainer unpauses the container after checkpointing it,
+// if it is still paused.
+func (c *ContainerServer) resumeCheckpointedContainer(ctx context.Context, ctr *oci.Container) {
+	if err := c.runtime.UpdateContainerStatus(ctx, ctr); err != nil {
+		log.Errorf(ctx, "Failed to update container status: %q: %v", ctr.ID(), err)
+	}
+	if ctr.State().Status == oci.ContainerStatePaused {
+		err := c.runtime.UnpauseContainer(ctx, ctr)
+		if err != nil {
+			log.Errorf(ctx, "Failed to unpause container: %q: %v", ctr.ID(), err)
 		}
-	}()
+	}
+	// container state needs to be written _after_ unpausing
+	if err := c.ContainerStateToDisk(ctx, ctr); err != nil {
+		log.Warnf(ctx, "Unable to write containers %s state to disk: %v", ctr.ID(), err)
+	}
+}
 
-	if opts.TargetFile != "" {
+// checkpointPausedContainer checkpoints an already paused container.
+func (c *ContainerServer) checkpointPausedContainer(
+	ctx context.Context,
+	ctr *oci.Container,
+	specgen *rspec.Spec,
+	opts *ContainerCheckpointOptions,
+) error {
+	if opts.TargetFile != "" || opts.TargetImage != "" {
 		if err := c.prepareCheckpointExport(ctr); err != nil {
-			return "", fmt.Errorf("failed to write config dumps for container %s: %w", ctr.ID(), err)
+			return fmt.Errorf("failed to write config dumps for container %s: %w", ctr.ID(), err)
 		}
 	}
 
-	if err := c.runtime.CheckpointContainer(ctx, ctr, specgen.Config, opts.KeepRunning); err != nil {
-		return "", fmt.Errorf("failed to checkpoint container %s: %w", ctr.ID(), err)
+	if err := c.runtime.CheckpointContainer(ctx, ctr, specgen, opts.KeepRunning); err != nil {
+		return fmt.Errorf("failed to checkpoint container %s: %w", ctr.ID(), err)
 	}
-	if opts.TargetFile != "" {
-		if err := c.exportCheckpoint(ctx, ctr, specgen.Config, opts.TargetFile); err != nil {
-			return "", fmt.Errorf("failed to write file system changes of container %s: %w", ctr.ID(), err)
+	if opts.TargetFile != "" || opts.TargetImage != "" {
+		if opts.TargetFile != "" {
+			if err := c.exportCheckpoint(ctx, ctr, specgen, opts.TargetFile); err != nil {
+				return fmt.Errorf("failed to write file system changes of container %s: %w", ctr.ID(), err)
+			}
+		}
+		if opts.TargetImage != "" {
+			if err := c.exportCheckpointImage(ctx, ctr, specgen, opts.TargetImage); err != nil {
+				return fmt.Errorf("failed to write checkpoint image of container %s: %w", ctr.ID(), err)
+			}
 		}
 		defer func() {
 			// clean up checkpoint directory
 			if err := os.RemoveAll(ctr.CheckpointPath()); err != nil {
 				log.Warnf(ctx, "Unable to remove checkpoint directory %s: %v", ctr.CheckpointPath(), err)
 			}
+			if err := os.RemoveAll(ctr.PreDumpPath()); err != nil {
+				log.Warnf(ctx, "Unable to remove pre-dump directory %s: %v", ctr.PreDumpPath(), err)
+			}
 		}()
 	}
 	if !opts.KeepRunning {
 		if err := c.storageRuntimeServer.StopContainer(ctx, ctr.ID()); err != nil {
-			return "", fmt.Errorf("failed to unmount container %s: %w", ctr.ID(), err)
+			return fmt.Errorf("failed to unmount container %s: %w", ctr.ID(), err)
 		}
 	}
 
@@ -123,7 +189,7 @@ func (c *ContainerServer) ContainerCheckpoint(
 		}
 	}
 
-	return ctr.ID(), nil
+	return nil
 }
 
 // Copied from libpod/diff.go
@@ -273,6 +339,20 @@ func (c *ContainerServer) prepareCheckpointExport(ctr *oci.Container) error {
 }
 
 func (c *ContainerServer) exportCheckpoint(ctx context.Context, ctr *oci.Container, specgen *rspec.Spec, export string) error {
+	// The resulting tar archive should not be readable by everyone as it contains
+	// every memory page of the checkpointed processes.
+	outFile, err := os.OpenFile(export, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return fmt.Errorf("error creating checkpoint export file %q: %w", export, err)
+	}
+	defer outFile.Close()
+
+	return c.writeCheckpointArchive(ctx, ctr, specgen, outFile)
+}
+
+// writeCheckpointArchive writes the checkpoint of the container as
+// uncompressed tar archive.
+func (c *ContainerServer) writeCheckpointArchive(ctx context.Context, ctr *oci.Container, specgen *rspec.Spec, out io.Writer) error {
 	id := ctr.ID()
 	dest := ctr.Dir()
 	log.Debugf(ctx, "Exporting checkpoint image of container %q to %q", id, dest)
@@ -281,6 +361,7 @@ func (c *ContainerServer) exportCheckpoint(ctx context.Context, ctr *oci.Contain
 		stats.StatsDump,
 		metadata.DumpLogFile,
 		metadata.CheckpointDirectory,
+		oci.CheckpointPreDumpDirectory,
 		metadata.ConfigDumpFile,
 		metadata.SpecDumpFile,
 		"bind.mounts",
@@ -333,15 +414,7 @@ func (c *ContainerServer) exportCheckpoint(ctx context.Context, ctr *oci.Contain
 		return fmt.Errorf("error reading checkpoint directory %q: %w", id, err)
 	}
 
-	// The resulting tar archive should not be readable by everyone as it contains
-	// every memory page of the checkpointed processes.
-	outFile, err := os.OpenFile(export, os.O_RDWR|os.O_CREATE, 0o600)
-	if err != nil {
-		return fmt.Errorf("error creating checkpoint export file %q: %w", export, err)
-	}
-	defer outFile.Close()
-
-	_, err = io.Copy(outFile, input)
+	_, err = io.Copy(out, input)
 	if err != nil {
 		return err
 	}
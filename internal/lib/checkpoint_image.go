@@ -0,0 +1,218 @@
+package lib
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/containers/image/v5/copy"
+	"github.com/containers/image/v5/docker/reference"
+	"github.com/containers/image/v5/oci/layout"
+	"github.com/containers/image/v5/signature"
+	istorage "github.com/containers/image/v5/storage"
+	"github.com/cri-o/cri-o/internal/log"
+	"github.com/cri-o/cri-o/internal/oci"
+	"github.com/cri-o/cri-o/pkg/annotations"
+	"github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
+	rspec "github.com/opencontainers/runtime-spec/specs-go"
+)
+
+// exportCheckpointImage writes the checkpoint of the container as single
+// layer OCI image into the local storage and tags it with the provided name.
+func (c *ContainerServer) exportCheckpointImage(ctx context.Context, ctr *oci.Container, specgen *rspec.Spec, name string) error {
+	named, err := reference.ParseNormalizedNamed(name)
+	if err != nil {
+		return fmt.Errorf("invalid checkpoint image name %q: %w", name, err)
+	}
+	named = reference.TagNameOnly(named)
+
+	dir, err := os.MkdirTemp("", "checkpoint-image")
+	if err != nil {
+		return fmt.Errorf("create checkpoint image directory: %w", err)
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			log.Warnf(ctx, "Unable to remove checkpoint image directory %s: %v", dir, err)
+		}
+	}()
+
+	if err := c.writeCheckpointImage(ctx, ctr, specgen, dir); err != nil {
+		return err
+	}
+
+	srcRef, err := layout.NewReference(dir, "")
+	if err != nil {
+		return err
+	}
+	destRef, err := istorage.Transport.NewStoreReference(c.store, named, "")
+	if err != nil {
+		return err
+	}
+	policyContext, err := signature.NewPolicyContext(&signature.Policy{
+		Default: signature.PolicyRequirements{signature.NewPRInsecureAcceptAnything()},
+	})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := policyContext.Destroy(); err != nil {
+			log.Warnf(ctx, "Unable to destroy policy context: %v", err)
+		}
+	}()
+
+	if _, err := copy.Image(ctx, policyContext, destRef, srcRef, &copy.Options{
+		DestinationCtx: c.config.SystemContext,
+	}); err != nil {
+		return fmt.Errorf("copy checkpoint image to storage: %w", err)
+	}
+
+	log.Infof(ctx, "Wrote checkpoint image %s of container %s", named, ctr.ID())
+	return nil
+}
+
+// writeCheckpointImage writes the checkpoint of the container as single
+// layer OCI image into the OCI layout directory.
+func (c *ContainerServer) writeCheckpointImage(ctx context.Context, ctr *oci.Container, specgen *rspec.Spec, dir string) error {
+	layer, err := writeLayoutBlob(dir, func(w io.Writer) error {
+		return c.writeCheckpointArchive(ctx, ctr, specgen, w)
+	})
+	if err != nil {
+		return fmt.Errorf("write checkpoint layer: %w", err)
+	}
+	layer.MediaType = imgspecv1.MediaTypeImageLayer
+
+	return writeCheckpointImageLayout(dir, layer, checkpointImageAnnotations(ctr))
+}
+
+// checkpointImageAnnotations returns the manifest annotations of a
+// checkpoint image for the container.
+func checkpointImageAnnotations(ctr *oci.Container) map[string]string {
+	res := checkpointAnnotations()
+	res[annotations.CheckpointAnnotationName] = ctr.Name()
+	res[annotations.CheckpointAnnotationRawImageName] = ctr.UserRequestedImage()
+	if id := ctr.ImageID(); id != nil {
+		res[annotations.CheckpointAnnotationRootfsImageID] = id.IDStringForOutOfProcessConsumptionOnly()
+	}
+	if imageName := ctr.ImageName(); imageName != nil {
+		res[annotations.CheckpointAnnotationRootfsImageName] = imageName.StringForOutOfProcessConsumptionOnly()
+	}
+	return res
+}
+
+// writeCheckpointImageLayout writes the config, manifest and index of a
+// single layer image into the OCI layout directory.
+func writeCheckpointImageLayout(dir string, layer imgspecv1.Descriptor, manifestAnnotations map[string]string) error {
+	created := time.Now().UTC()
+	config := imgspecv1.Image{
+		Created: &created,
+		Platform: imgspecv1.Platform{
+			Architecture: runtime.GOARCH,
+			OS:           runtime.GOOS,
+		},
+		RootFS: imgspecv1.RootFS{
+			Type:    "layers",
+			DiffIDs: []digest.Digest{layer.Digest},
+		},
+	}
+	configDesc, err := writeLayoutJSONBlob(dir, config)
+	if err != nil {
+		return fmt.Errorf("write checkpoint image config: %w", err)
+	}
+	configDesc.MediaType = imgspecv1.MediaTypeImageConfig
+
+	manifest := imgspecv1.Manifest{
+		Versioned:   specs.Versioned{SchemaVersion: 2},
+		MediaType:   imgspecv1.MediaTypeImageManifest,
+		Config:      configDesc,
+		Layers:      []imgspecv1.Descriptor{layer},
+		Annotations: manifestAnnotations,
+	}
+	manifestDesc, err := writeLayoutJSONBlob(dir, manifest)
+	if err != nil {
+		return fmt.Errorf("write checkpoint image manifest: %w", err)
+	}
+	manifestDesc.MediaType = imgspecv1.MediaTypeImageManifest
+
+	index := imgspecv1.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: imgspecv1.MediaTypeImageIndex,
+		Manifests: []imgspecv1.Descriptor{manifestDesc},
+	}
+	data, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, imgspecv1.ImageIndexFile), data, 0o600); err != nil {
+		return fmt.Errorf("write checkpoint image index: %w", err)
+	}
+
+	data, err = json.Marshal(imgspecv1.ImageLayout{Version: imgspecv1.ImageLayoutVersion})
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, imgspecv1.ImageLayoutFile), data, 0o600)
+}
+
+// writeLayoutJSONBlob writes the JSON representation of the value as blob
+// into the OCI layout directory.
+func writeLayoutJSONBlob(dir string, value any) (imgspecv1.Descriptor, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return imgspecv1.Descriptor{}, err
+	}
+	return writeLayoutBlob(dir, func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	})
+}
+
+// writeLayoutBlob writes the content provided by the write function as blob
+// into the OCI layout directory and returns its descriptor.
+func writeLayoutBlob(dir string, write func(io.Writer) error) (imgspecv1.Descriptor, error) {
+	blobDir := filepath.Join(dir, imgspecv1.ImageBlobsDir, digest.Canonical.String())
+	if err := os.MkdirAll(blobDir, 0o700); err != nil {
+		return imgspecv1.Descriptor{}, err
+	}
+
+	tmpFile, err := os.CreateTemp(blobDir, "blob")
+	if err != nil {
+		return imgspecv1.Descriptor{}, err
+	}
+	defer os.Remove(tmpFile.Name())
+	defer tmpFile.Close()
+
+	digester := digest.Canonical.Digester()
+	counter := &countingWriter{}
+	if err := write(io.MultiWriter(tmpFile, digester.Hash(), counter)); err != nil {
+		return imgspecv1.Descriptor{}, err
+	}
+	if err := tmpFile.Close(); err != nil {
+		return imgspecv1.Descriptor{}, err
+	}
+
+	desc := imgspecv1.Descriptor{
+		Digest: digester.Digest(),
+		Size:   counter.size,
+	}
+	if err := os.Rename(tmpFile.Name(), filepath.Join(blobDir, desc.Digest.Encoded())); err != nil {
+		return imgspecv1.Descriptor{}, err
+	}
+	return desc, nil
+}
+
+// countingWriter counts the bytes written to it.
+type countingWriter struct {
+	size int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.size += int64(len(p))
+	return len(p), nil
+}
@@ -0,0 +1,295 @@
+package lib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	metadata "github.com/checkpoint-restore/checkpointctl/lib"
+	criu "github.com/checkpoint-restore/go-criu/v7/utils"
+	"github.com/containers/storage/pkg/archive"
+	"github.com/cri-o/cri-o/internal/lib/sandbox"
+	"github.com/cri-o/cri-o/internal/log"
+	"github.com/cri-o/cri-o/internal/oci"
+	"github.com/cri-o/cri-o/internal/version"
+	"github.com/cri-o/cri-o/pkg/annotations"
+	rspec "github.com/opencontainers/runtime-spec/specs-go"
+	"github.com/opencontainers/runtime-tools/generate"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+	kubetypes "k8s.io/kubelet/pkg/types"
+)
+
+// PodCheckpointDumpFile is the file of a pod checkpoint archive containing
+// the pod metadata.
+const PodCheckpointDumpFile = "pod.dump"
+
+// PodCheckpointConfig is the pod metadata stored in a pod checkpoint archive.
+type PodCheckpointConfig struct {
+	// ID is the ID of the checkpointed pod sandbox.
+	ID string `json:"id"`
+	// RuntimeHandler is the runtime handler of the checkpointed pod sandbox.
+	RuntimeHandler string `json:"runtimeHandler,omitempty"`
+	// CheckpointedAt is the time the pod sandbox got checkpointed.
+	CheckpointedAt time.Time `json:"checkpointedAt"`
+	// Annotations contain the checkpoint annotations, like the CRI-O and
+	// CRIU version used for the checkpoint.
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Config is the configuration to recreate the pod sandbox.
+	Config *types.PodSandboxConfig `json:"config"`
+	// Containers are the checkpointed containers in creation order.
+	Containers []PodCheckpointContainer `json:"containers"`
+}
+
+// PodCheckpointContainer is a container stored in a pod checkpoint archive.
+type PodCheckpointContainer struct {
+	// ID is the ID of the checkpointed container.
+	ID string `json:"id"`
+	// Name is the name of the container from its metadata.
+	Name string `json:"name"`
+	// Archive is the container checkpoint archive within the pod archive.
+	Archive string `json:"archive"`
+}
+
+// PodCheckpoint checkpoints all running containers of a pod sandbox together
+// into a single archive written to the target file of the options.
+func (c *ContainerServer) PodCheckpoint(
+	ctx context.Context,
+	sb *sandbox.Sandbox,
+	opts *ContainerCheckpointOptions,
+) error {
+	if opts.TargetFile == "" {
+		return errors.New("pod checkpoint requires a target file")
+	}
+
+	ctrs := []*oci.Container{}
+	for _, ctr := range sb.Containers().List() {
+		if ctr.State().Status != oci.ContainerStateRunning {
+			log.Infof(ctx, "Skipping checkpoint of container %s which is not running", ctr.ID())
+			continue
+		}
+		ctrs = append(ctrs, ctr)
+	}
+	if len(ctrs) == 0 {
+		return fmt.Errorf("pod sandbox %s has no running containers", sb.ID())
+	}
+	sort.Slice(ctrs, func(i, j int) bool {
+		return ctrs[i].CreatedAt().Before(ctrs[j].CreatedAt())
+	})
+
+	specs := make(map[string]*rspec.Spec, len(ctrs))
+	for _, ctr := range ctrs {
+		specgen, err := generate.NewFromFile(filepath.Join(ctr.BundlePath(), "config.json"))
+		if err != nil {
+			return fmt.Errorf("not able to read config for container %q: %w", ctr.ID(), err)
+		}
+		specs[ctr.ID()] = specgen.Config
+	}
+
+	for _, ctr := range ctrs {
+		if err := c.preDumpContainer(ctx, ctr, specs[ctr.ID()], opts.PreDumps); err != nil {
+			return err
+		}
+	}
+
+	dir, err := os.MkdirTemp("", "pod-checkpoint")
+	if err != nil {
+		return fmt.Errorf("create pod checkpoint directory: %w", err)
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			log.Warnf(ctx, "Unable to remove pod checkpoint directory %s: %v", dir, err)
+		}
+	}()
+
+	// All containers get frozen before checkpointing the first one, to get a
+	// consistent state of the whole pod.
+	paused := []*oci.Container{}
+	defer func() {
+		for _, ctr := range paused {
+			c.resumeCheckpointedContainer(ctx, ctr)
+		}
+	}()
+	for _, ctr := range ctrs {
+		if err := c.runtime.PauseContainer(ctx, ctr); err != nil {
+			return fmt.Errorf("failed to pause container %q before checkpointing: %w", ctr.ID(), err)
+		}
+		paused = append(paused, ctr)
+	}
+
+	config := &PodCheckpointConfig{
+		ID:             sb.ID(),
+		RuntimeHandler: sb.RuntimeHandler(),
+		CheckpointedAt: time.Now(),
+		Annotations:    checkpointAnnotations(),
+		Config:         podSandboxConfig(sb),
+	}
+	for _, ctr := range ctrs {
+		archiveName := ctr.ID() + ".tar"
+		ctrOpts := &ContainerCheckpointOptions{
+			Keep:        opts.Keep,
+			KeepRunning: opts.KeepRunning,
+			TargetFile:  filepath.Join(dir, archiveName),
+		}
+		if err := c.checkpointPausedContainer(ctx, ctr, specs[ctr.ID()], ctrOpts); err != nil {
+			return err
+		}
+		config.Containers = append(config.Containers, PodCheckpointContainer{
+			ID:      ctr.ID(),
+			Name:    ctr.Metadata().Name,
+			Archive: archiveName,
+		})
+	}
+
+	if _, err := metadata.WriteJSONFile(config, dir, PodCheckpointDumpFile); err != nil {
+		return fmt.Errorf("write pod checkpoint config: %w", err)
+	}
+
+	return exportPodCheckpoint(dir, opts.TargetFile)
+}
+
+// SandboxConfig returns the configuration for restoring the pod sandbox. The
+// requested configuration takes precedence over the checkpointed one.
+func (p *PodCheckpointConfig) SandboxConfig(requested *types.PodSandboxConfig) *types.PodSandboxConfig {
+	config := &types.PodSandboxConfig{}
+	if p.Config != nil {
+		*config = *p.Config
+	}
+	if requested == nil {
+		requested = &types.PodSandboxConfig{}
+	}
+
+	if requested.Metadata != nil {
+		config.Metadata = requested.Metadata
+	}
+	if requested.Hostname != "" {
+		config.Hostname = requested.Hostname
+	}
+	if requested.LogDirectory != "" {
+		config.LogDirectory = requested.LogDirectory
+	}
+	if requested.DnsConfig != nil {
+		config.DnsConfig = requested.DnsConfig
+	}
+	if len(requested.PortMappings) > 0 {
+		config.PortMappings = requested.PortMappings
+	}
+
+	config.Labels = mergeMaps(config.Labels, requested.Labels)
+	config.Annotations = mergeMaps(config.Annotations, requested.Annotations)
+	delete(config.Annotations, annotations.CheckpointAnnotationPodArchive)
+
+	// The pod identity labels have to match the new metadata
+	if md := config.Metadata; md != nil {
+		for label, value := range map[string]string{
+			kubetypes.KubernetesPodNameLabel:      md.Name,
+			kubetypes.KubernetesPodNamespaceLabel: md.Namespace,
+			kubetypes.KubernetesPodUIDLabel:       md.Uid,
+		} {
+			if _, ok := requested.Labels[label]; ok {
+				continue
+			}
+			if _, ok := config.Labels[label]; ok {
+				config.Labels[label] = value
+			}
+		}
+	}
+
+	if requested.Linux != nil {
+		linux := *requested.Linux
+		if linux.SecurityContext.GetNamespaceOptions() == nil && config.Linux.GetSecurityContext().GetNamespaceOptions() != nil {
+			securityContext := &types.LinuxSandboxSecurityContext{}
+			if linux.SecurityContext != nil {
+				*securityContext = *linux.SecurityContext
+			}
+			securityContext.NamespaceOptions = config.Linux.SecurityContext.NamespaceOptions
+			linux.SecurityContext = securityContext
+		}
+		config.Linux = &linux
+	}
+
+	return config
+}
+
+// podSandboxConfig reconstructs the configuration of the pod sandbox.
+func podSandboxConfig(sb *sandbox.Sandbox) *types.PodSandboxConfig {
+	config := &types.PodSandboxConfig{
+		Metadata:     sb.Metadata(),
+		Hostname:     sb.Hostname(),
+		LogDirectory: sb.LogDir(),
+		DnsConfig:    sb.DNSConfig(),
+		Labels:       mergeMaps(nil, sb.Labels()),
+		Annotations:  mergeMaps(nil, sb.Annotations()),
+		Linux: &types.LinuxPodSandboxConfig{
+			CgroupParent: sb.CgroupParent(),
+			SecurityContext: &types.LinuxSandboxSecurityContext{
+				NamespaceOptions: sb.NamespaceOptions(),
+				Privileged:       sb.Privileged(),
+			},
+		},
+	}
+	for _, mapping := range sb.PortMappings() {
+		config.PortMappings = append(config.PortMappings, &types.PortMapping{
+			Protocol:      types.Protocol(types.Protocol_value[string(mapping.Protocol)]),
+			ContainerPort: mapping.ContainerPort,
+			HostPort:      mapping.HostPort,
+			HostIp:        mapping.HostIP,
+		})
+	}
+	return config
+}
+
+// checkpointAnnotations returns the versions used for creating a checkpoint.
+func checkpointAnnotations() map[string]string {
+	res := map[string]string{}
+	if info, err := version.Get(false); err == nil {
+		res[annotations.CheckpointAnnotationCRIOVersion] = info.Version
+	}
+	if criuVersion, err := criu.GetCriuVersion(); err == nil {
+		res[annotations.CheckpointAnnotationCriuVersion] = strconv.Itoa(criuVersion)
+	}
+	return res
+}
+
+// exportPodCheckpoint writes the content of the pod checkpoint directory into
+// the target archive.
+func exportPodCheckpoint(dir, target string) error {
+	input, err := archive.TarWithOptions(dir, &archive.TarOptions{
+		Compression:      archive.Uncompressed,
+		IncludeSourceDir: true,
+	})
+	if err != nil {
+		return fmt.Errorf("error reading pod checkpoint directory %q: %w", dir, err)
+	}
+	defer input.Close()
+
+	// The resulting tar archive should not be readable by everyone as it contains
+	// every memory page of the checkpointed processes.
+	outFile, err := os.OpenFile(target, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return fmt.Errorf("error creating pod checkpoint export file %q: %w", target, err)
+	}
+	defer outFile.Close()
+
+	if _, err := io.Copy(outFile, input); err != nil {
+		return fmt.Errorf("write pod checkpoint export file %q: %w", target, err)
+	}
+	return nil
+}
+
+// mergeMaps returns a copy of the base map updated by the overrides.
+func mergeMaps(base, overrides map[string]string) map[string]string {
+	res := make(map[string]string, len(base)+len(overrides))
+	for k, v := range base {
+		res[k] = v
+	}
+	for k, v := range overrides {
+		res[k] = v
+	}
+	return res
+}
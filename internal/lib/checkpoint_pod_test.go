@@ -0,0 +1,103 @@
+package lib_test
+
+import (
+	"github.com/cri-o/cri-o/internal/lib"
+	"github.com/cri-o/cri-o/pkg/annotations"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+	kubetypes "k8s.io/kubelet/pkg/types"
+)
+
+// The actual test suite
+var _ = t.Describe("PodCheckpointConfig", func() {
+	var sut *lib.PodCheckpointConfig
+
+	BeforeEach(func() {
+		sut = &lib.PodCheckpointConfig{
+			ID: "podID",
+			Config: &types.PodSandboxConfig{
+				Metadata: &types.PodSandboxMetadata{
+					Name:      "old",
+					Namespace: "default",
+					Uid:       "old-uid",
+				},
+				Hostname: "old-host",
+				Labels: map[string]string{
+					"app":                                 "batch",
+					kubetypes.KubernetesPodNameLabel:      "old",
+					kubetypes.KubernetesPodNamespaceLabel: "default",
+					kubetypes.KubernetesPodUIDLabel:       "old-uid",
+				},
+				Annotations: map[string]string{"key": "old"},
+				PortMappings: []*types.PortMapping{
+					{ContainerPort: 80, HostPort: 8080},
+				},
+				Linux: &types.LinuxPodSandboxConfig{
+					SecurityContext: &types.LinuxSandboxSecurityContext{
+						NamespaceOptions: &types.NamespaceOption{
+							Network: types.NamespaceMode_NODE,
+						},
+					},
+				},
+			},
+		}
+	})
+
+	t.Describe("SandboxConfig", func() {
+		It("should use the checkpointed config without request", func() {
+			// When
+			config := sut.SandboxConfig(nil)
+
+			// Then
+			Expect(config.Metadata.Name).To(Equal("old"))
+			Expect(config.Hostname).To(Equal("old-host"))
+			Expect(config.Labels).To(Equal(sut.Config.Labels))
+			Expect(config.PortMappings).To(HaveLen(1))
+			Expect(config.Linux.SecurityContext.NamespaceOptions.Network).To(Equal(types.NamespaceMode_NODE))
+		})
+
+		It("should prefer the requested config", func() {
+			// Given
+			requested := &types.PodSandboxConfig{
+				Metadata: &types.PodSandboxMetadata{
+					Name:      "new",
+					Namespace: "other",
+					Uid:       "new-uid",
+				},
+				Labels: map[string]string{"app": "restored"},
+				Annotations: map[string]string{
+					"key": "new",
+					annotations.CheckpointAnnotationPodArchive: "/pod.tar",
+				},
+				Linux: &types.LinuxPodSandboxConfig{CgroupParent: "parent"},
+			}
+
+			// When
+			config := sut.SandboxConfig(requested)
+
+			// Then
+			Expect(config.Metadata).To(Equal(requested.Metadata))
+			Expect(config.Hostname).To(Equal("old-host"))
+			Expect(config.Labels).To(Equal(map[string]string{
+				"app":                                 "restored",
+				kubetypes.KubernetesPodNameLabel:      "new",
+				kubetypes.KubernetesPodNamespaceLabel: "other",
+				kubetypes.KubernetesPodUIDLabel:       "new-uid",
+			}))
+			Expect(config.Annotations).To(Equal(map[string]string{"key": "new"}))
+			Expect(config.Linux.CgroupParent).To(Equal("parent"))
+			Expect(config.Linux.SecurityContext.NamespaceOptions.Network).To(Equal(types.NamespaceMode_NODE))
+		})
+
+		It("should not modify the checkpointed config", func() {
+			// When
+			sut.SandboxConfig(&types.PodSandboxConfig{
+				Labels: map[string]string{"app": "restored"},
+			})
+
+			// Then
+			Expect(sut.Config.Labels).To(HaveKeyWithValue("app", "batch"))
+		})
+	})
+})
@@ -2,18 +2,22 @@ package lib_test
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 
 	metadata "github.com/checkpoint-restore/checkpointctl/lib"
 	criu "github.com/checkpoint-restore/go-criu/v7/utils"
+	"github.com/containers/image/v5/oci/layout"
 	cstorage "github.com/containers/storage"
 	"github.com/containers/storage/pkg/archive"
 	"github.com/cri-o/cri-o/internal/lib"
 	"github.com/cri-o/cri-o/internal/oci"
+	"github.com/cri-o/cri-o/pkg/annotations"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	imgspecv1 "github.com/opencontainers/image-spec/specs-go/v1"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
 )
 
@@ -86,6 +90,32 @@ var _ = t.Describe("ContainerCheckpoint", func() {
 			Expect(res).To(Equal(config.ID))
 		})
 	})
+	t.Describe("ContainerCheckpoint", func() {
+		It("should fail with invalid target image", func() {
+			// Given
+			addContainerAndSandbox()
+			config := &metadata.ContainerConfig{
+				ID: containerID,
+			}
+
+			myContainer.SetState(&oci.ContainerState{
+				State: specs.State{Status: oci.ContainerStateRunning},
+			})
+			myContainer.SetSpec(&specs.Spec{Version: "1.0.0"})
+
+			// When
+			res, err := sut.ContainerCheckpoint(
+				context.Background(),
+				config,
+				&lib.ContainerCheckpointOptions{TargetImage: "Invalid:Name"},
+			)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(res).To(Equal(""))
+			Expect(err.Error()).To(ContainSubstring(`invalid checkpoint image name "Invalid:Name"`))
+		})
+	})
 	t.Describe("ContainerCheckpoint", func() {
 		It("should fail because runtime failure (/bin/false)", func() {
 			// Given
@@ -256,4 +286,38 @@ var _ = t.Describe("ContainerCheckpoint", func() {
 			Expect(err.Error()).To(Equal(`not able to read config for container "containerID": template configuration at config.json not found`))
 		})
 	})
+	t.Describe("WriteCheckpointImage", func() {
+		It("should write a manifest with the checkpoint annotations", func() {
+			// Given
+			addContainerAndSandbox()
+			dir := t.MustTempDir("checkpoint-image")
+			gomock.InOrder(
+				storeMock.EXPECT().Container(gomock.Any()).Return(&cstorage.Container{}, nil),
+				storeMock.EXPECT().Changes(gomock.Any(), gomock.Any()).Return([]archive.Change{}, nil),
+				storeMock.EXPECT().Mount(gomock.Any(), gomock.Any()).Return("/tmp/", nil),
+			)
+
+			// When
+			err := sut.WriteCheckpointImage(context.Background(), myContainer, &specs.Spec{Linux: &specs.Linux{}}, dir)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			ref, err := layout.NewReference(dir, "")
+			Expect(err).ToNot(HaveOccurred())
+			src, err := ref.NewImageSource(context.Background(), nil)
+			Expect(err).ToNot(HaveOccurred())
+			defer src.Close()
+			blob, manifestType, err := src.GetManifest(context.Background(), nil)
+			Expect(err).ToNot(HaveOccurred())
+			// The checkpoint image is only detected by the annotations of an
+			// OCI image manifest
+			Expect(manifestType).To(Equal(imgspecv1.MediaTypeImageManifest))
+			var manifest imgspecv1.Manifest
+			Expect(json.Unmarshal(blob, &manifest)).To(Succeed())
+			Expect(manifest.Annotations).To(HaveKeyWithValue(annotations.CheckpointAnnotationName, myContainer.Name()))
+			Expect(manifest.Annotations).To(HaveKey(annotations.CheckpointAnnotationRawImageName))
+			Expect(manifest.Layers).To(HaveLen(1))
+			Expect(manifest.Layers[0].MediaType).To(Equal(imgspecv1.MediaTypeImageLayer))
+		})
+	})
 })
@@ -7,7 +7,11 @@
 package lib
 
 import (
+	"context"
+
+	"github.com/cri-o/cri-o/internal/oci"
 	"github.com/cri-o/cri-o/internal/storage"
+	rspec "github.com/opencontainers/runtime-spec/specs-go"
 )
 
 // SetStorageRuntimeServer sets the runtime server for the ContainerServer
@@ -19,3 +23,9 @@ func (c *ContainerServer) SetStorageRuntimeServer(server storage.RuntimeServer)
 func (c *ContainerServer) SetStorageImageServer(server storage.ImageServer) {
 	c.storageImageServer = server
 }
+
+// WriteCheckpointImage writes the checkpoint image of the container into the
+// OCI layout directory instead of the local storage.
+func (c *ContainerServer) WriteCheckpointImage(ctx context.Context, ctr *oci.Container, specgen *rspec.Spec, dir string) error {
+	return c.writeCheckpointImage(ctx, ctr, specgen, dir)
+}
@@ -80,6 +80,7 @@ func (c *ContainerServer) ContainerRestore(
 			checkpoint := []string{
 				"artifacts",
 				metadata.CheckpointDirectory,
+				oci.CheckpointPreDumpDirectory,
 				metadata.DevShmCheckpointTar,
 				metadata.RootFsDiffTar,
 				metadata.DeletedFilesFile,
@@ -268,6 +269,10 @@ func (c *ContainerServer) ContainerRestore(
 		if err != nil {
 			log.Debugf(ctx, "Non-fatal: removal of checkpoint directory (%s) failed: %v", ctr.CheckpointPath(), err)
 		}
+		err = os.RemoveAll(ctr.PreDumpPath())
+		if err != nil {
+			log.Debugf(ctx, "Non-fatal: removal of pre-dump directory (%s) failed: %v", ctr.PreDumpPath(), err)
+		}
 		cleanup := [...]string{
 			metadata.RestoreLogFile,
 			metadata.DumpLogFile,
@@ -0,0 +1,283 @@
+package statsserver
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cri-o/cri-o/internal/config/cgmgr"
+	"github.com/cri-o/cri-o/internal/lib/sandbox"
+	"github.com/cri-o/cri-o/internal/oci"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+// baseLabelKeys are the labels every metric reported by CRI-O carries.
+// They follow the naming used by cAdvisor, so the kubelet can use them as a
+// drop-in replacement.
+var baseLabelKeys = []string{"id", "name", "image"}
+
+// Metric names, as reported through ListMetricDescriptors and ListPodSandboxMetrics.
+const (
+	metricCPUUsageSecondsTotal        = "container_cpu_usage_seconds_total"
+	metricCPUUserSecondsTotal         = "container_cpu_user_seconds_total"
+	metricCPUSystemSecondsTotal       = "container_cpu_system_seconds_total"
+	metricCPUCfsPeriodsTotal          = "container_cpu_cfs_periods_total"
+	metricCPUCfsThrottledPeriodsTotal = "container_cpu_cfs_throttled_periods_total"
+	metricCPUCfsThrottledSecondsTotal = "container_cpu_cfs_throttled_seconds_total"
+	metricMemoryUsageBytes            = "container_memory_usage_bytes"
+	metricMemoryWorkingSetBytes       = "container_memory_working_set_bytes"
+	metricMemoryRss                   = "container_memory_rss"
+	metricMemoryCache                 = "container_memory_cache"
+	metricMemorySwap                  = "container_memory_swap"
+	metricMemoryMaxUsageBytes         = "container_memory_max_usage_bytes"
+	metricMemoryKernelUsage           = "container_memory_kernel_usage"
+	metricMemoryFailuresTotal         = "container_memory_failures_total"
+	metricSpecMemoryLimitBytes        = "container_spec_memory_limit_bytes"
+	metricProcesses                   = "container_processes"
+	metricThreadsMax                  = "container_threads_max"
+	metricNetworkReceiveBytesTotal    = "container_network_receive_bytes_total"
+	metricNetworkReceiveErrorsTotal   = "container_network_receive_errors_total"
+	metricNetworkTransmitBytesTotal   = "container_network_transmit_bytes_total"
+	metricNetworkTransmitErrorsTotal  = "container_network_transmit_errors_total"
+	metricFsUsageBytes                = "container_fs_usage_bytes"
+	metricFsInodesUsed                = "container_fs_inodes_used"
+	metricHugetlbUsageBytes           = "container_hugetlb_usage_bytes"
+	metricHugetlbMaxUsageBytes        = "container_hugetlb_max_usage_bytes"
+	metricHugetlbFailuresTotal        = "container_hugetlb_failures_total"
+	metricFsReadsBytesTotal           = "container_fs_reads_bytes_total"
+	metricFsWritesBytesTotal          = "container_fs_writes_bytes_total"
+	metricFsReadsTotal                = "container_fs_reads_total"
+	metricFsWritesTotal               = "container_fs_writes_total"
+	metricPressureCPUWaiting          = "container_pressure_cpu_waiting_seconds_total"
+	metricPressureCPUStalled          = "container_pressure_cpu_stalled_seconds_total"
+	metricPressureMemoryWaiting       = "container_pressure_memory_waiting_seconds_total"
+	metricPressureMemoryStalled       = "container_pressure_memory_stalled_seconds_total"
+	metricPressureIOWaiting           = "container_pressure_io_waiting_seconds_total"
+	metricPressureIOStalled           = "container_pressure_io_stalled_seconds_total"
+)
+
+// withLabelKeys returns the base label keys extended by the provided ones.
+func withLabelKeys(keys ...string) []string {
+	return append(append([]string{}, baseLabelKeys...), keys...)
+}
+
+// metricDescriptors is the fixed catalog of metrics which can be returned by
+// ListPodSandboxMetrics.
+var metricDescriptors = []*types.MetricDescriptor{
+	// CPU
+	{Name: metricCPUUsageSecondsTotal, Help: "Cumulative cpu time consumed in seconds.", LabelKeys: baseLabelKeys},
+	{Name: metricCPUUserSecondsTotal, Help: "Cumulative user cpu time consumed in seconds.", LabelKeys: baseLabelKeys},
+	{Name: metricCPUSystemSecondsTotal, Help: "Cumulative system cpu time consumed in seconds.", LabelKeys: baseLabelKeys},
+	{Name: metricCPUCfsPeriodsTotal, Help: "Number of elapsed enforcement period intervals.", LabelKeys: baseLabelKeys},
+	{Name: metricCPUCfsThrottledPeriodsTotal, Help: "Number of throttled period intervals.", LabelKeys: baseLabelKeys},
+	{Name: metricCPUCfsThrottledSecondsTotal, Help: "Total time duration the container has been throttled.", LabelKeys: baseLabelKeys},
+	// Memory
+	{Name: metricMemoryUsageBytes, Help: "Current memory usage in bytes, including all memory regardless of when it was accessed.", LabelKeys: baseLabelKeys},
+	{Name: metricMemoryWorkingSetBytes, Help: "Current working set in bytes.", LabelKeys: baseLabelKeys},
+	{Name: metricMemoryRss, Help: "Size of RSS in bytes.", LabelKeys: baseLabelKeys},
+	{Name: metricMemoryCache, Help: "Number of bytes of page cache memory.", LabelKeys: baseLabelKeys},
+	{Name: metricMemorySwap, Help: "Container swap usage in bytes.", LabelKeys: baseLabelKeys},
+	{Name: metricMemoryMaxUsageBytes, Help: "Maximum memory usage recorded in bytes.", LabelKeys: baseLabelKeys},
+	{Name: metricMemoryKernelUsage, Help: "Size of kernel memory allocated in bytes.", LabelKeys: baseLabelKeys},
+	{Name: metricMemoryFailuresTotal, Help: "Cumulative count of memory allocation failures.", LabelKeys: withLabelKeys("failure_type", "scope")},
+	{Name: metricSpecMemoryLimitBytes, Help: "Memory limit for the container.", LabelKeys: baseLabelKeys},
+	// Pids
+	{Name: metricProcesses, Help: "Number of processes running inside the container.", LabelKeys: baseLabelKeys},
+	{Name: metricThreadsMax, Help: "Maximum number of threads allowed inside the container, infinity if value is zero.", LabelKeys: baseLabelKeys},
+	// Network
+	{Name: metricNetworkReceiveBytesTotal, Help: "Cumulative count of bytes received.", LabelKeys: withLabelKeys("interface")},
+	{Name: metricNetworkReceiveErrorsTotal, Help: "Cumulative count of errors encountered while receiving.", LabelKeys: withLabelKeys("interface")},
+	{Name: metricNetworkTransmitBytesTotal, Help: "Cumulative count of bytes transmitted.", LabelKeys: withLabelKeys("interface")},
+	{Name: metricNetworkTransmitErrorsTotal, Help: "Cumulative count of errors encountered while transmitting.", LabelKeys: withLabelKeys("interface")},
+	// Filesystem
+	{Name: metricFsUsageBytes, Help: "Number of bytes that are consumed by the container on this filesystem.", LabelKeys: withLabelKeys("device")},
+	{Name: metricFsInodesUsed, Help: "Number of inodes that are consumed by the container on this filesystem.", LabelKeys: withLabelKeys("device")},
+	// Hugetlb
+	{Name: metricHugetlbUsageBytes, Help: "Current hugetlb usage in bytes.", LabelKeys: withLabelKeys("pagesize")},
+	{Name: metricHugetlbMaxUsageBytes, Help: "Maximum hugetlb usage recorded in bytes.", LabelKeys: withLabelKeys("pagesize")},
+	{Name: metricHugetlbFailuresTotal, Help: "Cumulative count of hugetlb allocation failures.", LabelKeys: withLabelKeys("pagesize")},
+	// Block I/O
+	{Name: metricFsReadsBytesTotal, Help: "Cumulative count of bytes read.", LabelKeys: withLabelKeys("device")},
+	{Name: metricFsWritesBytesTotal, Help: "Cumulative count of bytes written.", LabelKeys: withLabelKeys("device")},
+	{Name: metricFsReadsTotal, Help: "Cumulative count of reads completed.", LabelKeys: withLabelKeys("device")},
+	{Name: metricFsWritesTotal, Help: "Cumulative count of writes completed.", LabelKeys: withLabelKeys("device")},
+	// Pressure
+	{Name: metricPressureCPUWaiting, Help: "Total time duration tasks in the container have waited due to CPU congestion.", LabelKeys: baseLabelKeys},
+	{Name: metricPressureCPUStalled, Help: "Total time duration no tasks in the container could make progress due to CPU congestion.", LabelKeys: baseLabelKeys},
+	{Name: metricPressureMemoryWaiting, Help: "Total time duration tasks in the container have waited due to memory congestion.", LabelKeys: baseLabelKeys},
+	{Name: metricPressureMemoryStalled, Help: "Total time duration no tasks in the container could make progress due to memory congestion.", LabelKeys: baseLabelKeys},
+	{Name: metricPressureIOWaiting, Help: "Total time duration tasks in the container have waited due to I/O congestion.", LabelKeys: baseLabelKeys},
+	{Name: metricPressureIOStalled, Help: "Total time duration no tasks in the container could make progress due to I/O congestion.", LabelKeys: baseLabelKeys},
+}
+
+// MetricDescriptors returns the catalog of metrics which are reported for
+// pod sandboxes and containers.
+func (ss *StatsServer) MetricDescriptors() []*types.MetricDescriptor {
+	return metricDescriptors
+}
+
+// metricsBuilder collects metrics sharing the same timestamp and base labels.
+type metricsBuilder struct {
+	timestamp   int64
+	labelValues []string
+	metrics     []*types.Metric
+}
+
+func newMetricsBuilder(timestamp int64, id, name, image string) *metricsBuilder {
+	return &metricsBuilder{
+		timestamp:   timestamp,
+		labelValues: []string{id, name, image},
+	}
+}
+
+// add appends a new metric. The extraLabelValues have to match the additional
+// label keys of the corresponding descriptor.
+func (b *metricsBuilder) add(name string, metricType types.MetricType, value uint64, extraLabelValues ...string) {
+	labelValues := make([]string, 0, len(b.labelValues)+len(extraLabelValues))
+	labelValues = append(labelValues, b.labelValues...)
+	labelValues = append(labelValues, extraLabelValues...)
+	b.metrics = append(b.metrics, &types.Metric{
+		Name:        name,
+		Timestamp:   b.timestamp,
+		MetricType:  metricType,
+		LabelValues: labelValues,
+		Value:       &types.UInt64Value{Value: value},
+	})
+}
+
+// addCgroupStats appends the CPU, memory, pids, hugetlb, block I/O and pressure metrics of the provided cgroup stats.
+func (b *metricsBuilder) addCgroupStats(stats *cgmgr.CgroupStats, scope string) {
+	if stats == nil {
+		return
+	}
+	if cpu := stats.CPU; cpu != nil {
+		b.add(metricCPUUsageSecondsTotal, types.MetricType_COUNTER, cpu.TotalUsageNano/uint64(time.Second))
+		b.add(metricCPUUserSecondsTotal, types.MetricType_COUNTER, cpu.UsageInUsermode/uint64(time.Second))
+		b.add(metricCPUSystemSecondsTotal, types.MetricType_COUNTER, cpu.UsageInKernelmode/uint64(time.Second))
+		b.add(metricCPUCfsPeriodsTotal, types.MetricType_COUNTER, cpu.ThrottlingActivePeriods)
+		b.add(metricCPUCfsThrottledPeriodsTotal, types.MetricType_COUNTER, cpu.ThrottledPeriods)
+		b.add(metricCPUCfsThrottledSecondsTotal, types.MetricType_COUNTER, cpu.ThrottledTime/uint64(time.Second))
+	}
+	if mem := stats.Memory; mem != nil {
+		b.add(metricMemoryUsageBytes, types.MetricType_GAUGE, mem.Usage)
+		b.add(metricMemoryWorkingSetBytes, types.MetricType_GAUGE, mem.WorkingSetBytes)
+		b.add(metricMemoryRss, types.MetricType_GAUGE, mem.RssBytes)
+		b.add(metricMemoryCache, types.MetricType_GAUGE, mem.Cache)
+		b.add(metricMemorySwap, types.MetricType_GAUGE, mem.SwapUsage)
+		b.add(metricMemoryMaxUsageBytes, types.MetricType_GAUGE, mem.MaxUsage)
+		b.add(metricMemoryKernelUsage, types.MetricType_GAUGE, mem.KernelUsage)
+		b.add(metricMemoryFailuresTotal, types.MetricType_COUNTER, mem.PageFaults, "pgfault", scope)
+		b.add(metricMemoryFailuresTotal, types.MetricType_COUNTER, mem.MajorPageFaults, "pgmajfault", scope)
+		b.add(metricSpecMemoryLimitBytes, types.MetricType_GAUGE, mem.Limit)
+	}
+	if pids := stats.Pid; pids != nil {
+		b.add(metricProcesses, types.MetricType_GAUGE, pids.Current)
+		b.add(metricThreadsMax, types.MetricType_GAUGE, pids.Limit)
+	}
+	for pageSize, hugetlb := range stats.Hugetlb {
+		b.add(metricHugetlbUsageBytes, types.MetricType_GAUGE, hugetlb.Usage, pageSize)
+		b.add(metricHugetlbMaxUsageBytes, types.MetricType_GAUGE, hugetlb.MaxUsage, pageSize)
+		b.add(metricHugetlbFailuresTotal, types.MetricType_COUNTER, hugetlb.Failcnt, pageSize)
+	}
+	for i := range stats.Blkio {
+		blkio := &stats.Blkio[i]
+		device := fmt.Sprintf("%d:%d", blkio.Major, blkio.Minor)
+		b.add(metricFsReadsBytesTotal, types.MetricType_COUNTER, blkio.ReadBytes, device)
+		b.add(metricFsWritesBytesTotal, types.MetricType_COUNTER, blkio.WriteBytes, device)
+		b.add(metricFsReadsTotal, types.MetricType_COUNTER, blkio.ReadOps, device)
+		b.add(metricFsWritesTotal, types.MetricType_COUNTER, blkio.WriteOps, device)
+	}
+	if pressure := stats.Pressure; pressure != nil {
+		b.addPSIStats(pressure.CPU, metricPressureCPUWaiting, metricPressureCPUStalled)
+		b.addPSIStats(pressure.Memory, metricPressureMemoryWaiting, metricPressureMemoryStalled)
+		b.addPSIStats(pressure.IO, metricPressureIOWaiting, metricPressureIOStalled)
+	}
+}
+
+// addPSIStats appends the total stall times of a single resource.
+func (b *metricsBuilder) addPSIStats(psi *cgmgr.PSIStats, waiting, stalled string) {
+	if psi == nil {
+		return
+	}
+	b.add(waiting, types.MetricType_COUNTER, psi.Some.Total/uint64(time.Second/time.Microsecond))
+	b.add(stalled, types.MetricType_COUNTER, psi.Full.Total/uint64(time.Second/time.Microsecond))
+}
+
+// addNetworkUsage appends the network metrics for every interface of the provided usage.
+func (b *metricsBuilder) addNetworkUsage(network *types.NetworkUsage) {
+	if network == nil {
+		return
+	}
+	ifaces := network.Interfaces
+	if network.DefaultInterface != nil {
+		ifaces = append([]*types.NetworkInterfaceUsage{network.DefaultInterface}, ifaces...)
+	}
+	for _, iface := range ifaces {
+		b.add(metricNetworkReceiveBytesTotal, types.MetricType_COUNTER, iface.RxBytes.GetValue(), iface.Name)
+		b.add(metricNetworkReceiveErrorsTotal, types.MetricType_COUNTER, iface.RxErrors.GetValue(), iface.Name)
+		b.add(metricNetworkTransmitBytesTotal, types.MetricType_COUNTER, iface.TxBytes.GetValue(), iface.Name)
+		b.add(metricNetworkTransmitErrorsTotal, types.MetricType_COUNTER, iface.TxErrors.GetValue(), iface.Name)
+	}
+}
+
+// addFilesystemUsage appends the filesystem metrics of the provided writable layer usage.
+func (b *metricsBuilder) addFilesystemUsage(usage *types.FilesystemUsage) {
+	if usage == nil || usage.UsedBytes == nil {
+		return
+	}
+	device := usage.GetFsId().GetMountpoint()
+	b.add(metricFsUsageBytes, types.MetricType_GAUGE, usage.UsedBytes.GetValue(), device)
+	b.add(metricFsInodesUsed, types.MetricType_GAUGE, usage.InodesUsed.GetValue(), device)
+}
+
+// sandboxMetrics creates the pod level metrics from the pod cgroup stats and network usage.
+func sandboxMetrics(sb *sandbox.Sandbox, cgstats *cgmgr.CgroupStats, network *types.NetworkUsage, timestamp int64) *types.PodSandboxMetrics {
+	b := newMetricsBuilder(timestamp, sb.ID(), sb.Name(), "")
+	b.addCgroupStats(cgstats, "pod")
+	b.addNetworkUsage(network)
+	return &types.PodSandboxMetrics{
+		PodSandboxId: sb.ID(),
+		Metrics:      b.metrics,
+	}
+}
+
+// containerMetrics creates the container level metrics from the container cgroup stats
+// and the already gathered writable layer usage.
+func containerMetrics(ctr *oci.Container, cgstats *cgmgr.CgroupStats, writableLayer *types.FilesystemUsage) *types.ContainerMetrics {
+	image := ctr.UserRequestedImage()
+	if imageName := ctr.ImageName(); imageName != nil {
+		image = imageName.StringForOutOfProcessConsumptionOnly()
+	}
+	b := newMetricsBuilder(cgstats.SystemNano, ctr.ID(), ctr.Name(), image)
+	b.addCgroupStats(cgstats, "container")
+	b.addFilesystemUsage(writableLayer)
+	return &types.ContainerMetrics{
+		ContainerId: ctr.ID(),
+		Metrics:     b.metrics,
+	}
+}
+
+// MetricsForPodSandboxes returns the metrics for the given list of sandboxes.
+func (ss *StatsServer) MetricsForPodSandboxes(sboxes []*sandbox.Sandbox) []*types.PodSandboxMetrics {
+	ss.mutex.Lock()
+	defer ss.mutex.Unlock()
+	metrics := make([]*types.PodSandboxMetrics, 0, len(sboxes))
+	for _, sb := range sboxes {
+		if m := ss.metricsForPodSandbox(sb); m != nil {
+			metrics = append(metrics, m)
+		}
+	}
+	return metrics
+}
+
+// metricsForPodSandbox is an internal, non-locking function that returns
+// (and occasionally gathers) the metrics for the given sandbox.
+func (ss *StatsServer) metricsForPodSandbox(sb *sandbox.Sandbox) *types.PodSandboxMetrics {
+	if ss.collectionPeriod != 0 {
+		if m, ok := ss.sboxMetrics[sb.ID()]; ok {
+			return m
+		}
+	}
+	// On demand collection or cache miss, the update populates the metrics as well.
+	ss.updateSandbox(sb)
+	return ss.sboxMetrics[sb.ID()]
+}
@@ -22,6 +22,7 @@ type StatsServer struct {
 	collectionPeriod time.Duration
 	sboxStats        map[string]*types.PodSandboxStats
 	ctrStats         map[string]*types.ContainerStats
+	sboxMetrics      map[string]*types.PodSandboxMetrics
 	parentServerIface
 	mutex sync.Mutex
 }
@@ -45,6 +46,7 @@ func New(cs parentServerIface) *StatsServer {
 		collectionPeriod:  time.Duration(cs.Config().StatsCollectionPeriod) * time.Second,
 		sboxStats:         make(map[string]*types.PodSandboxStats),
 		ctrStats:          make(map[string]*types.ContainerStats),
+		sboxMetrics:       make(map[string]*types.PodSandboxMetrics),
 		parentServerIface: cs,
 	}
 	go ss.updateLoop()
@@ -171,6 +173,7 @@ func (ss *StatsServer) RemoveStatsForSandbox(sb *sandbox.Sandbox) {
 	ss.mutex.Lock()
 	defer ss.mutex.Unlock()
 	delete(ss.sboxStats, sb.ID())
+	delete(ss.sboxMetrics, sb.ID())
 }
 
 // StatsForContainer returns the stats for the given container
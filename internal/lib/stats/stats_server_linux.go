@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/containernetworking/plugins/pkg/ns"
 	"github.com/cri-o/cri-o/internal/config/cgmgr"
@@ -31,17 +32,19 @@ func (ss *StatsServer) updateSandbox(sb *sandbox.Sandbox) *types.PodSandboxStats
 		Linux: &types.LinuxPodSandboxStats{},
 	}
 
-	if cgstats, err := ss.Config().CgroupManager().SandboxCgroupStats(sb.CgroupParent(), sb.ID()); err != nil {
+	sbCgstats, err := ss.Config().CgroupManager().SandboxCgroupStats(sb.CgroupParent(), sb.ID())
+	if err != nil {
 		logrus.Errorf("Error getting sandbox stats %s: %v", sb.ID(), err)
 	} else {
-		sandboxStats.Linux.Cpu = criCPUStats(cgstats.CPU, cgstats.SystemNano)
-		sandboxStats.Linux.Memory = criMemStats(cgstats.Memory, cgstats.SystemNano)
-		sandboxStats.Linux.Process = criProcessStats(cgstats.Pid, cgstats.SystemNano)
+		sandboxStats.Linux.Cpu = criCPUStats(sbCgstats.CPU, sbCgstats.SystemNano)
+		sandboxStats.Linux.Memory = criMemStats(sbCgstats.Memory, sbCgstats.SystemNano)
+		sandboxStats.Linux.Process = criProcessStats(sbCgstats.Pid, sbCgstats.SystemNano)
 	}
 
 	if err := ss.populateNetworkUsage(sandboxStats, sb); err != nil {
 		logrus.Errorf("Error adding network stats for sandbox %s: %v", sb.ID(), err)
 	}
+	podMetrics := sandboxMetrics(sb, sbCgstats, sandboxStats.Linux.Network, time.Now().UnixNano())
 	containerStats := make([]*types.ContainerStats, 0, len(sb.Containers().List()))
 	for _, c := range sb.Containers().List() {
 		if c.StateNoLock().Status == oci.ContainerStateStopped {
@@ -58,12 +61,14 @@ func (ss *StatsServer) updateSandbox(sb *sandbox.Sandbox) *types.PodSandboxStats
 			updateUsageNanoCores(oldcStats.Cpu, cStats.Cpu)
 		}
 		containerStats = append(containerStats, cStats)
+		podMetrics.ContainerMetrics = append(podMetrics.ContainerMetrics, containerMetrics(c, cgstats, cStats.WritableLayer))
 	}
 	sandboxStats.Linux.Containers = containerStats
 	if old, ok := ss.sboxStats[sb.ID()]; ok {
 		updateUsageNanoCores(old.Linux.Cpu, sandboxStats.Linux.Cpu)
 	}
 	ss.sboxStats[sb.ID()] = sandboxStats
+	ss.sboxMetrics[sb.ID()] = podMetrics
 	return sandboxStats
 }
 
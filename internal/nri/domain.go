@@ -5,6 +5,7 @@ import (
 	"errors"
 	"sync"
 
+	config "github.com/cri-o/cri-o/internal/config/nri"
 	"github.com/cri-o/cri-o/internal/log"
 	"github.com/sirupsen/logrus"
 
@@ -33,8 +34,10 @@ type Domain interface {
 	// UpdateContainer applies an NRI container update request.
 	UpdateContainer(context.Context, *nri.ContainerUpdate) error
 
-	// EvictContainer evicts the requested container.
-	EvictContainer(context.Context, *nri.ContainerEviction) error
+	// EvictContainer evicts the requested container on behalf of the named
+	// plugin, which is empty if the requesting plugin is unknown. Stopping the
+	// container may continue after EvictContainer returned.
+	EvictContainer(context.Context, string, *nri.ContainerEviction) error
 }
 
 // SetDomain registers the domain with NRI.
@@ -89,11 +92,11 @@ func (t *domainTable) updateContainers(ctx context.Context, updates []*nri.Conta
 	return nil, nil
 }
 
-func (t *domainTable) evictContainers(ctx context.Context, evict []*nri.ContainerEviction) ([]*nri.ContainerEviction, error) {
+func (t *domainTable) evictContainers(ctx context.Context, evict []*nri.ContainerEviction, plugins *config.EvictingPlugins) ([]*nri.ContainerEviction, error) {
 	var failed []*nri.ContainerEviction
 
 	for _, e := range evict {
-		err := t.domain.EvictContainer(ctx, e)
+		err := t.domain.EvictContainer(ctx, plugins.Get(e.ContainerId), e)
 		if err != nil {
 			log.Errorf(ctx, "NRI eviction of container %s failed: %v", e.ContainerId, err)
 			failed = append(failed, e)
@@ -298,12 +298,13 @@ func (l *local) UpdateContainer(ctx context.Context, pod PodSandbox, ctr Contain
 		LinuxResources: req,
 	}
 
+	ctx, plugins := config.WithEvictingPlugins(ctx)
 	response, err := l.nri.UpdateContainer(ctx, request)
 	if err != nil {
 		return nil, err
 	}
 
-	_, err = l.evictContainers(ctx, response.Evict)
+	_, err = l.evictContainers(ctx, response.Evict, plugins)
 	if err != nil {
 		return nil, err
 	}
@@ -454,8 +455,8 @@ func (l *local) applyUpdates(ctx context.Context, updates []*nri.ContainerUpdate
 	return failed, err
 }
 
-func (l *local) evictContainers(ctx context.Context, evict []*nri.ContainerEviction) ([]*nri.ContainerEviction, error) {
-	failed, err := domains.evictContainers(ctx, evict)
+func (l *local) evictContainers(ctx context.Context, evict []*nri.ContainerEviction, plugins *config.EvictingPlugins) ([]*nri.ContainerEviction, error) {
+	failed, err := domains.evictContainers(ctx, evict, plugins)
 	return failed, err
 }
 
@@ -72,6 +72,7 @@ type Container struct {
 	restore               bool
 	restoreArchivePath    string
 	restoreStorageImageID *storage.StorageImageID
+	preDumps              int
 	resources             *types.ContainerResources
 	runtimePath           string // runtime path for a given platform
 }
@@ -110,6 +111,22 @@ type ContainerState struct {
 	// This is used to track whether the PID we have stored
 	// is the same as the corresponding PID on the host.
 	InitStartTime string `json:"initStartTime,omitempty"`
+	// PodOOMKilled is set if the container got killed because the pod
+	// cgroup ran out of memory.
+	PodOOMKilled bool `json:"podOOMKilled,omitempty"`
+	// PodOOMCount is the number of pod cgroup OOM events when the container
+	// got started.
+	PodOOMCount uint64 `json:"podOOMCount,omitempty"`
+	// StartFailed is set if the container could not be started.
+	StartFailed bool `json:"startFailed,omitempty"`
+	// StopTimedOut is set if the container got killed because it did not
+	// stop within the timeout after receiving its stop signal.
+	StopTimedOut bool `json:"stopTimedOut,omitempty"`
+	// EvictedBy is the NRI plugin which evicted the container.
+	EvictedBy string `json:"evictedBy,omitempty"`
+	// EvictionReason is the reason the NRI plugin provided for evicting the
+	// container.
+	EvictionReason string `json:"evictionReason,omitempty"`
 	// Checkpoint/Restore related states
 	CheckpointedAt time.Time `json:"checkpointedTime,omitempty"`
 }
@@ -398,6 +415,18 @@ func (c *Container) CheckpointPath() string {
 	return filepath.Join(c.dir, metadata.CheckpointDirectory)
 }
 
+// PreDumpPath returns the path to the directory containing the memory
+// pre-dumps of the container, one sub directory per iteration.
+func (c *Container) PreDumpPath() string {
+	return filepath.Join(c.dir, CheckpointPreDumpDirectory)
+}
+
+// PreDumps returns the number of memory pre-dumps written since the last
+// checkpoint of the container.
+func (c *Container) PreDumps() int {
+	return c.preDumps
+}
+
 // Metadata returns the metadata of the container.
 func (c *Container) Metadata() *types.ContainerMetadata {
 	return c.criContainer.Metadata
@@ -455,17 +484,54 @@ func (c *Container) Created() bool {
 	return c.created
 }
 
-// SetStartFailed sets the container state appropriately after a start failure
+// SetStartFailed sets the container state appropriately after a start failure.
+// The error output of the runtime is recorded if the runtime failed to start
+// the container.
 func (c *Container) SetStartFailed(err error) {
 	c.opLock.Lock()
 	defer c.opLock.Unlock()
 	// adjust finished and started times
 	c.state.Finished, c.state.Started = c.state.Created, c.state.Created
+	c.state.StartFailed = true
 	if err != nil {
 		c.state.Error = err.Error()
+		var cmdErr *runtimeCmdError
+		if errors.As(err, &cmdErr) && strings.TrimSpace(cmdErr.stderr) != "" {
+			c.state.Error = strings.TrimSpace(cmdErr.stderr)
+		}
 	}
 }
 
+// SetPodOOMCount stores the number of OOM events of the pod cgroup when the
+// container got started.
+func (c *Container) SetPodOOMCount(count uint64) {
+	c.opLock.Lock()
+	defer c.opLock.Unlock()
+	c.state.PodOOMCount = count
+}
+
+// SetPodOOMKilled marks an OOM killed container as killed because its pod ran
+// out of memory, if the pod cgroup invoked the OOM killer more often than when
+// the container got started. It returns whether the container got marked.
+func (c *Container) SetPodOOMKilled(count uint64) bool {
+	c.opLock.Lock()
+	defer c.opLock.Unlock()
+	if !c.state.OOMKilled || count <= c.state.PodOOMCount {
+		return false
+	}
+	c.state.PodOOMKilled = true
+	return true
+}
+
+// SetEvicted records that the container got evicted by the NRI plugin for
+// the provided reason.
+func (c *Container) SetEvicted(plugin, reason string) {
+	c.opLock.Lock()
+	defer c.opLock.Unlock()
+	c.state.EvictedBy = plugin
+	c.state.EvictionReason = reason
+}
+
 // Description returns a description for the container
 func (c *Container) Description() string {
 	return fmt.Sprintf("%s/%s/%s", c.Labels()[kubeletTypes.KubernetesPodNamespaceLabel], c.Labels()[kubeletTypes.KubernetesPodNameLabel], c.Labels()[kubeletTypes.KubernetesContainerNameLabel])
@@ -136,6 +136,45 @@ var _ = t.Describe("Container", func() {
 
 		// Then
 		Expect(sut.State().Error).To(Equal(err.Error()))
+		Expect(sut.State().StartFailed).To(BeTrue())
+	})
+
+	It("should mark an OOM killed container as pod OOM killed", func() {
+		// Given
+		sut.SetPodOOMCount(1)
+		sut.State().OOMKilled = true
+
+		// When
+		marked := sut.SetPodOOMKilled(2)
+
+		// Then
+		Expect(marked).To(BeTrue())
+		Expect(sut.State().PodOOMKilled).To(BeTrue())
+	})
+
+	It("should not mark a container as pod OOM killed without OOM kill", func() {
+		// Given
+		sut.SetPodOOMCount(1)
+
+		// When
+		marked := sut.SetPodOOMKilled(2)
+
+		// Then
+		Expect(marked).To(BeFalse())
+		Expect(sut.State().PodOOMKilled).To(BeFalse())
+	})
+
+	It("should not mark a container as pod OOM killed without new pod OOM events", func() {
+		// Given
+		sut.SetPodOOMCount(2)
+		sut.State().OOMKilled = true
+
+		// When
+		marked := sut.SetPodOOMKilled(2)
+
+		// Then
+		Expect(marked).To(BeFalse())
+		Expect(sut.State().PodOOMKilled).To(BeFalse())
 	})
 
 	It("should succeed to set restore", func() {
@@ -7,6 +7,7 @@
 package oci
 
 import (
+	"github.com/containerd/containerd/api/runtime/task/v2"
 	"github.com/cri-o/cri-o/pkg/config"
 )
 
@@ -42,3 +43,17 @@ func NewRuntimeOCI(r *Runtime, handler *config.RuntimeHandler) RuntimeOCI {
 		},
 	}
 }
+
+type RuntimeVM struct {
+	*runtimeVM
+}
+
+// NewRuntimeVM creates a VM runtime which uses the provided shim task service.
+func NewRuntimeVM(handler *config.RuntimeHandler, exitsPath string, taskService task.TaskService) RuntimeVM {
+	r, ok := newRuntimeVM(handler, exitsPath).(*runtimeVM)
+	if !ok {
+		panic("unexpected VM runtime implementation")
+	}
+	r.task = taskService
+	return RuntimeVM{runtimeVM: r}
+}
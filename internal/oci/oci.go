@@ -32,6 +32,9 @@ const (
 	ContainerStateStopped = "stopped"
 	// ContainerCreateTimeout represents the value of container creating timeout
 	ContainerCreateTimeout = 240 * time.Second
+	// CheckpointPreDumpDirectory is the directory of the memory pre-dumps
+	// within the container and checkpoint archive directory
+	CheckpointPreDumpDirectory = "pre-dump"
 
 	// killContainerTimeout is the timeout that we wait for the container to
 	// be SIGKILLed.
@@ -75,9 +78,10 @@ type RuntimeImpl interface {
 	AttachContainer(context.Context, *Container, io.Reader, io.WriteCloser, io.WriteCloser,
 		bool, <-chan remotecommand.TerminalSize) error
 	PortForwardContainer(context.Context, *Container, string,
-		int32, io.ReadWriteCloser) error
+		int32, types.Protocol, io.ReadWriteCloser) error
 	ReopenContainerLog(context.Context, *Container) error
 	CheckpointContainer(context.Context, *Container, *rspec.Spec, bool) error
+	PreDumpContainer(context.Context, *Container, *rspec.Spec) error
 	RestoreContainer(context.Context, *Container, string, string) error
 }
 
@@ -439,7 +443,7 @@ func (r *Runtime) AttachContainer(ctx context.Context, c *Container, inputStream
 }
 
 // PortForwardContainer forwards the specified port provides statistics of a container.
-func (r *Runtime) PortForwardContainer(ctx context.Context, c *Container, netNsPath string, port int32, stream io.ReadWriteCloser) error {
+func (r *Runtime) PortForwardContainer(ctx context.Context, c *Container, netNsPath string, port int32, protocol types.Protocol, stream io.ReadWriteCloser) error {
 	ctx, span := log.StartSpan(ctx)
 	defer span.End()
 	impl, err := r.RuntimeImpl(c)
@@ -447,7 +451,7 @@ func (r *Runtime) PortForwardContainer(ctx context.Context, c *Container, netNsP
 		return err
 	}
 
-	return impl.PortForwardContainer(ctx, c, netNsPath, port, stream)
+	return impl.PortForwardContainer(ctx, c, netNsPath, port, protocol, stream)
 }
 
 // ReopenContainerLog reopens the log file of a container.
@@ -484,6 +488,16 @@ func (r *Runtime) CheckpointContainer(ctx context.Context, c *Container, specgen
 	return impl.CheckpointContainer(ctx, c, specgen, leaveRunning)
 }
 
+// PreDumpContainer writes a memory pre-dump of a running container.
+func (r *Runtime) PreDumpContainer(ctx context.Context, c *Container, specgen *rspec.Spec) error {
+	impl, err := r.RuntimeImpl(c)
+	if err != nil {
+		return err
+	}
+
+	return impl.PreDumpContainer(ctx, c, specgen)
+}
+
 // RestoreContainer restores a container.
 func (r *Runtime) RestoreContainer(ctx context.Context, c *Container, cgroupParent, mountLabel string) error {
 	impl, err := r.RuntimeImpl(c)
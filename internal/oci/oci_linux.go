@@ -33,7 +33,7 @@ func (r *runtimeOCI) createContainerPlatform(c *Container, cgroupParent string,
 	}
 
 	// Mutate our newly created spec to find the customizations that are needed for conmon
-	if err := r.config.Workloads.MutateSpecGivenAnnotations(InfraContainerName, g, c.Annotations()); err != nil {
+	if err := r.config.Workloads.MutateSpecGivenAnnotations(InfraContainerName, g, c.Annotations(), r.config.Rdt(), r.config.BlockIO()); err != nil {
 		return err
 	}
 
@@ -3,6 +3,7 @@ package oci_test
 import (
 	"context"
 	"os"
+	"path/filepath"
 
 	criu "github.com/checkpoint-restore/go-criu/v7/utils"
 	"github.com/cri-o/cri-o/internal/oci"
@@ -230,6 +231,64 @@ var _ = t.Describe("Oci", func() {
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(Equal("configured runtime does not support checkpoint/restore"))
 		})
+		It("PreDumpContainer should succeed", func() {
+			if err := criu.CheckForCriu(criu.PodCriuVersion); err != nil {
+				Skip("Check CRIU: " + err.Error())
+			}
+			// Given
+			beforeEach()
+			defer os.RemoveAll("dump.log")
+			defer os.RemoveAll(myContainer.PreDumpPath())
+			config.Runtimes["runc"] = &libconfig.RuntimeHandler{
+				RuntimePath: "/bin/true",
+			}
+
+			specgen := &specs.Spec{
+				Version: "1.0.0",
+				Linux: &specs.Linux{
+					MountLabel: "",
+				},
+			}
+			// When
+			err := sut.PreDumpContainer(context.Background(), myContainer, specgen)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(myContainer.PreDumps()).To(Equal(1))
+			Expect(filepath.Join(myContainer.PreDumpPath(), "1")).To(BeADirectory())
+
+			// When
+			err = sut.CheckpointContainer(context.Background(), myContainer, specgen, true)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(myContainer.PreDumps()).To(BeZero())
+		})
+		It("PreDumpContainer should fail", func() {
+			if err := criu.CheckForCriu(criu.PodCriuVersion); err != nil {
+				Skip("Check CRIU: " + err.Error())
+			}
+			// Given
+			defer os.RemoveAll("dump.log")
+			beforeEach()
+			config.Runtimes["runc"] = &libconfig.RuntimeHandler{
+				RuntimePath: "/bin/false",
+			}
+
+			specgen := &specs.Spec{
+				Version: "1.0.0",
+				Linux: &specs.Linux{
+					MountLabel: "",
+				},
+			}
+			// When
+			err := sut.PreDumpContainer(context.Background(), myContainer, specgen)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("configured runtime does not support checkpoint/restore"))
+			Expect(myContainer.PreDumps()).To(BeZero())
+		})
 		It("RestoreContainer should fail with destination sandbox detection", func() {
 			if err := criu.CheckForCriu(criu.PodCriuVersion); err != nil {
 				Skip("Check CRIU: " + err.Error())
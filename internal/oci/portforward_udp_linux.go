@@ -0,0 +1,133 @@
+package oci
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// udpFrameHeaderSize is the size of the big endian length prefix of every
+// datagram relayed over a port forward stream.
+const udpFrameHeaderSize = 2
+
+// udpResponseTimeout is the time to wait for further response datagrams
+// after the client closed its side of the stream.
+const udpResponseTimeout = time.Second
+
+// relayUDP relays datagrams between the connected UDP socket and the port
+// forward stream. Datagrams read from the stream are expected to be framed
+// by a two byte big endian length prefix, and datagrams received from the
+// socket get framed the same way.
+func relayUDP(ctx context.Context, conn net.Conn, stream io.ReadWriter, debug func(string, ...interface{})) error {
+	errCh := make(chan error, 1)
+	respCh := make(chan error, 1)
+
+	// Copy datagrams from the namespace socket to the client stream
+	go func() {
+		debug("copy datagrams from container to client")
+		buf := make([]byte, math.MaxUint16)
+		for {
+			n, err := conn.Read(buf)
+			if err != nil {
+				var netErr net.Error
+				if errors.As(err, &netErr) && netErr.Timeout() {
+					err = nil
+				}
+				respCh <- err
+				return
+			}
+			if err := writeUDPFrame(stream, buf[:n]); err != nil {
+				respCh <- err
+				return
+			}
+		}
+	}()
+
+	// Copy datagrams from the client stream to the namespace socket
+	go func() {
+		debug("copy datagrams from client to container")
+		buf := make([]byte, math.MaxUint16)
+		for {
+			n, err := readUDPFrame(stream, buf)
+			if err != nil {
+				if errors.Is(err, io.EOF) {
+					err = nil
+				}
+				errCh <- err
+				return
+			}
+			if _, err := conn.Write(buf[:n]); err != nil {
+				errCh <- err
+				return
+			}
+		}
+	}()
+
+	var errFwd error
+	select {
+	case errFwd = <-errCh:
+		debug("stop forwarding datagrams from client: %v", errFwd)
+	case errFwd = <-respCh:
+		debug("stop forwarding datagrams from container: %v", errFwd)
+		return errFwd
+	case <-ctx.Done():
+		debug("cancelled: %v", ctx.Err())
+		return ctx.Err()
+	}
+
+	// UDP has no notion of a closed connection, so give the container a
+	// chance to respond to the last datagrams.
+	if err := conn.SetReadDeadline(time.Now().Add(udpResponseTimeout)); err != nil {
+		return err
+	}
+	select {
+	case e := <-respCh:
+		if errFwd == nil {
+			errFwd = e
+		}
+		debug("stopped forwarding datagrams in both directions")
+	case <-ctx.Done():
+		debug("cancelled: %v", ctx.Err())
+		errFwd = ctx.Err()
+	}
+
+	return errFwd
+}
+
+// readUDPFrame reads a single framed datagram from the reader into the
+// buffer and returns its size.
+func readUDPFrame(r io.Reader, buf []byte) (int, error) {
+	var header [udpFrameHeaderSize]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, err
+	}
+	size := int(binary.BigEndian.Uint16(header[:]))
+	if size > len(buf) {
+		return 0, fmt.Errorf("datagram of %d bytes exceeds buffer of %d bytes", size, len(buf))
+	}
+	if _, err := io.ReadFull(r, buf[:size]); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return 0, err
+	}
+	return size, nil
+}
+
+// writeUDPFrame writes the datagram prefixed by its length to the writer.
+func writeUDPFrame(w io.Writer, datagram []byte) error {
+	if len(datagram) > math.MaxUint16 {
+		return fmt.Errorf("datagram of %d bytes is too large", len(datagram))
+	}
+	frame := make([]byte, udpFrameHeaderSize+len(datagram))
+	binary.BigEndian.PutUint16(frame, uint16(len(datagram)))
+	copy(frame[udpFrameHeaderSize:], datagram)
+	_, err := w.Write(frame)
+	return err
+}
@@ -16,6 +16,7 @@ import (
 	"time"
 
 	metadata "github.com/checkpoint-restore/checkpointctl/lib"
+	"github.com/checkpoint-restore/go-criu/v7/stats"
 	criu "github.com/checkpoint-restore/go-criu/v7/utils"
 	conmonconfig "github.com/containers/conmon/runner/config"
 	"github.com/containers/podman/v4/pkg/checkpoint/crutils"
@@ -919,6 +920,8 @@ func (r *runtimeOCI) StopLoopForContainer(c *Container, bm kwait.BackoffManager)
 
 		case <-time.After(time.Until(targetTime)):
 			log.Warnf(ctx, "Stopping container %s with stop signal timed out. Killing...", c.ID())
+			// The stop loop holds the opLock, so the state can be updated directly
+			c.state.StopTimedOut = true
 
 			if _, err := r.runtimeCmd("kill", c.ID(), "KILL"); err != nil {
 				log.Errorf(ctx, "Killing container %v failed: %v", c.ID(), err)
@@ -1376,12 +1379,30 @@ func (r *runtimeOCI) runtimeCmd(args ...string) (string, error) {
 
 	err := cmd.Run()
 	if err != nil {
-		return "", fmt.Errorf("`%v %v` failed: %v %v: %w", r.handler.RuntimePath, strings.Join(runtimeArgs, " "), stderr.String(), stdout.String(), err)
+		return "", &runtimeCmdError{
+			stderr: stderr.String(),
+			err:    fmt.Errorf("`%v %v` failed: %v %v: %w", r.handler.RuntimePath, strings.Join(runtimeArgs, " "), stderr.String(), stdout.String(), err),
+		}
 	}
 
 	return stdout.String(), nil
 }
 
+// runtimeCmdError is returned if a runtime command fails and keeps the error
+// output of the runtime.
+type runtimeCmdError struct {
+	stderr string
+	err    error
+}
+
+func (e *runtimeCmdError) Error() string {
+	return e.err.Error()
+}
+
+func (e *runtimeCmdError) Unwrap() error {
+	return e.err
+}
+
 func (r *runtimeOCI) defaultRuntimeArgs() []string {
 	args := []string{rootFlag, r.root}
 	if r.config.CgroupManager().IsSystemd() {
@@ -1431,6 +1452,16 @@ func (r *runtimeOCI) CheckpointContainer(ctx context.Context, c *Container, spec
 	if leaveRunning {
 		args = append(args, "--leave-running")
 	}
+	if parent := lastPreDumpPath(c); parent != "" {
+		// Only the memory pages changed since the last pre-dump get written
+		parentPath, err := filepath.Rel(imagePath, parent)
+		if err != nil {
+			return err
+		}
+		args = append(args, "--parent-path", parentPath)
+	}
+	// The next checkpoint has to start with a new series of pre-dumps
+	defer func() { c.preDumps = 0 }()
 
 	args = append(args, c.ID())
 
@@ -1438,6 +1469,7 @@ func (r *runtimeOCI) CheckpointContainer(ctx context.Context, c *Container, spec
 	if err != nil {
 		return fmt.Errorf("running %q %q failed: %w", runtimePath, args, err)
 	}
+	recordCheckpointStats(ctx, c, workPath, "final", "final")
 
 	c.SetCheckpointedAt(time.Now())
 	if !leaveRunning {
@@ -1449,6 +1481,106 @@ func (r *runtimeOCI) CheckpointContainer(ctx context.Context, c *Container, spec
 	return nil
 }
 
+// PreDumpContainer writes a memory pre-dump of a running container. The
+// following pre-dumps and the final checkpoint only contain the memory pages
+// changed since the previous pre-dump.
+func (r *runtimeOCI) PreDumpContainer(ctx context.Context, c *Container, specgen *rspec.Spec) error {
+	c.opLock.Lock()
+	defer c.opLock.Unlock()
+	runtimePath := c.RuntimePathForPlatform(r)
+	if err := r.checkpointRestoreSupported(runtimePath); err != nil {
+		return err
+	}
+
+	if err := crutils.CRCreateFileWithLabel(
+		c.Dir(),
+		metadata.DumpLogFile,
+		specgen.Linux.MountLabel,
+	); err != nil {
+		return err
+	}
+
+	parent := lastPreDumpPath(c)
+	iteration := c.preDumps + 1
+	workPath := c.Dir()
+	imagePath := preDumpIterationPath(c, iteration)
+	if err := os.MkdirAll(imagePath, 0o700); err != nil {
+		return fmt.Errorf("create pre-dump directory: %w", err)
+	}
+
+	log.Debugf(ctx, "Writing pre-dump %d to %s", iteration, imagePath)
+	args := []string{
+		"checkpoint",
+		"--pre-dump",
+		"--image-path",
+		imagePath,
+		"--work-path",
+		workPath,
+	}
+	if parent != "" {
+		parentPath, err := filepath.Rel(imagePath, parent)
+		if err != nil {
+			return err
+		}
+		args = append(args, "--parent-path", parentPath)
+	}
+	args = append(args, c.ID())
+
+	if _, err := r.runtimeCmd(args...); err != nil {
+		return fmt.Errorf("running %q %q failed: %w", runtimePath, args, err)
+	}
+	recordCheckpointStats(ctx, c, workPath, strconv.Itoa(iteration), "pre-dump")
+
+	c.preDumps = iteration
+	return nil
+}
+
+// preDumpIterationPath returns the path of a single pre-dump iteration.
+func preDumpIterationPath(c *Container, iteration int) string {
+	return filepath.Join(c.PreDumpPath(), strconv.Itoa(iteration))
+}
+
+// lastPreDumpPath returns the path of the last pre-dump of the container or
+// an empty string if there is none.
+func lastPreDumpPath(c *Container) string {
+	if c.preDumps == 0 {
+		return ""
+	}
+	path := preDumpIterationPath(c, c.preDumps)
+	if _, err := os.Stat(path); err != nil {
+		// The pre-dumps got removed in the meantime
+		c.preDumps = 0
+		return ""
+	}
+	return path
+}
+
+// recordCheckpointStats logs and records the CRIU statistics of the last
+// dump written to the work path. The iteration only gets logged, while the
+// metrics are recorded by the kind of the dump.
+func recordCheckpointStats(ctx context.Context, c *Container, workPath, iteration, dump string) {
+	dir, err := os.Open(workPath)
+	if err != nil {
+		log.Debugf(ctx, "Unable to open checkpoint work path %s: %v", workPath, err)
+		return
+	}
+	defer dir.Close()
+
+	dumpStats, err := stats.CriuGetDumpStats(dir)
+	if err != nil {
+		log.Debugf(ctx, "Unable to read checkpoint statistics of container %s: %v", c.ID(), err)
+		return
+	}
+
+	frozen := time.Duration(dumpStats.GetFrozenTime()) * time.Microsecond
+	log.Infof(ctx,
+		"Checkpoint iteration %s of container %s wrote %d memory pages, processes were frozen for %s",
+		iteration, c.ID(), dumpStats.GetPagesWritten(), frozen,
+	)
+	metrics.Instance().MetricContainersCheckpointPagesWrittenObserve(dump, dumpStats.GetPagesWritten())
+	metrics.Instance().MetricContainersCheckpointFrozenObserve(dump, frozen)
+}
+
 // RestoreContainer restores a container.
 func (r *runtimeOCI) RestoreContainer(ctx context.Context, c *Container, cgroupParent, mountLabel string) error {
 	if err := r.checkpointRestoreSupported(c.RuntimePathForPlatform(r)); err != nil {
@@ -9,14 +9,32 @@ import (
 	"github.com/containernetworking/plugins/pkg/ns"
 	"github.com/cri-o/cri-o/internal/log"
 	"golang.org/x/net/context"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
 )
 
 // PortForwardContainer forwards the specified port into the provided container.
-func (r *runtimeOCI) PortForwardContainer(ctx context.Context, c *Container, netNsPath string, port int32, stream io.ReadWriteCloser) error {
+func (r *runtimeOCI) PortForwardContainer(ctx context.Context, c *Container, netNsPath string, port int32, protocol types.Protocol, stream io.ReadWriteCloser) error {
 	ctx, span := log.StartSpan(ctx)
 	defer span.End()
+	return portForwardInNetNS(ctx, c, netNsPath, port, protocol, stream)
+}
+
+// portForwardInNetNS forwards the specified port by connecting to it from
+// within the network namespace of the container. UDP datagrams are relayed
+// framed over the stream.
+func portForwardInNetNS(ctx context.Context, c *Container, netNsPath string, port int32, protocol types.Protocol, stream io.ReadWriteCloser) error {
+	var network string
+	switch protocol {
+	case types.Protocol_TCP:
+		network = "tcp"
+	case types.Protocol_UDP:
+		network = "udp"
+	default:
+		return fmt.Errorf("unsupported port forward protocol %s", protocol)
+	}
+
 	log.Infof(ctx,
-		"Starting port forward for %s in network namespace %s", c.ID(), netNsPath,
+		"Starting %s port forward for %s in network namespace %s", protocol, c.ID(), netNsPath,
 	)
 
 	// Adapted reference implementation:
@@ -36,20 +54,24 @@ func (r *runtimeOCI) PortForwardContainer(ctx context.Context, c *Container, net
 		// xref https://github.com/golang/go/issues/44922
 		var d net.Dialer
 		d.FallbackDelay = -1
-		conn, err := d.Dial("tcp", fmt.Sprintf("localhost:%d", port))
+		conn, err := d.Dial(network, fmt.Sprintf("localhost:%d", port))
 		if err != nil {
 			return fmt.Errorf("failed to connect to localhost:%d inside namespace %s: %w", port, c.ID(), err)
 		}
 		defer conn.Close()
 
-		errCh := make(chan error, 2)
-
 		debug := func(format string, args ...interface{}) {
 			log.Debugf(ctx, fmt.Sprintf(
-				"PortForward (id: %s, port: %d): %s", c.ID(), port, format,
+				"PortForward (id: %s, port: %d, protocol: %s): %s", c.ID(), port, protocol, format,
 			), args...)
 		}
 
+		if protocol == types.Protocol_UDP {
+			return relayUDP(ctx, conn, stream, debug)
+		}
+
+		errCh := make(chan error, 2)
+
 		// Copy from the namespace port connection to the client stream
 		go func() {
 			debug("copy data from container to client")
@@ -1,8 +1,11 @@
 package oci_test
 
 import (
+	"bytes"
 	"context"
+	"encoding/binary"
 	"math/rand"
+	"net"
 	"os"
 	"os/exec"
 	"time"
@@ -15,6 +18,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	kwait "k8s.io/apimachinery/pkg/util/wait"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
 	kclock "k8s.io/utils/clock"
 )
 
@@ -229,6 +233,84 @@ var _ = t.Describe("Oci", func() {
 			})
 		}
 	})
+	Context("PortForwardContainer", func() {
+		var (
+			runtime oci.RuntimeOCI
+			conn    net.PacketConn
+			port    int32
+		)
+		BeforeEach(func() {
+			cfg, err := libconfig.DefaultConfig()
+			Expect(err).ToNot(HaveOccurred())
+			r, err := oci.New(cfg)
+			Expect(err).ToNot(HaveOccurred())
+			runtime = oci.NewRuntimeOCI(r, &libconfig.RuntimeHandler{})
+
+			// An UDP server which echoes the datagrams in upper case
+			conn, err = net.ListenPacket("udp", "localhost:0")
+			Expect(err).ToNot(HaveOccurred())
+			port = int32(conn.LocalAddr().(*net.UDPAddr).Port)
+			go func() {
+				buf := make([]byte, 1024)
+				for {
+					n, addr, err := conn.ReadFrom(buf)
+					if err != nil {
+						return
+					}
+					conn.WriteTo(bytes.ToUpper(buf[:n]), addr) //nolint:errcheck
+				}
+			}()
+		})
+		AfterEach(func() {
+			conn.Close()
+		})
+
+		udpFrames := func(datagrams ...string) []byte {
+			res := []byte{}
+			for _, datagram := range datagrams {
+				res = binary.BigEndian.AppendUint16(res, uint16(len(datagram)))
+				res = append(res, datagram...)
+			}
+			return res
+		}
+
+		It("should relay framed UDP datagrams", func() {
+			// Given
+			stream := &portForwardStream{Reader: bytes.NewReader(udpFrames("hello", "", "world"))}
+
+			// When
+			err := runtime.PortForwardContainer(context.Background(), getTestContainer(),
+				"/proc/self/ns/net", port, types.Protocol_UDP, stream)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stream.String()).To(Equal(string(udpFrames("HELLO", "", "WORLD"))))
+		})
+
+		It("should fail on a truncated UDP frame", func() {
+			// Given
+			stream := &portForwardStream{Reader: bytes.NewReader(udpFrames("hello")[:4])}
+
+			// When
+			err := runtime.PortForwardContainer(context.Background(), getTestContainer(),
+				"/proc/self/ns/net", port, types.Protocol_UDP, stream)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail on an unsupported protocol", func() {
+			// Given
+			stream := &portForwardStream{Reader: bytes.NewReader(nil)}
+
+			// When
+			err := runtime.PortForwardContainer(context.Background(), getTestContainer(),
+				"/proc/self/ns/net", port, types.Protocol_SCTP, stream)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+	})
 })
 
 func containerIgnoreSignalCmdrunnerMock(sleepProcess *exec.Cmd, runner *runnerMock.MockCommandRunner) {
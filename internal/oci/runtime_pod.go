@@ -162,6 +162,14 @@ func (r *runtimePod) CheckpointContainer(
 	return r.oci.CheckpointContainer(ctx, c, specgen, leaveRunning)
 }
 
+func (r *runtimePod) PreDumpContainer(
+	ctx context.Context,
+	c *Container,
+	specgen *rspec.Spec,
+) error {
+	return r.oci.PreDumpContainer(ctx, c, specgen)
+}
+
 func (r *runtimePod) RestoreContainer(
 	ctx context.Context,
 	c *Container,
@@ -287,8 +295,8 @@ func (r *runtimePod) AttachContainer(ctx context.Context, c *Container, inputStr
 	})
 }
 
-func (r *runtimePod) PortForwardContainer(ctx context.Context, c *Container, netNsPath string, port int32, stream io.ReadWriteCloser) error {
-	return r.oci.PortForwardContainer(ctx, c, netNsPath, port, stream)
+func (r *runtimePod) PortForwardContainer(ctx context.Context, c *Container, netNsPath string, port int32, protocol types.Protocol, stream io.ReadWriteCloser) error {
+	return r.oci.PortForwardContainer(ctx, c, netNsPath, port, protocol, stream)
 }
 
 func (r *runtimePod) ReopenContainerLog(ctx context.Context, c *Container) error {
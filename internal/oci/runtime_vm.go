@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -43,6 +44,7 @@ import (
 	"github.com/sirupsen/logrus"
 	"golang.org/x/net/context"
 	"golang.org/x/sys/unix"
+	"google.golang.org/protobuf/encoding/protowire"
 	anypb "google.golang.org/protobuf/types/known/anypb"
 	"k8s.io/client-go/tools/remotecommand"
 	types "k8s.io/cri-api/pkg/apis/runtime/v1"
@@ -78,6 +80,12 @@ const (
 	execTimeout = -2
 )
 
+// execIODrainTimeout is the maximum time to wait for the output of an exec
+// process after it exited. The shim closes the output fifos once the process
+// exited, but copying their remaining content to the exec streams may still
+// be in progress, so removing the process right away truncates the output.
+const execIODrainTimeout = 5 * time.Second
+
 // newRuntimeVM creates a new runtimeVM instance
 func newRuntimeVM(handler *config.RuntimeHandler, exitsPath string) RuntimeImpl {
 	logrus.Debug("oci.newRuntimeVM() start")
@@ -463,7 +471,7 @@ func (r *runtimeVM) execContainerCommon(ctx context.Context, c *Container, cmd [
 		}
 	}()
 
-	execIO.Attach(cio.AttachOptions{
+	attachDone := execIO.Attach(cio.AttachOptions{
 		Stdin:     stdin,
 		Stdout:    stdout,
 		Stderr:    stderr,
@@ -569,6 +577,8 @@ func (r *runtimeVM) execContainerCommon(ctx context.Context, c *Container, cmd [
 	}
 
 	if err == nil {
+		waitForExecIO(ctx, c, execID, attachDone)
+
 		// Delete the process
 		if err := r.remove(c.ID(), execID); err != nil {
 			log.Debugf(ctx, "Unable to remove container %s: %v", c.ID(), err)
@@ -578,6 +588,18 @@ func (r *runtimeVM) execContainerCommon(ctx context.Context, c *Container, cmd [
 	return exitCode, err
 }
 
+// waitForExecIO waits for the output of an exited exec process to be copied
+// to its streams before the fifos get closed, but at most execIODrainTimeout.
+// This applies to all exec callers, like ExecSyncContainer and the port
+// forwarding inside the guest.
+func waitForExecIO(ctx context.Context, c *Container, execID string, attachDone <-chan struct{}) {
+	select {
+	case <-attachDone:
+	case <-time.After(execIODrainTimeout):
+		log.Warnf(ctx, "Timed out waiting for the output of exec %s in container %s", execID, c.ID())
+	}
+}
+
 // UpdateContainer updates container resources
 func (r *runtimeVM) UpdateContainer(ctx context.Context, c *Container, res *rspec.LinuxResources) error {
 	log.Debugf(ctx, "RuntimeVM.UpdateContainer() start")
@@ -652,6 +674,8 @@ func (r *runtimeVM) StopContainer(ctx context.Context, c *Container, timeout int
 			return nil
 		}
 		log.Warnf(ctx, "%v", err)
+		// The opLock is held, so the state can be updated directly
+		c.state.StopTimedOut = true
 	}
 
 	sig = syscall.SIGKILL
@@ -875,15 +899,36 @@ func (r *runtimeVM) createContainerIO(ctx context.Context, c *Container, cioOpts
 		}
 	}()
 
-	f, err := os.OpenFile(c.LogPath(), os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	stdout, stderr, err := r.openContainerLog(ctx, c)
 	if err != nil {
 		return nil, err
 	}
 
+	containerIO.AddOutput(c.LogPath(), stdout, stderr)
+	containerIO.Pipe()
+
+	r.Lock()
+	r.ctrs[c.ID()] = containerInfo{
+		cio: containerIO,
+	}
+	r.Unlock()
+
+	return containerIO, nil
+}
+
+// openContainerLog opens the log file of the container and returns the CRI
+// loggers writing the stdout and stderr streams into it. The file gets closed
+// once both loggers are closed.
+func (r *runtimeVM) openContainerLog(ctx context.Context, c *Container) (stdout, stderr io.WriteCloser, _ error) {
+	f, err := os.OpenFile(c.LogPath(), os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	var stdoutCh, stderrCh <-chan struct{}
 	wc := cioutil.NewSerialWriteCloser(f)
-	stdout, stdoutCh := cio.NewCRILogger(c.LogPath(), wc, cio.Stdout, -1)
-	stderr, stderrCh := cio.NewCRILogger(c.LogPath(), wc, cio.Stderr, -1)
+	stdout, stdoutCh = cio.NewCRILogger(c.LogPath(), wc, cio.Stdout, -1)
+	stderr, stderrCh = cio.NewCRILogger(c.LogPath(), wc, cio.Stderr, -1)
 
 	go func() {
 		if stdoutCh != nil {
@@ -896,16 +941,7 @@ func (r *runtimeVM) createContainerIO(ctx context.Context, c *Container, cioOpts
 		f.Close()
 	}()
 
-	containerIO.AddOutput(c.LogPath(), stdout, stderr)
-	containerIO.Pipe()
-
-	r.Lock()
-	r.ctrs[c.ID()] = containerInfo{
-		cio: containerIO,
-	}
-	r.Unlock()
-
-	return containerIO, nil
+	return stdout, stderr, nil
 }
 
 // PauseContainer pauses a container.
@@ -1026,10 +1062,38 @@ func metricsV1ToCgroupStats(ctx context.Context, m *cgroupsV1.Metrics) *cgmgr.Cg
 			Current: m.Pids.Current,
 			Limit:   m.Pids.Limit,
 		},
+		Hugetlb:    metricsV1ToHugetlbStats(m.Hugetlb),
+		Blkio:      metricsV1ToBlkioStats(m.Blkio),
 		SystemNano: time.Now().UnixNano(),
 	}
 }
 
+func metricsV1ToHugetlbStats(hugetlb []*cgroupsV1.HugetlbStat) map[string]cgmgr.HugetlbStats {
+	res := make(map[string]cgmgr.HugetlbStats, len(hugetlb))
+	for _, stat := range hugetlb {
+		res[stat.Pagesize] = cgmgr.HugetlbStats{
+			Usage:    stat.Usage,
+			MaxUsage: stat.Max,
+			Failcnt:  stat.Failcnt,
+		}
+	}
+	return res
+}
+
+func metricsV1ToBlkioStats(blkio *cgroupsV1.BlkIOStat) []cgmgr.BlkioDeviceStats {
+	aggregator := &cgmgr.BlkioStatsAggregator{}
+	if blkio == nil {
+		return aggregator.Stats()
+	}
+	for _, entry := range blkio.IoServiceBytesRecursive {
+		aggregator.AddServiceBytes(entry.Major, entry.Minor, entry.Op, entry.Value)
+	}
+	for _, entry := range blkio.IoServicedRecursive {
+		aggregator.AddServiced(entry.Major, entry.Minor, entry.Op, entry.Value)
+	}
+	return aggregator.Stats()
+}
+
 func metricsV2ToCgroupStats(ctx context.Context, m *cgroupsV2.Metrics) *cgmgr.CgroupStats {
 	var (
 		memLimit        uint64
@@ -1078,10 +1142,151 @@ func metricsV2ToCgroupStats(ctx context.Context, m *cgroupsV2.Metrics) *cgmgr.Cg
 			Current: m.Pids.Current,
 			Limit:   m.Pids.Limit,
 		},
+		Hugetlb:    metricsV2ToHugetlbStats(m.Hugetlb),
+		Blkio:      metricsV2ToBlkioStats(m.Io),
+		Pressure:   metricsV2ToPressureStats(m),
 		SystemNano: time.Now().UnixNano(),
 	}
 }
 
+func metricsV2ToHugetlbStats(hugetlb []*cgroupsV2.HugeTlbStat) map[string]cgmgr.HugetlbStats {
+	res := make(map[string]cgmgr.HugetlbStats, len(hugetlb))
+	for _, stat := range hugetlb {
+		// Max is the limit, cgroup v2 does not record the maximum usage
+		res[stat.Pagesize] = cgmgr.HugetlbStats{
+			Usage: stat.Current,
+		}
+	}
+	return res
+}
+
+func metricsV2ToBlkioStats(io *cgroupsV2.IOStat) []cgmgr.BlkioDeviceStats {
+	res := []cgmgr.BlkioDeviceStats{}
+	if io == nil {
+		return res
+	}
+	for _, entry := range io.Usage {
+		res = append(res, cgmgr.BlkioDeviceStats{
+			Major:      entry.Major,
+			Minor:      entry.Minor,
+			ReadBytes:  entry.Rbytes,
+			WriteBytes: entry.Wbytes,
+			ReadOps:    entry.Rios,
+			WriteOps:   entry.Wios,
+		})
+	}
+	return res
+}
+
+// The protobuf field numbers of the pressure stall information (PSI) in the
+// cgroup v2 metrics of github.com/containerd/cgroups/v3. The vendored metrics
+// types do not know them, which keeps them as unrecognized fields.
+const (
+	metricsV2CPUPSIField    protowire.Number = 7
+	metricsV2MemoryPSIField protowire.Number = 38
+	metricsV2IOPSIField     protowire.Number = 2
+
+	psiStatsSomeField protowire.Number = 1
+	psiStatsFullField protowire.Number = 2
+
+	psiDataAvg10Field  protowire.Number = 1
+	psiDataAvg60Field  protowire.Number = 2
+	psiDataAvg300Field protowire.Number = 3
+	psiDataTotalField  protowire.Number = 4
+)
+
+// metricsV2ToPressureStats returns the pressure stall information reported by
+// the guest, or nil if the guest does not report it.
+func metricsV2ToPressureStats(m *cgroupsV2.Metrics) *cgmgr.PressureStats {
+	res := &cgmgr.PressureStats{}
+	if m.CPU != nil {
+		res.CPU = protoToPSIStats(protoMessageField(m.CPU.XXX_unrecognized, metricsV2CPUPSIField))
+	}
+	if m.Memory != nil {
+		res.Memory = protoToPSIStats(protoMessageField(m.Memory.XXX_unrecognized, metricsV2MemoryPSIField))
+	}
+	if m.Io != nil {
+		res.IO = protoToPSIStats(protoMessageField(m.Io.XXX_unrecognized, metricsV2IOPSIField))
+	}
+	if res.CPU == nil && res.Memory == nil && res.IO == nil {
+		return nil
+	}
+	return res
+}
+
+func protoToPSIStats(b []byte) *cgmgr.PSIStats {
+	if b == nil {
+		return nil
+	}
+	return &cgmgr.PSIStats{
+		Some: protoToPSIData(protoMessageField(b, psiStatsSomeField)),
+		Full: protoToPSIData(protoMessageField(b, psiStatsFullField)),
+	}
+}
+
+func protoToPSIData(b []byte) cgmgr.PSIData {
+	res := cgmgr.PSIData{}
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return res
+		}
+		b = b[n:]
+
+		var value uint64
+		switch typ {
+		case protowire.Fixed64Type:
+			value, n = protowire.ConsumeFixed64(b)
+		case protowire.VarintType:
+			value, n = protowire.ConsumeVarint(b)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, b)
+		}
+		if n < 0 {
+			return res
+		}
+		b = b[n:]
+
+		switch {
+		case num == psiDataAvg10Field && typ == protowire.Fixed64Type:
+			res.Avg10 = math.Float64frombits(value)
+		case num == psiDataAvg60Field && typ == protowire.Fixed64Type:
+			res.Avg60 = math.Float64frombits(value)
+		case num == psiDataAvg300Field && typ == protowire.Fixed64Type:
+			res.Avg300 = math.Float64frombits(value)
+		case num == psiDataTotalField && typ == protowire.VarintType:
+			res.Total = value
+		}
+	}
+	return res
+}
+
+// protoMessageField returns the encoded message of the field within the
+// encoded protobuf fields, or nil if the field is missing or malformed.
+func protoMessageField(b []byte, field protowire.Number) []byte {
+	var res []byte
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return nil
+		}
+		b = b[n:]
+
+		if num == field && typ == protowire.BytesType {
+			var value []byte
+			value, n = protowire.ConsumeBytes(b)
+			res = value
+		} else {
+			n = protowire.ConsumeFieldValue(num, typ, b)
+		}
+		if n < 0 {
+			return nil
+		}
+		b = b[n:]
+	}
+	return res
+}
+
 // SignalContainer sends a signal to a container process.
 func (r *runtimeVM) SignalContainer(ctx context.Context, c *Container, sig syscall.Signal) error {
 	log.Debugf(ctx, "RuntimeVM.SignalContainer() start")
@@ -1130,19 +1335,42 @@ func (r *runtimeVM) AttachContainer(ctx context.Context, c *Container, inputStre
 	return nil
 }
 
-// PortForwardContainer forwards the specified port provides statistics of a container.
-func (r *runtimeVM) PortForwardContainer(ctx context.Context, c *Container, netNsPath string, port int32, stream io.ReadWriteCloser) error {
-	log.Debugf(ctx, "RuntimeVM.PortForwardContainer() start")
-	defer log.Debugf(ctx, "RuntimeVM.PortForwardContainer() end")
-
-	return nil
-}
-
 // ReopenContainerLog reopens the log file of a container.
 func (r *runtimeVM) ReopenContainerLog(ctx context.Context, c *Container) error {
 	log.Debugf(ctx, "RuntimeVM.ReopenContainerLog() start")
 	defer log.Debugf(ctx, "RuntimeVM.ReopenContainerLog() end")
 
+	response, err := r.task.State(r.ctx, &task.StateRequest{
+		ID: c.ID(),
+	})
+	if err != nil {
+		return errdefs.FromGRPC(err)
+	}
+	if response.Status != tasktypes.Status_RUNNING {
+		return fmt.Errorf("container %s is not running", c.ID())
+	}
+
+	r.Lock()
+	cInfo, ok := r.ctrs[c.ID()]
+	r.Unlock()
+	if !ok {
+		return errors.New("could not retrieve container information")
+	}
+
+	stdout, stderr, err := r.openContainerLog(ctx, c)
+	if err != nil {
+		return fmt.Errorf("reopen container log: %w", err)
+	}
+
+	// The loggers of the previous log file get closed, which closes the file
+	// once both of them are done.
+	oldStdout, oldStderr := cInfo.cio.AddOutput(c.LogPath(), stdout, stderr)
+	if oldStdout != nil {
+		oldStdout.Close()
+	}
+	if oldStderr != nil {
+		oldStderr.Close()
+	}
 	return nil
 }
 
@@ -1231,6 +1459,14 @@ func (r *runtimeVM) CheckpointContainer(ctx context.Context, c *Container, specg
 	return errors.New("checkpointing not implemented for runtimeVM")
 }
 
+// PreDumpContainer not implemented for runtimeVM
+func (r *runtimeVM) PreDumpContainer(ctx context.Context, c *Container, specgen *rspec.Spec) error {
+	log.Debugf(ctx, "RuntimeVM.PreDumpContainer() start")
+	defer log.Debugf(ctx, "RuntimeVM.PreDumpContainer() end")
+
+	return errors.New("checkpointing not implemented for runtimeVM")
+}
+
 // RestoreContainer not implemented for runtimeVM
 func (r *runtimeVM) RestoreContainer(ctx context.Context, c *Container, cgroupParent, mountLabel string) error {
 	log.Debugf(ctx, "RuntimeVM.RestoreContainer() start")
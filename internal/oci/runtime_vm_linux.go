@@ -0,0 +1,93 @@
+package oci
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/cri-o/cri-o/internal/log"
+	"golang.org/x/net/context"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+// vmPortForwarder is the binary executed inside the guest to forward a port,
+// which has to be provided by the container image.
+const vmPortForwarder = "socat"
+
+// execNotFoundExitCode is the exit code of a process whose executable could
+// not be found.
+const execNotFoundExitCode = 127
+
+// vmPortForwardCommand returns the command executed inside the guest to
+// forward a port. It connects its stdin and stdout to the port on localhost.
+func vmPortForwardCommand(port int32) []string {
+	return []string{vmPortForwarder, "-", fmt.Sprintf("TCP:localhost:%d", port)}
+}
+
+// PortForwardContainer forwards the specified port into the provided container.
+// The port gets dialed from the network namespace of the sandbox if it exists
+// on the host. Otherwise it gets dialed inside the guest by executing a
+// forwarder in the container through the shim, which is only supported for
+// TCP.
+func (r *runtimeVM) PortForwardContainer(ctx context.Context, c *Container, netNsPath string, port int32, protocol types.Protocol, stream io.ReadWriteCloser) error {
+	log.Debugf(ctx, "RuntimeVM.PortForwardContainer() start")
+	defer log.Debugf(ctx, "RuntimeVM.PortForwardContainer() end")
+
+	if netNsPath != "" {
+		if _, err := os.Stat(netNsPath); err == nil {
+			return portForwardInNetNS(ctx, c, netNsPath, port, protocol, stream)
+		}
+	}
+
+	if protocol != types.Protocol_TCP {
+		return fmt.Errorf("%s port forwarding requires the network namespace of the sandbox on the host", protocol)
+	}
+
+	return r.portForwardInGuest(ctx, c, port, stream)
+}
+
+// portForwardInGuest forwards the specified port by connecting to it from
+// within the guest.
+func (r *runtimeVM) portForwardInGuest(ctx context.Context, c *Container, port int32, stream io.ReadWriteCloser) error {
+	defer stream.Close()
+	log.Infof(ctx, "Starting port forward for %s inside the guest", c.ID())
+
+	var stderrBuf bytes.Buffer
+	stderr := &writeCloserWrapper{limitWriter(&stderrBuf, maxExecSyncSize)}
+
+	exitCode, err := r.execContainerCommon(ctx, c, vmPortForwardCommand(port), 0, stream, stream, stderr, false, nil)
+	if err != nil {
+		if isExecutableNotFound(err) {
+			return fmt.Errorf(
+				"port forward into guest requires the %s binary in the image of container %s: %w",
+				vmPortForwarder, c.ID(), err,
+			)
+		}
+		return fmt.Errorf("port forward into guest of container %s: %w", c.ID(), err)
+	}
+	if exitCode == execNotFoundExitCode {
+		return fmt.Errorf(
+			"port forward into guest requires the %s binary in the image of container %s: %s",
+			vmPortForwarder, c.ID(), strings.TrimSpace(stderrBuf.String()),
+		)
+	}
+	if exitCode != 0 {
+		return fmt.Errorf(
+			"failed to connect to localhost:%d inside guest of container %s, exit code %d: %s",
+			port, c.ID(), exitCode, strings.TrimSpace(stderrBuf.String()),
+		)
+	}
+
+	log.Infof(ctx, "Finished port forwarding for %q on port %d", c.ID(), port)
+	return nil
+}
+
+// isExecutableNotFound returns true if the error reports that the executable
+// of a process does not exist.
+func isExecutableNotFound(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "executable file not found") ||
+		strings.Contains(msg, "no such file or directory")
+}
@@ -0,0 +1,432 @@
+package oci_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	cgroupsV1 "github.com/containerd/cgroups/stats/v1"
+	cgroupsV2 "github.com/containerd/cgroups/v2/stats"
+	"github.com/containerd/containerd/api/runtime/task/v2"
+	tasktypes "github.com/containerd/containerd/api/types/task"
+	"github.com/containerd/typeurl"
+	"github.com/cri-o/cri-o/internal/config/cgmgr"
+	"github.com/cri-o/cri-o/internal/oci"
+	libconfig "github.com/cri-o/cri-o/pkg/config"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	rspec "github.com/opencontainers/runtime-spec/specs-go"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/emptypb"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+// fakeTaskService is a shim task service, which echoes the stdin of exec
+// processes to their stdout. Exec processes without stdin write the output
+// instead.
+type fakeTaskService struct {
+	task.TaskService
+
+	status     tasktypes.Status
+	exitStatus uint32
+	startErr   error
+	output     string
+	stats      *anypb.Any
+
+	mutex sync.Mutex
+	execs map[string]*task.ExecProcessRequest
+	done  map[string]chan struct{}
+}
+
+func newFakeTaskService(status tasktypes.Status) *fakeTaskService {
+	return &fakeTaskService{
+		status: status,
+		execs:  map[string]*task.ExecProcessRequest{},
+		done:   map[string]chan struct{}{},
+	}
+}
+
+func (f *fakeTaskService) State(_ context.Context, req *task.StateRequest) (*task.StateResponse, error) {
+	return &task.StateResponse{ID: req.ID, Status: f.status}, nil
+}
+
+func (f *fakeTaskService) Exec(_ context.Context, req *task.ExecProcessRequest) (*emptypb.Empty, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.execs[req.ExecID] = req
+	f.done[req.ExecID] = make(chan struct{})
+	return &emptypb.Empty{}, nil
+}
+
+func (f *fakeTaskService) Start(_ context.Context, req *task.StartRequest) (*task.StartResponse, error) {
+	if f.startErr != nil {
+		return nil, f.startErr
+	}
+	f.mutex.Lock()
+	exec, done := f.execs[req.ExecID], f.done[req.ExecID]
+	f.mutex.Unlock()
+
+	go func() {
+		defer close(done)
+		stdout, err := os.OpenFile(exec.Stdout, os.O_WRONLY, 0)
+		if err != nil {
+			return
+		}
+		defer stdout.Close()
+		stderr, err := os.OpenFile(exec.Stderr, os.O_WRONLY, 0)
+		if err != nil {
+			return
+		}
+		defer stderr.Close()
+		if exec.Stdin == "" {
+			io.WriteString(stdout, f.output) //nolint:errcheck
+			return
+		}
+		stdin, err := os.OpenFile(exec.Stdin, os.O_RDONLY, 0)
+		if err != nil {
+			return
+		}
+		defer stdin.Close()
+		io.Copy(stdout, stdin) //nolint:errcheck
+	}()
+	return &task.StartResponse{}, nil
+}
+
+func (f *fakeTaskService) Wait(_ context.Context, req *task.WaitRequest) (*task.WaitResponse, error) {
+	f.mutex.Lock()
+	done := f.done[req.ExecID]
+	f.mutex.Unlock()
+	<-done
+	return &task.WaitResponse{ExitStatus: f.exitStatus}, nil
+}
+
+func (f *fakeTaskService) CloseIO(context.Context, *task.CloseIORequest) (*emptypb.Empty, error) {
+	return &emptypb.Empty{}, nil
+}
+
+func (f *fakeTaskService) Kill(context.Context, *task.KillRequest) (*emptypb.Empty, error) {
+	return &emptypb.Empty{}, nil
+}
+
+func (f *fakeTaskService) Delete(context.Context, *task.DeleteRequest) (*task.DeleteResponse, error) {
+	return &task.DeleteResponse{}, nil
+}
+
+func (f *fakeTaskService) Stats(context.Context, *task.StatsRequest) (*task.StatsResponse, error) {
+	return &task.StatsResponse{Stats: f.stats}, nil
+}
+
+func (f *fakeTaskService) execArgs() [][]string {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	res := [][]string{}
+	for _, exec := range f.execs {
+		spec := &rspec.Process{}
+		Expect(json.Unmarshal(exec.Spec.GetValue(), spec)).To(Succeed())
+		res = append(res, spec.Args)
+	}
+	return res
+}
+
+// portForwardStream is a port forward stream which reads from the provided
+// input and records the output.
+type portForwardStream struct {
+	io.Reader
+
+	mutex  sync.Mutex
+	output bytes.Buffer
+}
+
+func (s *portForwardStream) Write(p []byte) (int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.output.Write(p)
+}
+
+func (s *portForwardStream) Close() error {
+	return nil
+}
+
+func (s *portForwardStream) String() string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.output.String()
+}
+
+// The actual test suite
+var _ = t.Describe("RuntimeVM", func() {
+	var (
+		taskService *fakeTaskService
+		sut         oci.RuntimeVM
+		ctr         *oci.Container
+		logPath     string
+	)
+
+	newSut := func(status tasktypes.Status) {
+		taskService = newFakeTaskService(status)
+		sut = oci.NewRuntimeVM(&libconfig.RuntimeHandler{
+			RuntimeType: libconfig.RuntimeTypeVM,
+			RuntimeRoot: t.MustTempDir("runtime"),
+		}, t.MustTempDir("exits"), taskService)
+	}
+
+	BeforeEach(func() {
+		logPath = filepath.Join(t.MustTempDir("log"), "ctr.log")
+
+		var err error
+		ctr, err = oci.NewContainer(containerID, "", t.MustTempDir("bundle"), logPath,
+			map[string]string{}, map[string]string{}, map[string]string{},
+			"", nil, nil, "", &types.ContainerMetadata{}, sandboxID,
+			false, false, false, "", "", time.Now(), "")
+		Expect(err).ToNot(HaveOccurred())
+		ctr.SetSpec(&rspec.Spec{Process: &rspec.Process{}})
+	})
+
+	t.Describe("ReopenContainerLog", func() {
+		It("should reopen the log of a running container", func() {
+			// Given
+			newSut(tasktypes.Status_RUNNING)
+			Expect(sut.UpdateContainerStatus(context.Background(), ctr)).To(Succeed())
+			Expect(logPath).To(BeAnExistingFile())
+			Expect(os.Rename(logPath, logPath+".1")).To(Succeed())
+
+			// When
+			err := sut.ReopenContainerLog(context.Background(), ctr)
+
+			// Then
+			Expect(err).NotTo(HaveOccurred())
+			Expect(logPath).To(BeAnExistingFile())
+		})
+
+		It("should fail if the container is not running", func() {
+			// Given
+			newSut(tasktypes.Status_STOPPED)
+			Expect(sut.UpdateContainerStatus(context.Background(), ctr)).To(Succeed())
+
+			// When
+			err := sut.ReopenContainerLog(context.Background(), ctr)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("not running"))
+		})
+
+		It("should fail without the container IO", func() {
+			// Given
+			newSut(tasktypes.Status_RUNNING)
+
+			// When
+			err := sut.ReopenContainerLog(context.Background(), ctr)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	t.Describe("ExecSyncContainer", func() {
+		It("should return the whole output of the process", func() {
+			// Given
+			newSut(tasktypes.Status_RUNNING)
+			taskService.output = strings.Repeat("output\n", 16*1024)
+
+			// When
+			res, err := sut.ExecSyncContainer(context.Background(), ctr, []string{"cat", "file"}, 0)
+
+			// Then
+			Expect(err).NotTo(HaveOccurred())
+			Expect(res.ExitCode).To(BeZero())
+			Expect(string(res.Stdout)).To(Equal(taskService.output))
+		})
+	})
+
+	t.Describe("ContainerStats", func() {
+		setStats := func(m interface{}) {
+			stats, err := typeurl.MarshalAny(m)
+			Expect(err).NotTo(HaveOccurred())
+			taskService.stats = &anypb.Any{TypeUrl: stats.GetTypeUrl(), Value: stats.GetValue()}
+		}
+
+		// psiField encodes the pressure stall information field of the
+		// cgroup v2 metrics, which only contains the "some" data.
+		psiField := func(field protowire.Number, avg10 float64, total uint64) []byte {
+			data := protowire.AppendTag(nil, 1, protowire.Fixed64Type)
+			data = protowire.AppendFixed64(data, math.Float64bits(avg10))
+			data = protowire.AppendTag(data, 4, protowire.VarintType)
+			data = protowire.AppendVarint(data, total)
+			stats := protowire.AppendTag(nil, 1, protowire.BytesType)
+			stats = protowire.AppendBytes(stats, data)
+			res := protowire.AppendTag(nil, field, protowire.BytesType)
+			return protowire.AppendBytes(res, stats)
+		}
+
+		It("should aggregate the blkio stats of cgroup v1 per device", func() {
+			// Given
+			newSut(tasktypes.Status_RUNNING)
+			setStats(&cgroupsV1.Metrics{
+				Memory: &cgroupsV1.MemoryStat{
+					Usage:     &cgroupsV1.MemoryEntry{},
+					Swap:      &cgroupsV1.MemoryEntry{},
+					Kernel:    &cgroupsV1.MemoryEntry{},
+					KernelTCP: &cgroupsV1.MemoryEntry{},
+				},
+				CPU:  &cgroupsV1.CPUStat{Usage: &cgroupsV1.CPUUsage{}, Throttling: &cgroupsV1.Throttle{}},
+				Pids: &cgroupsV1.PidsStat{},
+				Blkio: &cgroupsV1.BlkIOStat{
+					IoServiceBytesRecursive: []*cgroupsV1.BlkIOEntry{
+						{Major: 8, Op: "Read", Value: 100},
+						{Major: 8, Op: "Write", Value: 200},
+						{Major: 8, Op: "Total", Value: 300},
+					},
+					IoServicedRecursive: []*cgroupsV1.BlkIOEntry{
+						{Major: 8, Op: "Read", Value: 1},
+						{Major: 8, Op: "Write", Value: 2},
+					},
+				},
+			})
+
+			// When
+			stats, err := sut.ContainerStats(context.Background(), ctr, "")
+
+			// Then
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stats.Blkio).To(Equal([]cgmgr.BlkioDeviceStats{
+				{Major: 8, ReadBytes: 100, WriteBytes: 200, ReadOps: 1, WriteOps: 2},
+			}))
+		})
+
+		It("should convert the pressure stall information of cgroup v2", func() {
+			// Given
+			newSut(tasktypes.Status_RUNNING)
+			setStats(&cgroupsV2.Metrics{
+				Pids:   &cgroupsV2.PidsStat{},
+				CPU:    &cgroupsV2.CPUStat{XXX_unrecognized: psiField(7, 1.5, 1234)},
+				Memory: &cgroupsV2.MemoryStat{},
+				Io:     &cgroupsV2.IOStat{XXX_unrecognized: psiField(2, 0.5, 567)},
+			})
+
+			// When
+			stats, err := sut.ContainerStats(context.Background(), ctr, "")
+
+			// Then
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stats.Pressure).NotTo(BeNil())
+			Expect(stats.Pressure.CPU).To(Equal(&cgmgr.PSIStats{Some: cgmgr.PSIData{Avg10: 1.5, Total: 1234}}))
+			Expect(stats.Pressure.Memory).To(BeNil())
+			Expect(stats.Pressure.IO).To(Equal(&cgmgr.PSIStats{Some: cgmgr.PSIData{Avg10: 0.5, Total: 567}}))
+		})
+
+		It("should not report pressure stall information if the guest does not", func() {
+			// Given
+			newSut(tasktypes.Status_RUNNING)
+			setStats(&cgroupsV2.Metrics{
+				Pids:   &cgroupsV2.PidsStat{},
+				CPU:    &cgroupsV2.CPUStat{},
+				Memory: &cgroupsV2.MemoryStat{},
+			})
+
+			// When
+			stats, err := sut.ContainerStats(context.Background(), ctr, "")
+
+			// Then
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stats.Pressure).To(BeNil())
+		})
+	})
+
+	t.Describe("PortForwardContainer", func() {
+		It("should forward the port inside the guest without a network namespace", func() {
+			// Given
+			newSut(tasktypes.Status_RUNNING)
+			stream := &portForwardStream{Reader: strings.NewReader("hello")}
+
+			// When
+			err := sut.PortForwardContainer(context.Background(), ctr, "", 8080, types.Protocol_TCP, stream)
+
+			// Then
+			Expect(err).NotTo(HaveOccurred())
+			Expect(taskService.execArgs()).To(Equal([][]string{
+				{"socat", "-", "TCP:localhost:8080"},
+			}))
+			Expect(stream.String()).To(Equal("hello"))
+		})
+
+		It("should forward the port inside the guest if the network namespace does not exist", func() {
+			// Given
+			newSut(tasktypes.Status_RUNNING)
+			stream := &portForwardStream{Reader: strings.NewReader("ping")}
+
+			// When
+			err := sut.PortForwardContainer(context.Background(), ctr, "/proc/not/existing", 80, types.Protocol_TCP, stream)
+
+			// Then
+			Expect(err).NotTo(HaveOccurred())
+			Expect(taskService.execArgs()).To(Equal([][]string{
+				{"socat", "-", "TCP:localhost:80"},
+			}))
+		})
+
+		It("should fail if the forwarder fails inside the guest", func() {
+			// Given
+			newSut(tasktypes.Status_RUNNING)
+			taskService.exitStatus = 1
+			stream := &portForwardStream{Reader: strings.NewReader("ping")}
+
+			// When
+			err := sut.PortForwardContainer(context.Background(), ctr, "", 8080, types.Protocol_TCP, stream)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("exit code 1"))
+		})
+
+		It("should fail with a clear error if the forwarder is missing inside the guest", func() {
+			// Given
+			newSut(tasktypes.Status_RUNNING)
+			taskService.startErr = errors.New(`exec: "socat": executable file not found in $PATH`)
+			stream := &portForwardStream{Reader: strings.NewReader("ping")}
+
+			// When
+			err := sut.PortForwardContainer(context.Background(), ctr, "", 8080, types.Protocol_TCP, stream)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("requires the socat binary"))
+		})
+
+		It("should fail with a clear error if the forwarder exits as not found", func() {
+			// Given
+			newSut(tasktypes.Status_RUNNING)
+			taskService.exitStatus = 127
+			stream := &portForwardStream{Reader: strings.NewReader("ping")}
+
+			// When
+			err := sut.PortForwardContainer(context.Background(), ctr, "", 8080, types.Protocol_TCP, stream)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("requires the socat binary"))
+		})
+
+		It("should fail to forward UDP inside the guest", func() {
+			// Given
+			newSut(tasktypes.Status_RUNNING)
+			stream := &portForwardStream{Reader: strings.NewReader("ping")}
+
+			// When
+			err := sut.PortForwardContainer(context.Background(), ctr, "", 53, types.Protocol_UDP, stream)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(taskService.execArgs()).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,17 @@
+//go:build !linux
+// +build !linux
+
+package oci
+
+import (
+	"errors"
+	"io"
+
+	"golang.org/x/net/context"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+// PortForwardContainer forwards the specified port into the provided container.
+func (r *runtimeVM) PortForwardContainer(ctx context.Context, c *Container, netNsPath string, port int32, protocol types.Protocol, stream io.ReadWriteCloser) error {
+	return errors.New("port forwarding is not supported for VM runtimes on this platform")
+}
@@ -0,0 +1,147 @@
+package pullscheduler
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/cri-o/cri-o/server/metrics"
+)
+
+// Scheduler limits the amount of image pulls running at the same time, in
+// total as well as per registry. Pulls which exceed the limits are queued and
+// started in the order they have been requested, as soon as a slot for their
+// registry becomes available.
+type Scheduler struct {
+	maxConcurrent     int
+	maxPerRegistry    int
+	active            int
+	activePerRegistry map[string]int
+	queue             *list.List
+	mutex             sync.Mutex
+}
+
+// request is a single queued pull.
+type request struct {
+	registry string
+	queuedAt time.Time
+	ready    chan struct{}
+}
+
+// New creates a new Scheduler. A limit of 0 means that the amount of pulls is
+// not limited.
+func New(maxConcurrent, maxPerRegistry int) *Scheduler {
+	return &Scheduler{
+		maxConcurrent:     maxConcurrent,
+		maxPerRegistry:    maxPerRegistry,
+		activePerRegistry: make(map[string]int),
+		queue:             list.New(),
+	}
+}
+
+// Acquire blocks until a pull from the registry is allowed to start or the
+// context is done. The returned release function has to be called once the
+// pull finished.
+func (s *Scheduler) Acquire(ctx context.Context, registry string) (release func(), err error) {
+	s.mutex.Lock()
+	req := &request{
+		registry: registry,
+		queuedAt: time.Now(),
+		ready:    make(chan struct{}),
+	}
+	elem := s.queue.PushBack(req)
+	metrics.Instance().MetricImagePullsQueueDepthSet(registry, s.queuedLocked(registry))
+	s.scheduleLocked()
+	s.mutex.Unlock()
+
+	release = func() { s.release(registry) }
+
+	select {
+	case <-req.ready:
+		return release, nil
+	case <-ctx.Done():
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	select {
+	case <-req.ready:
+		// The request got scheduled in the meantime, hand back the slot.
+		s.releaseLocked(registry)
+	default:
+		s.queue.Remove(elem)
+		metrics.Instance().MetricImagePullsQueueDepthSet(registry, s.queuedLocked(registry))
+	}
+	return nil, ctx.Err()
+}
+
+// release frees the slot of a finished pull and starts the next queued ones.
+func (s *Scheduler) release(registry string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.releaseLocked(registry)
+}
+
+func (s *Scheduler) releaseLocked(registry string) {
+	s.active--
+	s.activePerRegistry[registry]--
+	if s.activePerRegistry[registry] <= 0 {
+		delete(s.activePerRegistry, registry)
+	}
+	s.scheduleLocked()
+}
+
+// scheduleLocked starts all queued requests in FIFO order, skipping the ones
+// whose registry is at its limit, until the global limit is reached.
+func (s *Scheduler) scheduleLocked() {
+	for elem := s.queue.Front(); elem != nil; {
+		if s.maxConcurrent > 0 && s.active >= s.maxConcurrent {
+			return
+		}
+		next := elem.Next()
+		req, ok := elem.Value.(*request)
+		if !ok {
+			s.queue.Remove(elem)
+			elem = next
+			continue
+		}
+		if s.maxPerRegistry > 0 && s.activePerRegistry[req.registry] >= s.maxPerRegistry {
+			elem = next
+			continue
+		}
+
+		s.queue.Remove(elem)
+		s.active++
+		s.activePerRegistry[req.registry]++
+		metrics.Instance().MetricImagePullsQueueDepthSet(req.registry, s.queuedLocked(req.registry))
+		metrics.Instance().MetricImagePullsQueueWaitObserve(req.registry, req.queuedAt)
+		close(req.ready)
+		elem = next
+	}
+}
+
+// queuedLocked returns the amount of queued requests for the registry.
+func (s *Scheduler) queuedLocked(registry string) int {
+	queued := 0
+	for elem := s.queue.Front(); elem != nil; elem = elem.Next() {
+		if req, ok := elem.Value.(*request); ok && req.registry == registry {
+			queued++
+		}
+	}
+	return queued
+}
+
+// Active returns the amount of currently running pulls.
+func (s *Scheduler) Active() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.active
+}
+
+// Queued returns the amount of currently queued pulls.
+func (s *Scheduler) Queued() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.queue.Len()
+}
@@ -0,0 +1,161 @@
+package pullscheduler_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/cri-o/cri-o/internal/pullscheduler"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const (
+	registryA = "registry-a.example.com"
+	registryB = "registry-b.example.com"
+)
+
+// acquireAsync acquires a pull slot in the background and sends the release
+// function once the slot got granted.
+func acquireAsync(ctx context.Context, sut *pullscheduler.Scheduler, registry string) (<-chan func(), <-chan error) {
+	granted := make(chan func(), 1)
+	failed := make(chan error, 1)
+	go func() {
+		release, err := sut.Acquire(ctx, registry)
+		if err != nil {
+			failed <- err
+			return
+		}
+		granted <- release
+	}()
+	return granted, failed
+}
+
+// The actual test suite
+var _ = t.Describe("PullScheduler", func() {
+	It("should not limit pulls without limits", func() {
+		// Given
+		sut := pullscheduler.New(0, 0)
+
+		// When
+		releases := []func(){}
+		for i := 0; i < 10; i++ {
+			release, err := sut.Acquire(context.Background(), registryA)
+			Expect(err).ToNot(HaveOccurred())
+			releases = append(releases, release)
+		}
+
+		// Then
+		Expect(sut.Active()).To(Equal(10))
+		Expect(sut.Queued()).To(BeZero())
+		for _, release := range releases {
+			release()
+		}
+		Expect(sut.Active()).To(BeZero())
+	})
+
+	It("should queue pulls exceeding the global limit", func() {
+		// Given
+		sut := pullscheduler.New(1, 0)
+		release, err := sut.Acquire(context.Background(), registryA)
+		Expect(err).ToNot(HaveOccurred())
+
+		// When
+		granted, _ := acquireAsync(context.Background(), sut, registryB)
+
+		// Then
+		Eventually(sut.Queued).Should(Equal(1))
+		Consistently(granted, 100*time.Millisecond).ShouldNot(Receive())
+		release()
+		var next func()
+		Eventually(granted).Should(Receive(&next))
+		Expect(sut.Active()).To(Equal(1))
+		next()
+		Expect(sut.Active()).To(BeZero())
+	})
+
+	It("should not block other registries on the per registry limit", func() {
+		// Given
+		sut := pullscheduler.New(0, 1)
+		release, err := sut.Acquire(context.Background(), registryA)
+		Expect(err).ToNot(HaveOccurred())
+		grantedA, _ := acquireAsync(context.Background(), sut, registryA)
+		Eventually(sut.Queued).Should(Equal(1))
+
+		// When
+		releaseB, err := sut.Acquire(context.Background(), registryB)
+
+		// Then
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sut.Active()).To(Equal(2))
+		Consistently(grantedA, 100*time.Millisecond).ShouldNot(Receive())
+		release()
+		var next func()
+		Eventually(grantedA).Should(Receive(&next))
+		next()
+		releaseB()
+		Expect(sut.Active()).To(BeZero())
+	})
+
+	It("should start queued pulls in FIFO order", func() {
+		// Given
+		sut := pullscheduler.New(1, 0)
+		release, err := sut.Acquire(context.Background(), registryA)
+		Expect(err).ToNot(HaveOccurred())
+		grantedFirst, _ := acquireAsync(context.Background(), sut, registryA)
+		Eventually(sut.Queued).Should(Equal(1))
+		grantedSecond, _ := acquireAsync(context.Background(), sut, registryB)
+		Eventually(sut.Queued).Should(Equal(2))
+
+		// When
+		release()
+
+		// Then
+		var next func()
+		Eventually(grantedFirst).Should(Receive(&next))
+		Consistently(grantedSecond, 100*time.Millisecond).ShouldNot(Receive())
+		next()
+		Eventually(grantedSecond).Should(Receive(&next))
+		next()
+		Expect(sut.Active()).To(BeZero())
+	})
+
+	It("should remove cancelled pulls from the queue", func() {
+		// Given
+		sut := pullscheduler.New(1, 0)
+		release, err := sut.Acquire(context.Background(), registryA)
+		Expect(err).ToNot(HaveOccurred())
+		ctx, cancel := context.WithCancel(context.Background())
+		granted, failed := acquireAsync(ctx, sut, registryA)
+		Eventually(sut.Queued).Should(Equal(1))
+
+		// When
+		cancel()
+
+		// Then
+		var failure error
+		Eventually(failed).Should(Receive(&failure))
+		Expect(failure).To(MatchError(context.Canceled))
+		Expect(sut.Queued()).To(BeZero())
+		Expect(granted).ToNot(Receive())
+		release()
+		Expect(sut.Active()).To(BeZero())
+	})
+
+	It("should fail if the context is already done", func() {
+		// Given
+		sut := pullscheduler.New(1, 0)
+		release, err := sut.Acquire(context.Background(), registryA)
+		Expect(err).ToNot(HaveOccurred())
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		// When
+		_, err = sut.Acquire(ctx, registryA)
+
+		// Then
+		Expect(err).To(MatchError(context.Canceled))
+		Expect(sut.Queued()).To(BeZero())
+		release()
+		Expect(sut.Active()).To(BeZero())
+	})
+})
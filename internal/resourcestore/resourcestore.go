@@ -7,14 +7,27 @@ import (
 	"time"
 
 	"github.com/cri-o/cri-o/internal/log"
+	"github.com/cri-o/cri-o/server/metrics"
 	"github.com/sirupsen/logrus"
 )
 
 const (
 	sleepTimeBeforeCleanup = 1 * time.Minute
 	StageUnknown           = "unknown"
+
+	// StageResultSuccess is the result of a stage which has been completed
+	// by entering the next stage or by successfully creating the resource.
+	StageResultSuccess = "success"
+	// StageResultFailure is the result of a stage during which the creation
+	// request of the resource failed or timed out.
+	StageResultFailure = "failure"
 )
 
+// observeStageLatency records the latency of a finished stage by its result.
+var observeStageLatency = func(stage, result string, start time.Time) {
+	metrics.Instance().MetricResourcesStageLatencyObserve(stage, result, start)
+}
+
 // ResourceStore is a structure that saves information about a recently created resource.
 // Resources can be added and retrieved from the store. A retrieval (Get) also removes the Resource from the store.
 // The ResourceStore comes with a cleanup routine that loops through the resources and marks them as stale, or removes
@@ -40,6 +53,18 @@ type Resource struct {
 	stale    bool
 	name     string
 	stage    string
+	// stageStart is the time when the current stage has been entered
+	stageStart time.Time
+}
+
+// finishStage records the latency of the current stage with its result, if
+// any.
+func (r *Resource) finishStage(result string) {
+	if r.stage == "" || r.stageStart.IsZero() {
+		return
+	}
+	observeStageLatency(r.stage, result, r.stageStart)
+	r.stageStart = time.Time{}
 }
 
 // wasPut checks that a resource has been fully defined yet.
@@ -184,10 +209,19 @@ func (rc *ResourceStore) Put(name string, resource IdentifiableCreatable, cleane
 func (rc *ResourceStore) Delete(name string) {
 	rc.mutex.Lock()
 	defer rc.mutex.Unlock()
-
 	delete(rc.resources, name)
 }
 
+// FinishStageForResource records the latency of the current stage of the
+// resource with the result of its creation request.
+func (rc *ResourceStore) FinishStageForResource(name, result string) {
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+	if r, ok := rc.resources[name]; ok {
+		r.finishStage(result)
+	}
+}
+
 // WatcherForResource looks up a Resource by name, and gives it a watcher.
 // If no entry exists for that resource, a placeholder is created and a watcher is given to that
 // placeholder resource.
@@ -211,6 +245,8 @@ func (rc *ResourceStore) WatcherForResource(name string) (watcher chan struct{},
 	return watcher, r.stage
 }
 
+// SetStageForResource sets the current creation stage of the resource and
+// records the latency of the previous one.
 func (rc *ResourceStore) SetStageForResource(ctx context.Context, name, stage string) {
 	rc.mutex.Lock()
 	defer rc.mutex.Unlock()
@@ -218,12 +254,15 @@ func (rc *ResourceStore) SetStageForResource(ctx context.Context, name, stage st
 	if !ok {
 		log.Debugf(ctx, "Initializing stage for resource %s to %s", name, stage)
 		rc.resources[name] = &Resource{
-			watchers: []chan struct{}{},
-			name:     name,
-			stage:    stage,
+			watchers:   []chan struct{}{},
+			name:       name,
+			stage:      stage,
+			stageStart: time.Now(),
 		}
 		return
 	}
 	log.Debugf(ctx, "Setting stage for resource %s from %s to %s", name, r.stage, stage)
+	r.finishStage(StageResultSuccess)
 	r.stage = stage
+	r.stageStart = time.Now()
 }
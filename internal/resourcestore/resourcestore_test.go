@@ -206,4 +206,94 @@ var _ = t.Describe("ResourceStore", func() {
 			Expect(stage).To(Equal(stage2))
 		})
 	})
+	Context("Stage latencies", func() {
+		type observation struct {
+			stage  string
+			result string
+		}
+		var (
+			ctx          context.Context
+			observations []observation
+			previous     func(stage, result string, start time.Time)
+		)
+		BeforeEach(func() {
+			sut = resourcestore.New()
+			cleaner = resourcestore.NewResourceCleaner()
+			e = &entry{
+				id: testID,
+			}
+			ctx = context.Background()
+			observations = nil
+			previous = resourcestore.SetStageLatencyObserver(func(stage, result string, start time.Time) {
+				Expect(start).NotTo(BeZero())
+				observations = append(observations, observation{stage, result})
+			})
+		})
+		AfterEach(func() {
+			resourcestore.SetStageLatencyObserver(previous)
+			sut.Close()
+		})
+		It("should record entered stages as success", func() {
+			// Given
+			sut.SetStageForResource(ctx, testName, "stage1")
+			sut.SetStageForResource(ctx, testName, "stage2")
+
+			// When
+			sut.FinishStageForResource(testName, resourcestore.StageResultSuccess)
+
+			// Then
+			Expect(observations).To(Equal([]observation{
+				{"stage1", resourcestore.StageResultSuccess},
+				{"stage2", resourcestore.StageResultSuccess},
+			}))
+		})
+		It("should record the current stage of a failed creation as failure", func() {
+			// Given
+			sut.SetStageForResource(ctx, testName, "stage1")
+			sut.SetStageForResource(ctx, testName, "stage2")
+
+			// When
+			sut.FinishStageForResource(testName, resourcestore.StageResultFailure)
+
+			// Then
+			Expect(observations).To(Equal([]observation{
+				{"stage1", resourcestore.StageResultSuccess},
+				{"stage2", resourcestore.StageResultFailure},
+			}))
+		})
+		It("should record the current stage only once", func() {
+			// Given
+			sut.SetStageForResource(ctx, testName, "stage1")
+			sut.FinishStageForResource(testName, resourcestore.StageResultFailure)
+
+			// When
+			sut.FinishStageForResource(testName, resourcestore.StageResultSuccess)
+
+			// Then
+			Expect(observations).To(Equal([]observation{
+				{"stage1", resourcestore.StageResultFailure},
+			}))
+		})
+		It("should not record stages when putting or deleting resources", func() {
+			// Given
+			sut.SetStageForResource(ctx, testName, "stage1")
+
+			// When
+			Expect(sut.Put(testName, e, cleaner)).To(Succeed())
+			sut.Delete(testName)
+
+			// Then
+			Expect(observations).To(BeEmpty())
+		})
+		It("should not record stages of resources without a stage", func() {
+			// Given
+			_, _ = sut.WatcherForResource(testName)
+
+			// When
+			sut.FinishStageForResource(testName, resourcestore.StageResultSuccess)
+
+			// Then
+			Expect(observations).To(BeEmpty())
+		})
+	})
 })
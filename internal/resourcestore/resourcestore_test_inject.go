@@ -0,0 +1,17 @@
+//go:build test
+// +build test
+
+// All *_inject.go files are meant to be used by tests only. Purpose of this
+// files is to provide a way to inject mocked data into the current setup.
+
+package resourcestore
+
+import "time"
+
+// SetStageLatencyObserver replaces the function recording the latency of
+// finished stages for testing purposes and returns the previous one.
+func SetStageLatencyObserver(observer func(stage, result string, start time.Time)) func(stage, result string, start time.Time) {
+	previous := observeStageLatency
+	observeStageLatency = observer
+	return previous
+}
@@ -0,0 +1,308 @@
+package storage
+
+import (
+	"errors"
+	"os"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/containers/image/v5/docker/reference"
+	"github.com/containers/storage"
+	"github.com/cri-o/cri-o/pkg/config"
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// imageLastUsedKey is the image big data key used to persist the last time a
+// container got created from the image.
+const imageLastUsedKey = "crio-image-last-used"
+
+// ImageGC removes unused images from the store based on the time they have
+// been used last, the amount of images per repository and the store size.
+// Pinned images, images in use by containers and images created or used
+// within the last garbage collection interval are never removed.
+type ImageGC struct {
+	store             storage.Store
+	interval          time.Duration
+	maxAge            time.Duration
+	keepPerRepository int
+	maxStoreSize      int64
+	pinnedImages      []*regexp.Regexp
+	inUse             func() (map[string]bool, error)
+	mutex             sync.Mutex
+
+	// recentUses are the uses recorded by this process within the last
+	// interval. They protect the images until their containers exist in the
+	// store, even if the last use could not be persisted.
+	recentUses map[string]time.Time
+	usesMutex  sync.Mutex
+}
+
+// imageGCCandidate is an image considered by a garbage collection run.
+type imageGCCandidate struct {
+	id           string
+	repositories []string
+	lastUsed     time.Time
+	removable    bool
+}
+
+// NewImageGC creates a new ImageGC for the provided store. The inUse function
+// has to return the IDs of all images used by containers.
+func NewImageGC(store storage.Store, imageConfig *config.ImageConfig, inUse func() (map[string]bool, error)) *ImageGC {
+	pinnedImages := imageConfig.PinnedImages
+	if imageConfig.PauseImage != "" {
+		pinnedImages = append(pinnedImages[:len(pinnedImages):len(pinnedImages)], imageConfig.PauseImage)
+	}
+	return &ImageGC{
+		store:             store,
+		interval:          imageConfig.ImageGCInterval,
+		maxAge:            imageConfig.ImageGCMaxAge,
+		keepPerRepository: imageConfig.ImageGCKeepPerRepository,
+		maxStoreSize:      imageConfig.ImageGCMaxStoreSize,
+		pinnedImages:      CompileRegexpsForPinnedImages(pinnedImages),
+		inUse:             inUse,
+		recentUses:        make(map[string]time.Time),
+	}
+}
+
+// Enabled returns true if the images get garbage collected periodically.
+func (gc *ImageGC) Enabled() bool {
+	return gc.interval > 0
+}
+
+// Run garbage collects the images periodically until stopCh gets closed.
+func (gc *ImageGC) Run(stopCh <-chan struct{}) {
+	if !gc.Enabled() {
+		return
+	}
+	wait.Until(func() {
+		if _, err := gc.GarbageCollect(); err != nil {
+			logrus.Warnf("Unable to garbage collect images: %v", err)
+		}
+	}, gc.interval, stopCh)
+}
+
+// RecordImageUse persists the current time as last use of the image. It does
+// not wait for a running garbage collection, which does not remove the image
+// afterwards.
+func (gc *ImageGC) RecordImageUse(id StorageImageID) {
+	if !gc.Enabled() {
+		return
+	}
+	imageID := id.IDStringForOutOfProcessConsumptionOnly()
+	now := time.Now()
+
+	gc.usesMutex.Lock()
+	gc.recentUses[imageID] = now
+	gc.usesMutex.Unlock()
+
+	data, err := now.MarshalText()
+	if err != nil {
+		logrus.Debugf("Unable to marshal last use of image %s: %v", imageID, err)
+		return
+	}
+	if err := gc.store.SetImageBigData(imageID, imageLastUsedKey, data, nil); err != nil {
+		// Images in read-only additional stores cannot be modified
+		logrus.Debugf("Unable to record last use of image %s: %v", imageID, err)
+	}
+}
+
+// GarbageCollect removes all images selected by the configured policies and
+// returns their IDs.
+func (gc *ImageGC) GarbageCollect() ([]string, error) {
+	gc.mutex.Lock()
+	defer gc.mutex.Unlock()
+
+	start := time.Now()
+	gc.usesMutex.Lock()
+	for id, used := range gc.recentUses {
+		if start.Sub(used) >= gc.interval {
+			delete(gc.recentUses, id)
+		}
+	}
+	gc.usesMutex.Unlock()
+
+	images, err := gc.store.Images()
+	if err != nil {
+		return nil, err
+	}
+	inUse, err := gc.inUse()
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]*imageGCCandidate, 0, len(images))
+	for i := range images {
+		candidates = append(candidates, gc.candidate(&images[i], inUse))
+	}
+	// The least recently used images get removed first
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].lastUsed.Before(candidates[j].lastUsed)
+	})
+
+	selected := make(map[string]bool)
+	gc.selectByAge(candidates, selected)
+	gc.selectByRepository(candidates, selected)
+	if err := gc.selectBySize(candidates, selected); err != nil {
+		return nil, err
+	}
+
+	removed := []string{}
+	for _, candidate := range candidates {
+		if !selected[candidate.id] {
+			continue
+		}
+		if err := gc.deleteUnusedImage(candidate.id, start); err != nil {
+			if errors.Is(err, errImageUsedDuringGC) {
+				logrus.Debugf("Not garbage collecting image %s: %v", candidate.id, err)
+			} else if !errors.Is(err, storage.ErrImageUnknown) {
+				logrus.Warnf("Unable to garbage collect image %s: %v", candidate.id, err)
+			}
+			continue
+		}
+		logrus.Infof("Garbage collected image %s %v, last used %v", candidate.id, candidate.repositories, candidate.lastUsed)
+		removed = append(removed, candidate.id)
+	}
+	return removed, nil
+}
+
+// errImageUsedDuringGC is returned by deleteUnusedImage if the image got used
+// after the start of the garbage collection run.
+var errImageUsedDuringGC = errors.New("image used during garbage collection")
+
+// deleteUnusedImage removes the image unless it got used since start. The
+// uses cannot be recorded during the removal, so a container is either
+// created from an image which is kept or fails to find the removed image.
+func (gc *ImageGC) deleteUnusedImage(id string, start time.Time) error {
+	gc.usesMutex.Lock()
+	defer gc.usesMutex.Unlock()
+	if used, ok := gc.recentUses[id]; ok && !used.Before(start) {
+		return errImageUsedDuringGC
+	}
+	_, err := gc.store.DeleteImage(id, true)
+	return err
+}
+
+// candidate collects the garbage collection relevant information of an image.
+func (gc *ImageGC) candidate(image *storage.Image, inUse map[string]bool) *imageGCCandidate {
+	candidate := &imageGCCandidate{
+		id:       image.ID,
+		lastUsed: gc.lastUsed(image),
+	}
+	// The containers of recently created or used images may not exist yet
+	candidate.removable = !inUse[image.ID] && !imageIsBeingPulled(image) &&
+		time.Since(candidate.lastUsed) >= gc.interval
+
+	for _, name := range image.Names {
+		if FilterPinnedImage(name, gc.pinnedImages) {
+			candidate.removable = false
+		}
+		named, err := reference.ParseNormalizedNamed(name)
+		if err != nil {
+			continue
+		}
+		candidate.repositories = append(candidate.repositories, named.Name())
+	}
+	return candidate
+}
+
+// lastUsed returns the latest of the creation time, the persisted last use
+// and the last use recorded by this process of an image.
+func (gc *ImageGC) lastUsed(image *storage.Image) time.Time {
+	lastUsed := image.Created
+
+	gc.usesMutex.Lock()
+	if used, ok := gc.recentUses[image.ID]; ok && used.After(lastUsed) {
+		lastUsed = used
+	}
+	gc.usesMutex.Unlock()
+
+	data, err := gc.store.ImageBigData(image.ID, imageLastUsedKey)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			logrus.Debugf("Unable to get last use of image %s: %v", image.ID, err)
+		}
+		return lastUsed
+	}
+	persisted := time.Time{}
+	if err := persisted.UnmarshalText(data); err != nil {
+		logrus.Debugf("Unable to parse last use of image %s: %v", image.ID, err)
+		return lastUsed
+	}
+	if persisted.After(lastUsed) {
+		lastUsed = persisted
+	}
+	return lastUsed
+}
+
+// selectByAge selects the images which have not been used within the maximum age.
+func (gc *ImageGC) selectByAge(candidates []*imageGCCandidate, selected map[string]bool) {
+	if gc.maxAge <= 0 {
+		return
+	}
+	for _, candidate := range candidates {
+		if candidate.removable && time.Since(candidate.lastUsed) > gc.maxAge {
+			selected[candidate.id] = true
+		}
+	}
+}
+
+// selectByRepository selects the images which are not within the most
+// recently used ones of any of their repositories.
+func (gc *ImageGC) selectByRepository(candidates []*imageGCCandidate, selected map[string]bool) {
+	if gc.keepPerRepository <= 0 {
+		return
+	}
+	kept := make(map[string]bool)
+	perRepository := make(map[string]int)
+	for i := len(candidates) - 1; i >= 0; i-- {
+		candidate := candidates[i]
+		for _, repository := range candidate.repositories {
+			if perRepository[repository] < gc.keepPerRepository {
+				kept[candidate.id] = true
+			}
+			perRepository[repository]++
+		}
+	}
+	for _, candidate := range candidates {
+		if candidate.removable && len(candidate.repositories) > 0 && !kept[candidate.id] {
+			selected[candidate.id] = true
+		}
+	}
+}
+
+// selectBySize selects the least recently used images until the store size
+// is within the budget. The store size is approximated by the sum of all
+// image sizes, even if they share layers.
+func (gc *ImageGC) selectBySize(candidates []*imageGCCandidate, selected map[string]bool) error {
+	if gc.maxStoreSize <= 0 {
+		return nil
+	}
+	sizes := make(map[string]int64, len(candidates))
+	total := int64(0)
+	for _, candidate := range candidates {
+		size, err := gc.store.ImageSize(candidate.id)
+		if err != nil {
+			if errors.Is(err, storage.ErrImageUnknown) {
+				continue
+			}
+			return err
+		}
+		sizes[candidate.id] = size
+		if !selected[candidate.id] {
+			total += size
+		}
+	}
+	for _, candidate := range candidates {
+		if total <= gc.maxStoreSize {
+			return nil
+		}
+		if candidate.removable && !selected[candidate.id] {
+			selected[candidate.id] = true
+			total -= sizes[candidate.id]
+		}
+	}
+	return nil
+}
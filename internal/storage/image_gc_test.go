@@ -0,0 +1,269 @@
+package storage_test
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	cs "github.com/containers/storage"
+	"github.com/cri-o/cri-o/internal/storage"
+	"github.com/cri-o/cri-o/pkg/config"
+	containerstoragemock "github.com/cri-o/cri-o/test/mocks/containerstorage"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// The actual test suite
+var _ = t.Describe("ImageGC", func() {
+	const (
+		lastUsedKey = "crio-image-last-used"
+		pauseImage  = "registry.k8s.io/pause:3.9"
+		imageID1    = "1111111111111111111111111111111111111111111111111111111111111111"
+		imageID2    = "2222222222222222222222222222222222222222222222222222222222222222"
+		imageID3    = "3333333333333333333333333333333333333333333333333333333333333333"
+		imageID4    = "4444444444444444444444444444444444444444444444444444444444444444"
+	)
+
+	var (
+		mockCtrl    *gomock.Controller
+		storeMock   *containerstoragemock.MockStore
+		imageConfig *config.ImageConfig
+		inUse       map[string]bool
+		lastUsed    map[string]time.Time
+	)
+
+	// Prepare the system under test
+	BeforeEach(func() {
+		mockCtrl = gomock.NewController(GinkgoT())
+		storeMock = containerstoragemock.NewMockStore(mockCtrl)
+		imageConfig = &config.ImageConfig{
+			PauseImage:      pauseImage,
+			ImageGCInterval: time.Hour,
+		}
+		inUse = map[string]bool{}
+		lastUsed = map[string]time.Time{}
+
+		storeMock.EXPECT().ImageBigData(gomock.Any(), lastUsedKey).
+			DoAndReturn(func(id, key string) ([]byte, error) {
+				used, ok := lastUsed[id]
+				if !ok {
+					return nil, os.ErrNotExist
+				}
+				return used.MarshalText()
+			}).AnyTimes()
+	})
+	AfterEach(func() {
+		mockCtrl.Finish()
+	})
+
+	newSUT := func() *storage.ImageGC {
+		return storage.NewImageGC(storeMock, imageConfig, func() (map[string]bool, error) {
+			return inUse, nil
+		})
+	}
+
+	image := func(id string, created time.Time, names ...string) cs.Image {
+		return cs.Image{ID: id, Created: created, Names: names}
+	}
+
+	t.Describe("GarbageCollect", func() {
+		It("should remove images not used within the max age", func() {
+			// Given
+			imageConfig.ImageGCMaxAge = time.Hour
+			old := time.Now().Add(-2 * time.Hour)
+			inUse[imageID3] = true
+			lastUsed[imageID2] = time.Now()
+			storeMock.EXPECT().Images().Return([]cs.Image{
+				image(imageID1, old, "docker.io/library/unused:latest"),
+				image(imageID2, old, "docker.io/library/used:latest"),
+				image(imageID3, old, "docker.io/library/running:latest"),
+				image(imageID4, old, pauseImage),
+			}, nil)
+			storeMock.EXPECT().DeleteImage(imageID1, true).Return(nil, nil)
+
+			// When
+			removed, err := newSUT().GarbageCollect()
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(removed).To(Equal([]string{imageID1}))
+		})
+
+		It("should keep the most recently used images per repository", func() {
+			// Given
+			imageConfig.ImageGCKeepPerRepository = 2
+			now := time.Now()
+			lastUsed[imageID4] = now
+			storeMock.EXPECT().Images().Return([]cs.Image{
+				image(imageID1, now.Add(-4*time.Hour), "docker.io/library/image:1"),
+				image(imageID2, now.Add(-3*time.Hour), "docker.io/library/image:2", "docker.io/library/other:2"),
+				image(imageID3, now.Add(-2*time.Hour), "docker.io/library/image:3"),
+				image(imageID4, now.Add(-5*time.Hour), "docker.io/library/image:4"),
+			}, nil)
+			storeMock.EXPECT().DeleteImage(imageID1, true).Return(nil, nil)
+
+			// When
+			removed, err := newSUT().GarbageCollect()
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(removed).To(Equal([]string{imageID1}))
+		})
+
+		It("should remove the least recently used images above the size budget", func() {
+			// Given
+			imageConfig.ImageGCMaxStoreSize = 150
+			now := time.Now()
+			inUse[imageID1] = true
+			storeMock.EXPECT().Images().Return([]cs.Image{
+				image(imageID1, now.Add(-4*time.Hour), "docker.io/library/image:1"),
+				image(imageID2, now.Add(-3*time.Hour), "docker.io/library/image:2"),
+				image(imageID3, now.Add(-2*time.Hour), "docker.io/library/image:3"),
+				image(imageID4, now.Add(-1*time.Hour), "docker.io/library/image:4"),
+			}, nil)
+			storeMock.EXPECT().ImageSize(gomock.Any()).Return(int64(50), nil).Times(4)
+			storeMock.EXPECT().DeleteImage(imageID2, true).Return(nil, nil)
+
+			// When
+			removed, err := newSUT().GarbageCollect()
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(removed).To(Equal([]string{imageID2}))
+		})
+
+		It("should not remove images created or used within the interval", func() {
+			// Given
+			imageConfig.ImageGCMaxStoreSize = 50
+			now := time.Now()
+			lastUsed[imageID1] = now.Add(-10 * time.Minute)
+			storeMock.EXPECT().Images().Return([]cs.Image{
+				image(imageID1, now.Add(-4*time.Hour), "docker.io/library/image:1"),
+				image(imageID2, now.Add(-30*time.Minute), "docker.io/library/image:2"),
+				image(imageID3, now.Add(-3*time.Hour), "docker.io/library/image:3"),
+			}, nil)
+			storeMock.EXPECT().ImageSize(gomock.Any()).Return(int64(50), nil).Times(3)
+			storeMock.EXPECT().DeleteImage(imageID3, true).Return(nil, nil)
+
+			// When
+			removed, err := newSUT().GarbageCollect()
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(removed).To(Equal([]string{imageID3}))
+		})
+
+		It("should not remove images used during the garbage collection", func() {
+			// Given
+			imageConfig.ImageGCMaxStoreSize = 50
+			old := time.Now().Add(-2 * time.Hour)
+			id, err := storage.ParseStorageImageIDFromOutOfProcessData(imageID1)
+			Expect(err).ToNot(HaveOccurred())
+			sut := newSUT()
+			storeMock.EXPECT().Images().Return([]cs.Image{
+				image(imageID1, old, "docker.io/library/image:1"),
+				image(imageID2, old.Add(time.Minute), "docker.io/library/image:2"),
+			}, nil)
+			// the image gets used after it has been selected and its use
+			// cannot be persisted
+			storeMock.EXPECT().SetImageBigData(imageID1, lastUsedKey, gomock.Any(), nil).
+				Return(errors.New("read-only"))
+			storeMock.EXPECT().ImageSize(imageID1).Return(int64(50), nil)
+			storeMock.EXPECT().ImageSize(imageID2).DoAndReturn(func(string) (int64, error) {
+				sut.RecordImageUse(id)
+				return 50, nil
+			})
+
+			// When
+			removed, err := sut.GarbageCollect()
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(removed).To(BeEmpty())
+		})
+
+		It("should skip images which cannot be removed", func() {
+			// Given
+			imageConfig.ImageGCMaxAge = time.Hour
+			old := time.Now().Add(-2 * time.Hour)
+			storeMock.EXPECT().Images().Return([]cs.Image{
+				image(imageID1, old, "docker.io/library/image:1"),
+				image(imageID2, old.Add(time.Minute), "docker.io/library/image:2"),
+			}, nil)
+			storeMock.EXPECT().DeleteImage(imageID1, true).Return(nil, cs.ErrImageUsedByContainer)
+			storeMock.EXPECT().DeleteImage(imageID2, true).Return(nil, nil)
+
+			// When
+			removed, err := newSUT().GarbageCollect()
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(removed).To(Equal([]string{imageID2}))
+		})
+
+		It("should fail if the images in use are unknown", func() {
+			// Given
+			storeMock.EXPECT().Images().Return([]cs.Image{}, nil)
+			sut := storage.NewImageGC(storeMock, imageConfig, func() (map[string]bool, error) {
+				return nil, errors.New("error")
+			})
+
+			// When
+			removed, err := sut.GarbageCollect()
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(removed).To(BeNil())
+		})
+	})
+
+	t.Describe("RecordImageUse", func() {
+		It("should persist the last use", func() {
+			// Given
+			id, err := storage.ParseStorageImageIDFromOutOfProcessData(imageID1)
+			Expect(err).ToNot(HaveOccurred())
+			storeMock.EXPECT().SetImageBigData(imageID1, lastUsedKey, gomock.Any(), nil).Return(nil)
+
+			// When
+			newSUT().RecordImageUse(id)
+
+			// Then
+			// the expected mock call
+		})
+
+		It("should protect the image if the last use cannot be persisted", func() {
+			// Given
+			imageConfig.ImageGCMaxAge = time.Hour
+			id, err := storage.ParseStorageImageIDFromOutOfProcessData(imageID1)
+			Expect(err).ToNot(HaveOccurred())
+			storeMock.EXPECT().SetImageBigData(imageID1, lastUsedKey, gomock.Any(), nil).
+				Return(errors.New("read-only"))
+			storeMock.EXPECT().Images().Return([]cs.Image{
+				image(imageID1, time.Now().Add(-2*time.Hour), "docker.io/library/image:1"),
+			}, nil)
+			sut := newSUT()
+
+			// When
+			sut.RecordImageUse(id)
+			removed, err := sut.GarbageCollect()
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(removed).To(BeEmpty())
+		})
+
+		It("should not persist the last use if disabled", func() {
+			// Given
+			imageConfig.ImageGCInterval = 0
+			id, err := storage.ParseStorageImageIDFromOutOfProcessData(imageID1)
+			Expect(err).ToNot(HaveOccurred())
+
+			// When
+			newSUT().RecordImageUse(id)
+
+			// Then
+			// no mock call
+		})
+	})
+})
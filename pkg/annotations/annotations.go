@@ -60,6 +60,10 @@ const (
 	// SeccompNotifierActionStop indicates that a container should be stopped if used via the SeccompNotifierActionAnnotation key.
 	SeccompNotifierActionStop = "stop"
 
+	// SeccompNotifierActionRecord indicates that the syscalls allowed by the seccomp profile of a container should be
+	// recorded into a seccomp profile if used via the SeccompNotifierActionAnnotation key.
+	SeccompNotifierActionRecord = "record"
+
 	// PodLinuxOverhead indicates the overheads associated with the pod
 	PodLinuxOverhead = "io.kubernetes.cri-o.PodLinuxOverhead"
 
@@ -103,4 +107,5 @@ var AllAllowedAnnotations = []string{
 	LinkLogsAnnotation,
 	CPUSharedAnnotation,
 	SeccompProfileAnnotation,
+	CheckpointAnnotationPodArchive,
 }
@@ -29,4 +29,9 @@ const (
 	// creating a checkpoint image to specify the version of CRIU used on the
 	// host where the checkpoint was created.
 	CheckpointAnnotationCriuVersion = "io.kubernetes.cri-o.annotations.checkpoint.criu.version"
+
+	// CheckpointAnnotationPodArchive is used on a pod sandbox to restore the
+	// sandbox and its containers from the pod checkpoint archive at the
+	// provided path.
+	CheckpointAnnotationPodArchive = "io.kubernetes.cri-o.annotations.checkpoint.pod.archive"
 )
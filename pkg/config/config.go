@@ -12,6 +12,7 @@ import (
 	"regexp"
 	"slices"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/BurntSushi/toml"
@@ -21,6 +22,7 @@ import (
 	"github.com/containers/image/v5/types"
 	"github.com/containers/storage"
 	"github.com/containers/storage/pkg/unshare"
+	"github.com/cri-o/cri-o/internal/audit"
 	"github.com/cri-o/cri-o/internal/config/apparmor"
 	"github.com/cri-o/cri-o/internal/config/blockio"
 	"github.com/cri-o/cri-o/internal/config/capabilities"
@@ -36,6 +38,7 @@ import (
 	"github.com/cri-o/cri-o/internal/config/ulimits"
 	"github.com/cri-o/cri-o/internal/storage/references"
 	"github.com/cri-o/cri-o/pkg/annotations"
+	crioTypes "github.com/cri-o/cri-o/pkg/types"
 	"github.com/cri-o/cri-o/server/otel-collector/collectors"
 	"github.com/cri-o/cri-o/server/useragent"
 	"github.com/cri-o/cri-o/utils"
@@ -52,6 +55,8 @@ import (
 // Defaults if none are specified
 const (
 	defaultGRPCMaxMsgSize      = 80 * 1024 * 1024
+	defaultAuditLogMaxSize     = 100 * 1024 * 1024
+	defaultAuditLogMaxBackups  = 5
 	defaultContainerMinMemory  = 12 * 1024 * 1024 // 12 MiB
 	OCIBufSize                 = 8192
 	RuntimeTypeVM              = "vm"
@@ -71,6 +76,12 @@ type Config struct {
 	singleConfigPath string // Path to the single config file
 	dropInConfigDir  string // Path to the drop-in config files
 
+	// fileOptions are the options loaded from the config files
+	fileOptions map[string]string
+	// lastReload is the result of the last configuration reload, shared
+	// between all copies of the config
+	lastReload *atomic.Pointer[crioTypes.ReloadInfo]
+
 	RootConfig
 	APIConfig
 	RuntimeConfig
@@ -105,6 +116,16 @@ const (
 	// ImageVolumesBind option is for using bind mounted volumes
 )
 
+// HostPortMappingBackendType describes the backend used to program hostport mappings
+type HostPortMappingBackendType string
+
+const (
+	// HostPortMappingBackendIPTables programs hostport mappings using iptables
+	HostPortMappingBackendIPTables HostPortMappingBackendType = "iptables"
+	// HostPortMappingBackendNFTables programs hostport mappings natively using nftables
+	HostPortMappingBackendNFTables HostPortMappingBackendType = "nftables"
+)
+
 const (
 	// DefaultPidsLimit is the default value for maximum number of processes
 	// allowed inside a container
@@ -129,6 +150,10 @@ const (
 	DefaultIrqBalanceConfigRestoreFile = "/etc/sysconfig/orig_irq_banned_cpus"
 )
 
+// DefaultSeccompProfileRecordDir is the default directory for the seccomp
+// profiles recorded by the seccomp notifier.
+const DefaultSeccompProfileRecordDir = "/var/lib/crio/seccomp-profiles"
+
 // This structure is necessary to fake the TOML tables when parsing,
 // while also not requiring a bunch of layered structs for no good
 // reason.
@@ -321,6 +346,10 @@ type RuntimeConfig struct {
 	// The name is matched against the Runtimes map below.
 	DefaultRuntime string `toml:"default_runtime"`
 
+	// RuntimeHealthCheckInterval is the interval in which the runtime
+	// handlers get probed for their health. 0 disables the health checks.
+	RuntimeHealthCheckInterval time.Duration `toml:"runtime_health_check_interval"`
+
 	// DecryptionKeysPath is the path where keys for image decryption are stored.
 	DecryptionKeysPath string `toml:"decryption_keys_path"`
 
@@ -336,6 +365,10 @@ type RuntimeConfig struct {
 	// default for the runtime.
 	SeccompProfile string `toml:"seccomp_profile"`
 
+	// SeccompProfileRecordDir is the directory where the seccomp profiles
+	// recorded by the seccomp notifier "record" action are written to.
+	SeccompProfileRecordDir string `toml:"seccomp_profile_record_dir"`
+
 	// ApparmorProfile is the apparmor profile name which is used as the
 	// default for the runtime.
 	ApparmorProfile string `toml:"apparmor_profile"`
@@ -493,6 +526,10 @@ type RuntimeConfig struct {
 	// Default value is 'false'
 	DisableHostPortMapping bool `toml:"disable_hostport_mapping"`
 
+	// HostPortMappingBackend is the backend used to program the hostport mappings,
+	// either "iptables" or "nftables".
+	HostPortMappingBackend HostPortMappingBackendType `toml:"hostport_mapping_backend"`
+
 	// Option to set the timezone inside the container.
 	// Use 'Local' to match the timezone of the host machine.
 	Timezone string `toml:"timezone"`
@@ -523,6 +560,10 @@ type ImageConfig struct {
 	// Pinned images will remain in the container runtime's storage until
 	// they are manually removed. Default value: empty list (no images pinned)
 	PinnedImages []string `toml:"pinned_images"`
+	// PrePullImages is a list of container images which get pulled in the
+	// background on startup and on config reload, to make sure that they are
+	// present in the local storage.
+	PrePullImages []string `toml:"pre_pull_images"`
 	// SignaturePolicyPath is the name of the file which decides what sort
 	// of policy we use when deciding whether or not to trust an image that
 	// we've pulled.  Outside of testing situations, it is strongly advised
@@ -546,6 +587,26 @@ type ImageConfig struct {
 	Registries []string `toml:"registries"`
 	// Temporary directory for big files
 	BigFilesTemporaryDir string `toml:"big_files_temporary_dir"`
+	// MaxConcurrentImagePulls is the maximum amount of image pulls running
+	// at the same time. Further pulls are queued. 0 means unlimited.
+	MaxConcurrentImagePulls int `toml:"max_concurrent_image_pulls"`
+	// MaxConcurrentImagePullsPerRegistry is the maximum amount of image pulls
+	// from a single registry running at the same time. Further pulls are
+	// queued. 0 means unlimited.
+	MaxConcurrentImagePullsPerRegistry int `toml:"max_concurrent_image_pulls_per_registry"`
+	// ImageGCInterval is the interval in which unused images get garbage
+	// collected by CRI-O. 0 disables the image garbage collection.
+	ImageGCInterval time.Duration `toml:"image_gc_interval"`
+	// ImageGCMaxAge is the duration after which images not used by any
+	// container get garbage collected. 0 means no age limit.
+	ImageGCMaxAge time.Duration `toml:"image_gc_max_age"`
+	// ImageGCKeepPerRepository is the amount of most recently used images
+	// kept per repository. 0 means no limit.
+	ImageGCKeepPerRepository int `toml:"image_gc_keep_per_repository"`
+	// ImageGCMaxStoreSize is the size in bytes the images are allowed to
+	// occupy before the least recently used ones get garbage collected.
+	// 0 means no limit.
+	ImageGCMaxStoreSize int64 `toml:"image_gc_max_store_size"`
 }
 
 // NetworkConfig represents the "crio.network" TOML config table
@@ -600,6 +661,21 @@ type APIConfig struct {
 
 	// StreamIdleTimeout is how long to leave idle connections open for
 	StreamIdleTimeout string `toml:"stream_idle_timeout"`
+
+	// AuditLogPath is the path to the audit log of the CRI calls. An empty
+	// path disables the audit log.
+	AuditLogPath string `toml:"audit_log_path"`
+
+	// AuditLogMaxSize is the size in bytes after which the audit log gets
+	// rotated. A value of 0 disables the rotation.
+	AuditLogMaxSize int64 `toml:"audit_log_max_size"`
+
+	// AuditLogMaxBackups is the number of rotated audit logs to keep.
+	AuditLogMaxBackups int `toml:"audit_log_max_backups"`
+
+	// AuditLogPolicy overrides the audit level per CRI method, in the format
+	// "Method=level".
+	AuditLogPolicy []string `toml:"audit_log_policy"`
 }
 
 // MetricsConfig specifies all necessary configuration for Prometheus based
@@ -757,6 +833,13 @@ func (c *Config) UpdateFromDropInFile(path string) error {
 	}
 
 	t.toConfig(c)
+
+	// Keep the options of the files to detect changes on reload
+	options, err := c.options()
+	if err != nil {
+		return fmt.Errorf("unable to read options of configuration %v: %w", path, err)
+	}
+	c.fileOptions = options
 	return nil
 }
 
@@ -840,7 +923,8 @@ func DefaultConfig() (*Config, error) {
 		return nil, fmt.Errorf("get user agent: %w", err)
 	}
 	return &Config{
-		Comment: "# ",
+		Comment:    "# ",
+		lastReload: &atomic.Pointer[crioTypes.ReloadInfo]{},
 		SystemContext: &types.SystemContext{
 			DockerRegistryUserAgent: ua,
 		},
@@ -862,6 +946,8 @@ func DefaultConfig() (*Config, error) {
 			StreamPort:         "0",
 			GRPCMaxSendMsgSize: defaultGRPCMaxMsgSize,
 			GRPCMaxRecvMsgSize: defaultGRPCMaxMsgSize,
+			AuditLogMaxSize:    defaultAuditLogMaxSize,
+			AuditLogMaxBackups: defaultAuditLogMaxBackups,
 		},
 		RuntimeConfig: RuntimeConfig{
 			AllowedDevices:     []string{"/dev/fuse"},
@@ -891,6 +977,7 @@ func DefaultConfig() (*Config, error) {
 			NamespacesDir:               defaultNamespacesDir,
 			DropInfraCtr:                true,
 			IrqBalanceConfigRestoreFile: DefaultIrqBalanceConfigRestoreFile,
+			SeccompProfileRecordDir:     DefaultSeccompProfileRecordDir,
 			seccompConfig:               seccomp.New(),
 			apparmorConfig:              apparmor.New(),
 			blockioConfig:               blockio.New(),
@@ -901,6 +988,7 @@ func DefaultConfig() (*Config, error) {
 			ulimitsConfig:               ulimits.New(),
 			HostNetworkDisableSELinux:   true,
 			DisableHostPortMapping:      false,
+			HostPortMappingBackend:      HostPortMappingBackendIPTables,
 			EnableCriuSupport:           true,
 		},
 		ImageConfig: ImageConfig{
@@ -994,6 +1082,16 @@ func (c *APIConfig) Validate(onExecution bool) error {
 		c.GRPCMaxRecvMsgSize = defaultGRPCMaxMsgSize
 	}
 
+	if c.AuditLogMaxSize < 0 {
+		return errors.New("audit_log_max_size must not be negative")
+	}
+	if c.AuditLogMaxBackups < 0 {
+		return errors.New("audit_log_max_backups must not be negative")
+	}
+	if _, err := audit.ParsePolicy(c.AuditLogPolicy); err != nil {
+		return err
+	}
+
 	if onExecution {
 		return RemoveUnusedSocket(c.Listen)
 	}
@@ -1079,6 +1177,16 @@ func (c *RuntimeConfig) Validate(systemContext *types.SystemContext, onExecution
 		}
 	}
 
+	switch c.HostPortMappingBackend {
+	case HostPortMappingBackendIPTables, HostPortMappingBackendNFTables:
+	default:
+		return fmt.Errorf("unrecognized hostport_mapping_backend %q", c.HostPortMappingBackend)
+	}
+
+	if c.RuntimeHealthCheckInterval < 0 {
+		return fmt.Errorf("runtime health check interval %v must not be negative", c.RuntimeHealthCheckInterval)
+	}
+
 	if c.LogSizeMax >= 0 && c.LogSizeMax < OCIBufSize {
 		return fmt.Errorf("log size max should be negative or >= %d", OCIBufSize)
 	}
@@ -1192,6 +1300,8 @@ func (c *RuntimeConfig) Validate(systemContext *types.SystemContext, onExecution
 			}
 		}
 
+		c.seccompConfig.SetProfileRecordDir(c.SeccompProfileRecordDir)
+
 		if err := c.apparmorConfig.LoadProfile(c.ApparmorProfile); err != nil {
 			return fmt.Errorf("unable to load AppArmor profile: %w", err)
 		}
@@ -1478,6 +1588,20 @@ func (c *ImageConfig) Validate(onExecution bool) error {
 	if _, err := c.ParsePauseImage(); err != nil {
 		return fmt.Errorf("invalid pause image %q: %w", c.PauseImage, err)
 	}
+	for _, image := range c.PrePullImages {
+		if _, err := references.ParseRegistryImageReferenceFromOutOfProcessData(image); err != nil {
+			return fmt.Errorf("invalid pre pull image %q: %w", image, err)
+		}
+	}
+	if c.MaxConcurrentImagePulls < 0 {
+		return fmt.Errorf("max concurrent image pulls %d must not be negative", c.MaxConcurrentImagePulls)
+	}
+	if c.MaxConcurrentImagePullsPerRegistry < 0 {
+		return fmt.Errorf("max concurrent image pulls per registry %d must not be negative", c.MaxConcurrentImagePullsPerRegistry)
+	}
+	if c.ImageGCInterval < 0 || c.ImageGCMaxAge < 0 || c.ImageGCKeepPerRepository < 0 || c.ImageGCMaxStoreSize < 0 {
+		return errors.New("image garbage collection options must not be negative")
+	}
 	if onExecution {
 		if err := os.MkdirAll(c.SignaturePolicyDir, 0o755); err != nil {
 			return fmt.Errorf("cannot create signature policy dir: %w", err)
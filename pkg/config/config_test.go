@@ -5,6 +5,7 @@ import (
 	"os/exec"
 	"path"
 	"path/filepath"
+	"time"
 
 	"github.com/containers/storage"
 	crioann "github.com/cri-o/cri-o/pkg/annotations"
@@ -163,6 +164,28 @@ var _ = t.Describe("Config", func() {
 			Expect(err).ToNot(HaveOccurred())
 		})
 
+		It("should fail with negative AuditLogMaxSize", func() {
+			// Given
+			sut.AuditLogMaxSize = -1
+
+			// When
+			err := sut.APIConfig.Validate(false)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail with invalid AuditLogPolicy", func() {
+			// Given
+			sut.AuditLogPolicy = []string{"ExecSync=invalid"}
+
+			// When
+			err := sut.APIConfig.Validate(false)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
 		It("should fail on invalid Listen directory", func() {
 			// Given
 			sut = runtimeValidConfig()
@@ -420,6 +443,17 @@ var _ = t.Describe("Config", func() {
 			Expect(sut.DefaultRuntime).To(Equal("runc"))
 		})
 
+		It("should fail on negative runtime health check interval", func() {
+			// Given
+			sut.RuntimeHealthCheckInterval = -time.Second
+
+			// When
+			err := sut.RuntimeConfig.Validate(nil, false)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
 		It("should fail on invalid default_sysctls", func() {
 			// Given
 			sut.DefaultSysctls = []string{invalid}
@@ -762,6 +796,28 @@ var _ = t.Describe("Config", func() {
 			Expect(err).To(HaveOccurred())
 		})
 
+		It("should fail when MaxConcurrentImagePulls is negative", func() {
+			// Given
+			sut.ImageConfig.MaxConcurrentImagePulls = -1
+
+			// When
+			err := sut.ImageConfig.Validate(false)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("should fail when MaxConcurrentImagePullsPerRegistry is negative", func() {
+			// Given
+			sut.ImageConfig.MaxConcurrentImagePullsPerRegistry = -1
+
+			// When
+			err := sut.ImageConfig.Validate(false)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+		})
+
 		It("should fail when PauseImage is invalid", func() {
 			// Given
 			sut.ImageConfig.PauseImage = "//NOT:a valid image reference!"
@@ -1,32 +1,142 @@
 package config
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
+	"slices"
 	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
 
+	"github.com/BurntSushi/toml"
 	"github.com/containers/image/v5/pkg/sysregistriesv2"
+	"github.com/cri-o/cri-o/internal/config/seccomp"
+	"github.com/cri-o/cri-o/internal/config/ulimits"
 	"github.com/cri-o/cri-o/internal/log"
+	"github.com/cri-o/cri-o/internal/storage/references"
+	crioTypes "github.com/cri-o/cri-o/pkg/types"
 	"github.com/sirupsen/logrus"
 	"tags.cncf.io/container-device-interface/pkg/cdi"
 )
 
+// reloadableOptions are the options applied by a configuration reload. All
+// other changed options require a restart of CRI-O. Tables like the runtimes
+// match all their nested options.
+var reloadableOptions = []string{
+	"crio.image.pause_command",
+	"crio.image.pause_image",
+	"crio.image.pause_image_auth_file",
+	"crio.image.pinned_images",
+	"crio.image.pre_pull_images",
+	"crio.runtime.apparmor_profile",
+	"crio.runtime.blockio_config_file",
+	"crio.runtime.blockio_reload",
+	"crio.runtime.cdi_spec_dirs",
+	"crio.runtime.decryption_keys_path",
+	"crio.runtime.default_capabilities",
+	"crio.runtime.default_runtime",
+	"crio.runtime.default_sysctls",
+	"crio.runtime.default_ulimits",
+	"crio.runtime.log_filter",
+	"crio.runtime.log_level",
+	"crio.runtime.pids_limit",
+	"crio.runtime.rdt_config_file",
+	"crio.runtime.runtimes",
+	"crio.runtime.seccomp_profile",
+	"crio.runtime.workloads",
+}
+
 // Reload reloads the configuration for the single crio.conf and the drop-in
-// configuration directory.
-func (c *Config) Reload() error {
+// configuration directory. The new configuration gets validated as a whole
+// before it gets applied. If applying an option fails, then all already
+// applied options are reverted to their previous values.
+func (c *Config) Reload() (retErr error) {
 	logrus.Infof("Reloading configuration")
 
+	info := &crioTypes.ReloadInfo{
+		Time:            time.Now().UnixNano(),
+		Changes:         []crioTypes.ConfigChange{},
+		RestartRequired: []string{},
+	}
+	defer func() {
+		info.Success = retErr == nil
+		if retErr != nil {
+			info.Error = retErr.Error()
+		}
+		if c.lastReload == nil {
+			c.lastReload = &atomic.Pointer[crioTypes.ReloadInfo]{}
+		}
+		c.lastReload.Store(info)
+	}()
+
 	// Reload the config
+	newConfig, err := c.loadReloadConfig()
+	if err != nil {
+		return err
+	}
+
+	newOptions, err := newConfig.loadedOptions()
+	if err != nil {
+		return err
+	}
+	info.Changes, info.RestartRequired, err = c.diffOptions(newOptions)
+	if err != nil {
+		return err
+	}
+	for _, change := range info.Changes {
+		logrus.Infof("Changed config %s from %s to %s", change.Option, change.OldValue, change.NewValue)
+	}
+	for _, option := range info.RestartRequired {
+		logrus.Warnf("Changed config %s requires a restart to be applied", option)
+	}
+
+	if err := c.validateReload(newConfig); err != nil {
+		return fmt.Errorf("validate new configuration: %w", err)
+	}
+
+	// The registries are read from their own config file and cannot be
+	// reverted, which is why they get reloaded before any other option.
+	if err := c.ReloadRegistries(); err != nil {
+		return err
+	}
+
+	previous := c.reloadSnapshot()
+	if err := c.applyReload(newConfig); err != nil {
+		logrus.Errorf("Unable to apply new configuration, reverting to the previous one: %v", err)
+		if err := c.revertReload(previous); err != nil {
+			logrus.Errorf("Unable to revert configuration: %v", err)
+		}
+		return err
+	}
+
+	c.fileOptions = newOptions
+	return nil
+}
+
+// LastReload returns the result of the last configuration reload or nil if
+// the configuration has not been reloaded yet.
+func (c *Config) LastReload() *crioTypes.ReloadInfo {
+	if c.lastReload == nil {
+		return nil
+	}
+	return c.lastReload.Load()
+}
+
+// loadReloadConfig reads the new configuration from the single crio.conf and
+// the drop-in configuration directory.
+func (c *Config) loadReloadConfig() (*Config, error) {
 	newConfig, err := DefaultConfig()
 	if err != nil {
-		return errors.New("unable to create default config")
+		return nil, errors.New("unable to create default config")
 	}
 
 	if _, err := os.Stat(c.singleConfigPath); !os.IsNotExist(err) {
 		logrus.Infof("Updating config from file %s", c.singleConfigPath)
 		if err := newConfig.UpdateFromFile(c.singleConfigPath); err != nil {
-			return err
+			return nil, err
 		}
 	} else {
 		logrus.Infof("Skipping not-existing config file %q", c.singleConfigPath)
@@ -35,13 +145,113 @@ func (c *Config) Reload() error {
 	if _, err := os.Stat(c.dropInConfigDir); !os.IsNotExist(err) {
 		logrus.Infof("Updating config from path %s", c.dropInConfigDir)
 		if err := newConfig.UpdateFromPath(c.dropInConfigDir); err != nil {
-			return err
+			return nil, err
 		}
 	} else {
 		logrus.Infof("Skipping not-existing config path %q", c.dropInConfigDir)
 	}
 
-	// Reload all available options
+	return newConfig, nil
+}
+
+// validateReload validates all reloadable options of the new config without
+// applying them.
+func (c *Config) validateReload(newConfig *Config) error {
+	if _, err := logrus.ParseLevel(newConfig.LogLevel); err != nil {
+		return err
+	}
+	if _, err := log.NewFilterHook(newConfig.LogFilter); err != nil {
+		return err
+	}
+	if _, err := newConfig.ParsePauseImage(); err != nil {
+		return err
+	}
+	if newConfig.PauseImageAuthFile != "" {
+		if _, err := os.Stat(newConfig.PauseImageAuthFile); err != nil {
+			return err
+		}
+	}
+	for _, image := range newConfig.PrePullImages {
+		if _, err := references.ParseRegistryImageReferenceFromOutOfProcessData(image); err != nil {
+			return fmt.Errorf("invalid pre pull image %q: %w", image, err)
+		}
+	}
+
+	// The new config has its own seccomp configuration, which makes it
+	// possible to load the profile without applying it.
+	if err := newConfig.seccompConfig.LoadProfile(newConfig.SeccompProfile); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("unable to load seccomp profile: %w", err)
+	}
+
+	if !RuntimesEqual(c.Runtimes, newConfig.Runtimes) || c.DefaultRuntime != newConfig.DefaultRuntime {
+		if err := newConfig.ValidateDefaultRuntime(); err != nil {
+			return fmt.Errorf("unable to reload runtimes: %w", err)
+		}
+		if err := newConfig.ValidateRuntimes(); err != nil {
+			return fmt.Errorf("unable to reload runtimes: %w", err)
+		}
+	}
+
+	if err := newConfig.Workloads.Validate(); err != nil {
+		return fmt.Errorf("invalid workloads: %w", err)
+	}
+	if _, err := newConfig.Sysctls(); err != nil {
+		return fmt.Errorf("invalid default_sysctls: %w", err)
+	}
+	if err := ulimits.New().LoadUlimits(newConfig.DefaultUlimits); err != nil {
+		return fmt.Errorf("invalid default_ulimits: %w", err)
+	}
+	if err := newConfig.DefaultCapabilities.Validate(); err != nil {
+		return fmt.Errorf("invalid capabilities: %w", err)
+	}
+
+	return nil
+}
+
+// applyReload applies all reloadable options of the new config.
+func (c *Config) applyReload(newConfig *Config) error {
+	if err := c.applyReloadOptions(newConfig); err != nil {
+		return err
+	}
+	if err := c.ReloadSeccompProfile(newConfig); err != nil {
+		return err
+	}
+	if err := c.ReloadBlockIOConfig(newConfig); err != nil {
+		return err
+	}
+	if err := c.ReloadRdtConfig(newConfig); err != nil {
+		return err
+	}
+	return c.ReloadCDISpecDirs(newConfig)
+}
+
+// revertReload reverts all reloadable options to the ones of the snapshot.
+// The seccomp, blockio and RDT configurations loaded before get swapped back
+// instead of loading their files again, which could have changed meanwhile.
+func (c *Config) revertReload(previous *Config) error {
+	c.seccompConfig = previous.seccompConfig
+	c.SeccompProfile = previous.SeccompProfile
+	c.blockioConfig = previous.blockioConfig
+	c.BlockIOConfigFile = previous.BlockIOConfigFile
+	c.BlockIOReload = previous.BlockIOReload
+	c.rdtConfig = previous.rdtConfig
+	c.RdtConfigFile = previous.RdtConfigFile
+
+	if err := c.blockioConfig.Activate(); err != nil {
+		return err
+	}
+	if err := c.rdtConfig.Activate(); err != nil {
+		return err
+	}
+	if err := c.ReloadCDISpecDirs(previous); err != nil {
+		return err
+	}
+	return c.applyReloadOptions(previous)
+}
+
+// applyReloadOptions applies all reloadable options of the new config, which
+// are not loaded from their own files.
+func (c *Config) applyReloadOptions(newConfig *Config) error {
 	if err := c.ReloadLogLevel(newConfig); err != nil {
 		return err
 	}
@@ -52,29 +262,143 @@ func (c *Config) Reload() error {
 		return err
 	}
 	c.ReloadPinnedImages(newConfig)
-	if err := c.ReloadRegistries(); err != nil {
+	if err := c.ReloadPrePullImages(newConfig); err != nil {
 		return err
 	}
 	c.ReloadDecryptionKeyConfig(newConfig)
-	if err := c.ReloadSeccompProfile(newConfig); err != nil {
+	if err := c.ReloadAppArmorProfile(newConfig); err != nil {
 		return err
 	}
-	if err := c.ReloadAppArmorProfile(newConfig); err != nil {
+	if err := c.ReloadRuntimes(newConfig); err != nil {
 		return err
 	}
-	if err := c.ReloadBlockIOConfig(newConfig); err != nil {
+	if err := c.ReloadWorkloads(newConfig); err != nil {
 		return err
 	}
-	if err := c.ReloadRdtConfig(newConfig); err != nil {
+	if err := c.ReloadDefaultSysctls(newConfig); err != nil {
 		return err
 	}
-	if err := c.ReloadRuntimes(newConfig); err != nil {
+	if err := c.ReloadDefaultUlimits(newConfig); err != nil {
 		return err
 	}
-	if err := cdi.Configure(cdi.WithSpecDirs(newConfig.CDISpecDirs...)); err != nil {
+	if err := c.ReloadDefaultCapabilities(newConfig); err != nil {
 		return err
 	}
+	c.ReloadPidsLimit(newConfig)
+	return nil
+}
+
+// reloadSnapshot returns a config containing the current values of all
+// reloadable options, which can be used to revert a failed reload. The
+// reload functions replace the loaded seccomp, blockio and RDT configurations
+// instead of changing them, so the snapshot keeps the ones loaded before.
+func (c *Config) reloadSnapshot() *Config {
+	return &Config{
+		RootConfig:    c.RootConfig,
+		RuntimeConfig: c.RuntimeConfig,
+		ImageConfig:   c.ImageConfig,
+	}
+}
+
+// options returns all options of the config in their TOML representation,
+// indexed by their full key like "crio.runtime.log_level".
+func (c *Config) options() (map[string]string, error) {
+	b, err := c.ToBytes()
+	if err != nil {
+		return nil, err
+	}
+	tree := map[string]any{}
+	if _, err := toml.Decode(string(b), &tree); err != nil {
+		return nil, err
+	}
+	res := map[string]string{}
+	if err := flattenOptions(res, "", tree); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// loadedOptions returns the options loaded from the config files or the
+// default options if the config has not been loaded from files. Options set
+// by command line flags are not part of them, because a reload only reads the
+// config files.
+func (c *Config) loadedOptions() (map[string]string, error) {
+	if c.fileOptions != nil {
+		return c.fileOptions, nil
+	}
+	defaultConfig, err := DefaultConfig()
+	if err != nil {
+		return nil, errors.New("unable to create default config")
+	}
+	return defaultConfig.options()
+}
+
+// diffOptions returns the options changed compared to the currently loaded
+// config files, as well as the changed options which require a restart.
+func (c *Config) diffOptions(newOptions map[string]string) (changes []crioTypes.ConfigChange, restartRequired []string, err error) {
+	oldOptions, err := c.loadedOptions()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keys := []string{}
+	for key := range oldOptions {
+		keys = append(keys, key)
+	}
+	for key := range newOptions {
+		if _, ok := oldOptions[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
 
+	changes = []crioTypes.ConfigChange{}
+	restartRequired = []string{}
+	for _, key := range keys {
+		oldValue, newValue := oldOptions[key], newOptions[key]
+		if oldValue == newValue {
+			continue
+		}
+		changes = append(changes, crioTypes.ConfigChange{
+			Option:   key,
+			OldValue: oldValue,
+			NewValue: newValue,
+		})
+		if !isReloadableOption(key) {
+			restartRequired = append(restartRequired, key)
+		}
+	}
+	return changes, restartRequired, nil
+}
+
+// isReloadableOption returns true if the option gets applied on reload.
+func isReloadableOption(key string) bool {
+	for _, option := range reloadableOptions {
+		if key == option || strings.HasPrefix(key, option+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// flattenOptions adds all leaf values of the TOML tree to the result map.
+func flattenOptions(res map[string]string, prefix string, tree map[string]any) error {
+	for key, value := range tree {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if table, ok := value.(map[string]any); ok {
+			if err := flattenOptions(res, key, table); err != nil {
+				return err
+			}
+			continue
+		}
+		b, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("marshal option %s: %w", key, err)
+		}
+		res[key] = string(b)
+	}
 	return nil
 }
 
@@ -158,6 +482,21 @@ func (c *Config) ReloadPinnedImages(newConfig *Config) {
 	c.PinnedImages = updatedPinnedImages
 }
 
+// ReloadPrePullImages updates the PrePullImages with the provided `newConfig`.
+func (c *Config) ReloadPrePullImages(newConfig *Config) error {
+	if slices.Equal(c.PrePullImages, newConfig.PrePullImages) {
+		return nil
+	}
+	for _, image := range newConfig.PrePullImages {
+		if _, err := references.ParseRegistryImageReferenceFromOutOfProcessData(image); err != nil {
+			return fmt.Errorf("invalid pre pull image %q: %w", image, err)
+		}
+	}
+	c.PrePullImages = newConfig.PrePullImages
+	logConfig("pre_pull_images", strings.Join(c.PrePullImages, ", "))
+	return nil
+}
+
 // ReloadRegistries reloads the registry configuration from the Configs
 // `SystemContext`. The method errors in case of any update failure.
 func (c *Config) ReloadRegistries() error {
@@ -183,21 +522,27 @@ func (c *Config) ReloadDecryptionKeyConfig(newConfig *Config) {
 }
 
 // ReloadSeccompProfile reloads the seccomp profile from the new config if
-// their paths differ.
+// their paths differ. The profile gets loaded into a new seccomp
+// configuration, which replaces the current one on success.
 func (c *Config) ReloadSeccompProfile(newConfig *Config) error {
+	seccompConfig := seccomp.New()
+	seccompConfig.SetNotifierPath(c.seccompConfig.NotifierPath())
+	seccompConfig.SetProfileRecordDir(c.seccompConfig.ProfileRecordDir())
+
 	// Reload the seccomp profile in any case because its content could have
 	// changed as well
-	if err := c.seccompConfig.LoadProfile(newConfig.SeccompProfile); err != nil {
+	if err := seccompConfig.LoadProfile(newConfig.SeccompProfile); err != nil {
 		if !errors.Is(err, os.ErrNotExist) {
 			return fmt.Errorf("unable to load seccomp profile: %w", err)
 		}
 
 		logrus.Info("Specified profile does not exist on disk")
-		if err := c.seccompConfig.LoadDefaultProfile(); err != nil {
+		if err := seccompConfig.LoadDefaultProfile(); err != nil {
 			return fmt.Errorf("load default seccomp profile: %w", err)
 		}
 	}
 
+	c.seccompConfig = seccompConfig
 	c.SeccompProfile = newConfig.SeccompProfile
 	logConfig("seccomp_profile", c.SeccompProfile)
 	return nil
@@ -217,33 +562,58 @@ func (c *Config) ReloadAppArmorProfile(newConfig *Config) error {
 }
 
 // ReloadBlockIOConfig reloads the blockio configuration from the new config
+// into a copy of the current one, which replaces it on success.
 func (c *Config) ReloadBlockIOConfig(newConfig *Config) error {
+	if c.BlockIOConfigFile == newConfig.BlockIOConfigFile && c.BlockIOReload == newConfig.BlockIOReload {
+		return nil
+	}
+	blockioConfig := c.BlockIO().Copy()
 	if c.BlockIOConfigFile != newConfig.BlockIOConfigFile {
-		if err := c.BlockIO().Load(newConfig.BlockIOConfigFile); err != nil {
+		if err := blockioConfig.Load(newConfig.BlockIOConfigFile); err != nil {
 			return fmt.Errorf("unable to reload blockio_config_file: %w", err)
 		}
-		c.BlockIOConfigFile = newConfig.BlockIOConfigFile
-		logConfig("blockio_config_file", c.BlockIOConfigFile)
+		logConfig("blockio_config_file", newConfig.BlockIOConfigFile)
 	}
 	if c.BlockIOReload != newConfig.BlockIOReload {
-		c.BlockIOReload = newConfig.BlockIOReload
-		logConfig("blockio_reload", strconv.FormatBool(c.BlockIOReload))
+		blockioConfig.SetReload(newConfig.BlockIOReload)
+		logConfig("blockio_reload", strconv.FormatBool(newConfig.BlockIOReload))
 	}
+	c.blockioConfig = blockioConfig
+	c.BlockIOConfigFile = newConfig.BlockIOConfigFile
+	c.BlockIOReload = newConfig.BlockIOReload
 	return nil
 }
 
-// ReloadRdtConfig reloads the RDT configuration if changed
+// ReloadRdtConfig reloads the RDT configuration if changed into a copy of the
+// current one, which replaces it on success.
 func (c *Config) ReloadRdtConfig(newConfig *Config) error {
 	if c.RdtConfigFile != newConfig.RdtConfigFile {
-		if err := c.Rdt().Load(newConfig.RdtConfigFile); err != nil {
+		rdtConfig := c.Rdt().Copy()
+		if err := rdtConfig.Load(newConfig.RdtConfigFile); err != nil {
 			return fmt.Errorf("unable to reload rdt_config_file: %w", err)
 		}
+		c.rdtConfig = rdtConfig
 		c.RdtConfigFile = newConfig.RdtConfigFile
 		logConfig("rdt_config_file", c.RdtConfigFile)
 	}
 	return nil
 }
 
+// ReloadCDISpecDirs reconfigures the CDI spec directories if changed. The CDI
+// cache gets refreshed from the directories before injecting devices anyway,
+// which is why there is no loaded state to keep.
+func (c *Config) ReloadCDISpecDirs(newConfig *Config) error {
+	if slices.Equal(c.CDISpecDirs, newConfig.CDISpecDirs) {
+		return nil
+	}
+	if err := cdi.Configure(cdi.WithSpecDirs(newConfig.CDISpecDirs...)); err != nil {
+		return fmt.Errorf("unable to reload cdi_spec_dirs: %w", err)
+	}
+	c.CDISpecDirs = newConfig.CDISpecDirs
+	logConfig("cdi_spec_dirs", strings.Join(c.CDISpecDirs, ", "))
+	return nil
+}
+
 // ReloadRuntimes reloads the runtimes configuration if changed
 func (c *Config) ReloadRuntimes(newConfig *Config) error {
 	var updated bool
@@ -272,3 +642,68 @@ func (c *Config) ReloadRuntimes(newConfig *Config) error {
 
 	return nil
 }
+
+// ReloadWorkloads reloads the workloads configuration if changed. The new
+// workloads only apply to newly created sandboxes and containers.
+func (c *Config) ReloadWorkloads(newConfig *Config) error {
+	if WorkloadsEqual(c.Workloads, newConfig.Workloads) {
+		return nil
+	}
+	if err := newConfig.Workloads.Validate(); err != nil {
+		return fmt.Errorf("unable to reload workloads: %w", err)
+	}
+	logrus.Infof("Updating workloads configuration")
+	c.Workloads = newConfig.Workloads
+	return nil
+}
+
+// ReloadDefaultSysctls reloads the default sysctls if changed.
+func (c *Config) ReloadDefaultSysctls(newConfig *Config) error {
+	if slices.Equal(c.DefaultSysctls, newConfig.DefaultSysctls) {
+		return nil
+	}
+	if _, err := newConfig.Sysctls(); err != nil {
+		return fmt.Errorf("unable to reload default_sysctls: %w", err)
+	}
+	c.DefaultSysctls = newConfig.DefaultSysctls
+	logConfig("default_sysctls", strings.Join(c.DefaultSysctls, ", "))
+	return nil
+}
+
+// ReloadDefaultUlimits reloads the default ulimits if changed. The ulimits
+// get parsed into a new ulimits configuration, which keeps the current one
+// untouched on failure.
+func (c *Config) ReloadDefaultUlimits(newConfig *Config) error {
+	if slices.Equal(c.DefaultUlimits, newConfig.DefaultUlimits) {
+		return nil
+	}
+	ulimitsConfig := ulimits.New()
+	if err := ulimitsConfig.LoadUlimits(newConfig.DefaultUlimits); err != nil {
+		return fmt.Errorf("unable to reload default_ulimits: %w", err)
+	}
+	c.ulimitsConfig = ulimitsConfig
+	c.DefaultUlimits = newConfig.DefaultUlimits
+	logConfig("default_ulimits", strings.Join(c.DefaultUlimits, ", "))
+	return nil
+}
+
+// ReloadDefaultCapabilities reloads the default capabilities if changed.
+func (c *Config) ReloadDefaultCapabilities(newConfig *Config) error {
+	if slices.Equal(c.DefaultCapabilities, newConfig.DefaultCapabilities) {
+		return nil
+	}
+	if err := newConfig.DefaultCapabilities.Validate(); err != nil {
+		return fmt.Errorf("unable to reload default_capabilities: %w", err)
+	}
+	c.DefaultCapabilities = newConfig.DefaultCapabilities
+	logConfig("default_capabilities", strings.Join(c.DefaultCapabilities, ", "))
+	return nil
+}
+
+// ReloadPidsLimit updates the PidsLimit with the provided `newConfig`.
+func (c *Config) ReloadPidsLimit(newConfig *Config) {
+	if c.PidsLimit != newConfig.PidsLimit {
+		c.PidsLimit = newConfig.PidsLimit
+		logConfig("pids_limit", strconv.FormatInt(c.PidsLimit, 10))
+	}
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/containers/common/pkg/apparmor"
 	"github.com/cri-o/cri-o/pkg/config"
+	"github.com/cri-o/cri-o/pkg/types"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -82,6 +83,111 @@ var _ = t.Describe("Config", func() {
 			// Then
 			Expect(err).ToNot(HaveOccurred())
 		})
+
+		It("should record the changed options", func() {
+			// Given
+			modifyDefaultConfig(
+				`log_level = "info"`,
+				`log_level = "fatal"`,
+			)
+
+			// When
+			err := sut.Reload()
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sut.LogLevel).To(Equal("fatal"))
+			info := sut.LastReload()
+			Expect(info).NotTo(BeNil())
+			Expect(info.Success).To(BeTrue())
+			Expect(info.Error).To(BeEmpty())
+			Expect(info.Changes).To(ConsistOf(types.ConfigChange{
+				Option:   "crio.runtime.log_level",
+				OldValue: `"info"`,
+				NewValue: `"fatal"`,
+			}))
+			Expect(info.RestartRequired).To(BeEmpty())
+		})
+
+		It("should record the options which require a restart", func() {
+			// Given
+			modifyDefaultConfig(
+				`log_size_max = -1`,
+				`log_size_max = 16384`,
+			)
+
+			// When
+			err := sut.Reload()
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sut.LogSizeMax).To(BeEquivalentTo(-1))
+			info := sut.LastReload()
+			Expect(info).NotTo(BeNil())
+			Expect(info.Success).To(BeTrue())
+			Expect(info.RestartRequired).To(Equal([]string{"crio.runtime.log_size_max"}))
+		})
+
+		It("should not apply any option if the validation fails", func() {
+			// Given
+			modifyDefaultConfig(
+				`log_level = "info"`,
+				`log_level = "fatal"`,
+			)
+			modifyDefaultConfig(
+				`pause_image_auth_file = ""`,
+				`pause_image_auth_file = "`+invalidPath+`"`,
+			)
+
+			// When
+			err := sut.Reload()
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(sut.LogLevel).To(Equal("info"))
+			info := sut.LastReload()
+			Expect(info).NotTo(BeNil())
+			Expect(info.Success).To(BeFalse())
+			Expect(info.Error).To(Equal(err.Error()))
+		})
+		It("should keep the loaded blockio config if the reload fails", func() {
+			// Given
+			filePath := t.MustTempFile("blockio")
+			Expect(os.WriteFile(filePath, []byte("classes:\n  lowprio:\n  - Weight: 20\n"), 0o644)).To(Succeed())
+			sut.BlockIOConfigFile = filePath
+			Expect(sut.BlockIO().Load(filePath)).To(Succeed())
+			blockioConfig := sut.BlockIO()
+			modifyDefaultConfig(
+				`blockio_config_file = "`+filePath+`"`,
+				`blockio_config_file = "`+invalidPath+`"`,
+			)
+
+			// When
+			err := sut.Reload()
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(sut.BlockIOConfigFile).To(Equal(filePath))
+			Expect(sut.BlockIO()).To(BeIdenticalTo(blockioConfig))
+			Expect(sut.BlockIO().Enabled()).To(BeTrue())
+		})
+
+		It("should not record options which are not set in config files", func() {
+			// Given
+			sut.SetSingleConfigPath(invalidPath)
+			sut.LogSizeMax = 16384
+
+			// When
+			err := sut.Reload()
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sut.LogSizeMax).To(BeEquivalentTo(16384))
+			info := sut.LastReload()
+			Expect(info).NotTo(BeNil())
+			Expect(info.Changes).To(BeEmpty())
+			Expect(info.RestartRequired).To(BeEmpty())
+		})
 	})
 
 	t.Describe("ReloadLogLevel", func() {
@@ -439,4 +545,164 @@ var _ = t.Describe("Config", func() {
 			Expect(sut.PinnedImages).To(Equal([]string{"image1", "image2", "image3"}))
 		})
 	})
+
+	t.Describe("ReloadPrePullImages", func() {
+		It("should update PrePullImages with newConfig's PrePullImages if they are different", func() {
+			sut.PrePullImages = []string{"docker.io/library/image1:latest"}
+			newConfig := &config.Config{}
+			newConfig.PrePullImages = []string{"docker.io/library/image2:latest"}
+			Expect(sut.ReloadPrePullImages(newConfig)).To(Succeed())
+			Expect(sut.PrePullImages).To(Equal([]string{"docker.io/library/image2:latest"}))
+		})
+
+		It("should fail if newConfig's PrePullImages are invalid", func() {
+			sut.PrePullImages = []string{"docker.io/library/image1:latest"}
+			newConfig := &config.Config{}
+			newConfig.PrePullImages = []string{"invalid:image:name"}
+			Expect(sut.ReloadPrePullImages(newConfig)).NotTo(Succeed())
+			Expect(sut.PrePullImages).To(Equal([]string{"docker.io/library/image1:latest"}))
+		})
+	})
+
+	t.Describe("ReloadWorkloads", func() {
+		It("should update the workloads", func() {
+			// Given
+			newConfig := &config.Config{}
+			newConfig.Workloads = config.Workloads{
+				"management": &config.WorkloadConfig{
+					ActivationAnnotation: "io.crio/management",
+					Resources:            &config.Resources{},
+				},
+			}
+
+			// When
+			err := sut.ReloadWorkloads(newConfig)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sut.Workloads).To(HaveKey("management"))
+		})
+
+		It("should fail for a workload without activation annotation", func() {
+			// Given
+			newConfig := &config.Config{}
+			newConfig.Workloads = config.Workloads{
+				"management": &config.WorkloadConfig{Resources: &config.Resources{}},
+			}
+
+			// When
+			err := sut.ReloadWorkloads(newConfig)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(sut.Workloads).To(BeEmpty())
+		})
+	})
+
+	t.Describe("ReloadDefaultSysctls", func() {
+		It("should update the default sysctls", func() {
+			// Given
+			newConfig := &config.Config{}
+			newConfig.DefaultSysctls = []string{"net.ipv4.ping_group_range=0 2147483647"}
+
+			// When
+			err := sut.ReloadDefaultSysctls(newConfig)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sut.DefaultSysctls).To(Equal(newConfig.DefaultSysctls))
+			sysctls, err := sut.Sysctls()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sysctls).To(HaveLen(1))
+		})
+
+		It("should fail for invalid default sysctls", func() {
+			// Given
+			newConfig := &config.Config{}
+			newConfig.DefaultSysctls = []string{"invalid"}
+
+			// When
+			err := sut.ReloadDefaultSysctls(newConfig)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(sut.DefaultSysctls).To(BeEmpty())
+		})
+	})
+
+	t.Describe("ReloadDefaultUlimits", func() {
+		It("should update the default ulimits", func() {
+			// Given
+			newConfig := &config.Config{}
+			newConfig.DefaultUlimits = []string{"nofile=1024:2048"}
+
+			// When
+			err := sut.ReloadDefaultUlimits(newConfig)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sut.DefaultUlimits).To(Equal(newConfig.DefaultUlimits))
+			Expect(sut.Ulimits()).To(HaveLen(1))
+			Expect(sut.Ulimits()[0].Name).To(Equal("RLIMIT_NOFILE"))
+			Expect(sut.Ulimits()[0].Soft).To(BeEquivalentTo(1024))
+			Expect(sut.Ulimits()[0].Hard).To(BeEquivalentTo(2048))
+		})
+
+		It("should fail for invalid default ulimits", func() {
+			// Given
+			newConfig := &config.Config{}
+			newConfig.DefaultUlimits = []string{"invalid=1024:2048"}
+
+			// When
+			err := sut.ReloadDefaultUlimits(newConfig)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(sut.DefaultUlimits).To(BeEmpty())
+			Expect(sut.Ulimits()).To(BeEmpty())
+		})
+	})
+
+	t.Describe("ReloadDefaultCapabilities", func() {
+		It("should update the default capabilities", func() {
+			// Given
+			newConfig := &config.Config{}
+			newConfig.DefaultCapabilities = []string{"CHOWN"}
+
+			// When
+			err := sut.ReloadDefaultCapabilities(newConfig)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sut.DefaultCapabilities).To(BeEquivalentTo([]string{"CHOWN"}))
+		})
+
+		It("should fail for invalid default capabilities", func() {
+			// Given
+			previous := sut.DefaultCapabilities
+			newConfig := &config.Config{}
+			newConfig.DefaultCapabilities = []string{"INVALID"}
+
+			// When
+			err := sut.ReloadDefaultCapabilities(newConfig)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(sut.DefaultCapabilities).To(Equal(previous))
+		})
+	})
+
+	t.Describe("ReloadPidsLimit", func() {
+		It("should update the pids limit", func() {
+			// Given
+			newConfig := &config.Config{}
+			newConfig.PidsLimit = 1024
+
+			// When
+			sut.ReloadPidsLimit(newConfig)
+
+			// Then
+			Expect(sut.PidsLimit).To(BeEquivalentTo(1024))
+		})
+	})
 })
@@ -5,6 +5,8 @@ import (
 	"reflect"
 	"strings"
 	"text/template"
+
+	"github.com/cri-o/cri-o/internal/config/nri"
 )
 
 // WriteTemplate write the configuration template to the provided writer
@@ -210,6 +212,26 @@ func initCrioTemplateConfig(c *Config) ([]*templateConfigValue, error) {
 			group:          crioAPIConfig,
 			isDefaultValue: simpleEqual(dc.GRPCMaxRecvMsgSize, c.GRPCMaxRecvMsgSize),
 		},
+		{
+			templateString: templateStringCrioAPIAuditLogPath,
+			group:          crioAPIConfig,
+			isDefaultValue: simpleEqual(dc.AuditLogPath, c.AuditLogPath),
+		},
+		{
+			templateString: templateStringCrioAPIAuditLogMaxSize,
+			group:          crioAPIConfig,
+			isDefaultValue: simpleEqual(dc.AuditLogMaxSize, c.AuditLogMaxSize),
+		},
+		{
+			templateString: templateStringCrioAPIAuditLogMaxBackups,
+			group:          crioAPIConfig,
+			isDefaultValue: simpleEqual(dc.AuditLogMaxBackups, c.AuditLogMaxBackups),
+		},
+		{
+			templateString: templateStringCrioAPIAuditLogPolicy,
+			group:          crioAPIConfig,
+			isDefaultValue: stringSliceEqual(dc.AuditLogPolicy, c.AuditLogPolicy),
+		},
 		{
 			templateString: templateStringCrioRuntimeDefaultUlimits,
 			group:          crioRuntimeConfig,
@@ -255,6 +277,11 @@ func initCrioTemplateConfig(c *Config) ([]*templateConfigValue, error) {
 			group:          crioRuntimeConfig,
 			isDefaultValue: simpleEqual(dc.SeccompProfile, c.SeccompProfile),
 		},
+		{
+			templateString: templateStringCrioRuntimeSeccompProfileRecordDir,
+			group:          crioRuntimeConfig,
+			isDefaultValue: simpleEqual(dc.SeccompProfileRecordDir, c.SeccompProfileRecordDir),
+		},
 		{
 			templateString: templateStringCrioRuntimeApparmorProfile,
 			group:          crioRuntimeConfig,
@@ -450,6 +477,11 @@ func initCrioTemplateConfig(c *Config) ([]*templateConfigValue, error) {
 			group:          crioRuntimeConfig,
 			isDefaultValue: simpleEqual(dc.DefaultRuntime, c.DefaultRuntime),
 		},
+		{
+			templateString: templateStringCrioRuntimeRuntimeHealthCheckInterval,
+			group:          crioRuntimeConfig,
+			isDefaultValue: simpleEqual(dc.RuntimeHealthCheckInterval, c.RuntimeHealthCheckInterval),
+		},
 		{
 			templateString: templateStringCrioRuntimeAbsentMountSourcesToReject,
 			group:          crioRuntimeConfig,
@@ -475,6 +507,11 @@ func initCrioTemplateConfig(c *Config) ([]*templateConfigValue, error) {
 			group:          crioRuntimeConfig,
 			isDefaultValue: simpleEqual(dc.DisableHostPortMapping, c.DisableHostPortMapping),
 		},
+		{
+			templateString: templateStringCrioRuntimeHostPortMappingBackend,
+			group:          crioRuntimeConfig,
+			isDefaultValue: simpleEqual(dc.HostPortMappingBackend, c.HostPortMappingBackend),
+		},
 		{
 			templateString: templateStringCrioRuntimeTimezone,
 			group:          crioRuntimeConfig,
@@ -510,6 +547,11 @@ func initCrioTemplateConfig(c *Config) ([]*templateConfigValue, error) {
 			group:          crioImageConfig,
 			isDefaultValue: stringSliceEqual(dc.PinnedImages, c.PinnedImages),
 		},
+		{
+			templateString: templateStringCrioImagePrePullImages,
+			group:          crioImageConfig,
+			isDefaultValue: stringSliceEqual(dc.PrePullImages, c.PrePullImages),
+		},
 		{
 			templateString: templateStringCrioImageSignaturePolicy,
 			group:          crioImageConfig,
@@ -535,6 +577,36 @@ func initCrioTemplateConfig(c *Config) ([]*templateConfigValue, error) {
 			group:          crioImageConfig,
 			isDefaultValue: simpleEqual(dc.BigFilesTemporaryDir, c.BigFilesTemporaryDir),
 		},
+		{
+			templateString: templateStringCrioImageMaxConcurrentImagePulls,
+			group:          crioImageConfig,
+			isDefaultValue: simpleEqual(dc.MaxConcurrentImagePulls, c.MaxConcurrentImagePulls),
+		},
+		{
+			templateString: templateStringCrioImageMaxConcurrentImagePullsPerRegistry,
+			group:          crioImageConfig,
+			isDefaultValue: simpleEqual(dc.MaxConcurrentImagePullsPerRegistry, c.MaxConcurrentImagePullsPerRegistry),
+		},
+		{
+			templateString: templateStringCrioImageImageGCInterval,
+			group:          crioImageConfig,
+			isDefaultValue: simpleEqual(dc.ImageGCInterval, c.ImageGCInterval),
+		},
+		{
+			templateString: templateStringCrioImageImageGCMaxAge,
+			group:          crioImageConfig,
+			isDefaultValue: simpleEqual(dc.ImageGCMaxAge, c.ImageGCMaxAge),
+		},
+		{
+			templateString: templateStringCrioImageImageGCKeepPerRepository,
+			group:          crioImageConfig,
+			isDefaultValue: simpleEqual(dc.ImageGCKeepPerRepository, c.ImageGCKeepPerRepository),
+		},
+		{
+			templateString: templateStringCrioImageImageGCMaxStoreSize,
+			group:          crioImageConfig,
+			isDefaultValue: simpleEqual(dc.ImageGCMaxStoreSize, c.ImageGCMaxStoreSize),
+		},
 		{
 			templateString: templateStringCrioNetworkCniDefaultNetwork,
 			group:          crioNetworkConfig,
@@ -640,6 +712,16 @@ func initCrioTemplateConfig(c *Config) ([]*templateConfigValue, error) {
 			group:          crioNRIConfig,
 			isDefaultValue: simpleEqual(dc.NRI.PluginRequestTimeout, c.NRI.PluginRequestTimeout),
 		},
+		{
+			templateString: templateStringCrioNRIEvictionGracePeriod,
+			group:          crioNRIConfig,
+			isDefaultValue: simpleEqual(dc.NRI.EvictionGracePeriod, c.NRI.EvictionGracePeriod),
+		},
+		{
+			templateString: templateStringCrioNRIPluginPolicies,
+			group:          crioNRIConfig,
+			isDefaultValue: NRIPluginPoliciesEqual(dc.NRI.PluginPolicies, c.NRI.PluginPolicies),
+		},
 	}
 
 	return crioTemplateConfig, nil
@@ -711,6 +793,24 @@ func WorkloadsEqual(a, b Workloads) bool {
 	return true
 }
 
+func NRIPluginPoliciesEqual(a, b map[string]*nri.PluginPolicy) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for key, valueA := range a {
+		valueB, ok := b[key]
+		if !ok {
+			return false
+		}
+		if !reflect.DeepEqual(valueA, valueB) {
+			return false
+		}
+	}
+
+	return true
+}
+
 const templateStringPrefix = `# The CRI-O configuration file specifies all of the available configuration
 # options and command-line flags for the crio(8) OCI Kubernetes Container Runtime
 # daemon, but in a TOML format that can be more easily modified and versioned.
@@ -861,6 +961,33 @@ const templateStringCrioAPIGrpcMaxRecvMsgSize = `# Maximum grpc receive message
 
 `
 
+const templateStringCrioAPIAuditLogPath = `# Path to the audit log, which records the mutating and interactive CRI calls
+# including the credentials of the caller. An empty path disables the audit log.
+{{ $.Comment }}audit_log_path = "{{ .AuditLogPath }}"
+
+`
+
+const templateStringCrioAPIAuditLogMaxSize = `# Size in bytes after which the audit log gets rotated. If set to 0, then the
+# audit log does not get rotated.
+{{ $.Comment }}audit_log_max_size = {{ .AuditLogMaxSize }}
+
+`
+
+const templateStringCrioAPIAuditLogMaxBackups = `# Number of rotated audit logs to keep.
+{{ $.Comment }}audit_log_max_backups = {{ .AuditLogMaxBackups }}
+
+`
+
+const templateStringCrioAPIAuditLogPolicy = `# Audit level per CRI method in the format "Method=level". Supported levels are
+# "none", "metadata" and "request". The mutating and interactive methods, like
+# "CreateContainer", "ExecSync" or "PortForward", use "metadata" by default,
+# all other methods "none". Registry credentials and environment variable values
+# are redacted from the requests recorded by the "request" level.
+{{ $.Comment }}audit_log_policy = [
+{{ range $opt := .AuditLogPolicy }}{{ $.Comment }}{{ printf "\t%q,\n" $opt }}{{ end }}{{ $.Comment }}]
+
+`
+
 const templateStringCrioRuntime = `# The crio.runtime table contains settings pertaining to the OCI runtime used
 # and options for how to set up and manage the OCI runtime.
 [crio.runtime]
@@ -870,7 +997,8 @@ const templateStringCrioRuntime = `# The crio.runtime table contains settings pe
 const templateStringCrioRuntimeDefaultUlimits = `# A list of ulimits to be set in containers by default, specified as
 # "<ulimit name>=<soft limit>:<hard limit>", for example:
 # "nofile=1024:2048"
-# If nothing is set here, settings will be inherited from the CRI-O daemon
+# If nothing is set here, settings will be inherited from the CRI-O daemon.
+# This option supports live configuration reload.
 {{ $.Comment }}default_ulimits = [
 {{ range $ulimit := .DefaultUlimits }}{{ $.Comment }}{{ printf "\t%q,\n" $ulimit }}{{ end }}{{ $.Comment }}]
 
@@ -929,6 +1057,12 @@ const templateStringCrioRuntimeSeccompProfile = `# Path to the seccomp.json prof
 
 `
 
+const templateStringCrioRuntimeSeccompProfileRecordDir = `# Directory where the seccomp profiles recorded for containers using the
+# "io.kubernetes.cri-o.seccompNotifierAction=record" annotation are written to.
+{{ $.Comment }}seccomp_profile_record_dir = "{{ .SeccompProfileRecordDir }}"
+
+`
+
 const templateStringCrioRuntimeApparmorProfile = `# Used to change the name of the default AppArmor profile of CRI-O. The default
 # profile name is "crio-default". This profile only takes effect if the user
 # does not specify a profile via the Kubernetes Pod's metadata annotation. If
@@ -974,7 +1108,7 @@ const templateStringCrioRuntimeSeparatePullCgroup = `# Specify whether the image
 
 const templateStringCrioRuntimeDefaultCapabilities = `# List of default capabilities for containers. If it is empty or commented out,
 # only the capabilities defined in the containers json file by the user/kube
-# will be added.
+# will be added. This option supports live configuration reload.
 {{ $.Comment }}default_capabilities = [
 {{ range $capability := .DefaultCapabilities}}{{ $.Comment }}{{ printf "\t%q,\n" $capability}}{{ end }}{{ $.Comment }}]
 
@@ -987,7 +1121,8 @@ const templateStringCrioRuntimeAddInheritableCapabilities = `# Add capabilities
 `
 
 const templateStringCrioRuntimeDefaultSysctls = `# List of default sysctls. If it is empty or commented out, only the sysctls
-# defined in the container json file by the user/kube will be added.
+# defined in the container json file by the user/kube will be added. This
+# option supports live configuration reload.
 {{ $.Comment }}default_sysctls = [
 {{ range $sysctl := .DefaultSysctls}}{{ $.Comment }}{{ printf "\t%q,\n" $sysctl}}{{ end }}{{ $.Comment }}]
 
@@ -1048,6 +1183,7 @@ const templateStringCrioRuntimeDefaultMountsFile = `# Path to the file specifyin
 
 const templateStringCrioRuntimePidsLimit = `# Maximum number of processes allowed in a container.
 # This option is deprecated. The Kubelet flag '--pod-pids-limit' should be used instead.
+# This option supports live configuration reload.
 {{ $.Comment }}pids_limit = {{ .PidsLimit }}
 
 `
@@ -1197,6 +1333,14 @@ const templateStringCrioRuntimeDefaultRuntime = `# default_runtime is the _name_
 
 `
 
+const templateStringCrioRuntimeRuntimeHealthCheckInterval = `# Interval in which CRI-O probes the configured runtime handlers for their
+# binary, version and supported features. Pod sandboxes of unhealthy runtime
+# handlers fail to be created, and an unhealthy default runtime handler makes
+# the runtime not ready. 0 disables the runtime handler health checks.
+{{ $.Comment }}runtime_health_check_interval = "{{ .RuntimeHealthCheckInterval }}"
+
+`
+
 const templateStringCrioRuntimeAbsentMountSourcesToReject = `# A list of paths that, when absent from the host,
 # will cause a container creation to fail (as opposed to the current behavior being created as a directory).
 # This option is to protect from source locations whose existence as a directory could jeopardize the health of the node, and whose
@@ -1250,6 +1394,7 @@ const templateStringCrioRuntimeRuntimesRuntimeHandler = `# The "crio.runtime.run
 #   "io.kubernetes.cri-o.seccompNotifierAction" for enabling the seccomp notifier feature.
 #   "io.kubernetes.cri-o.umask" for setting the umask for container init process.
 #   "io.kubernetes.cri.rdt-class" for setting the RDT class of a container
+#   "io.kubernetes.cri-o.annotations.checkpoint.pod.archive" for restoring a pod sandbox from a pod checkpoint archive.
 #   "seccomp-profile.kubernetes.cri-o.io" for setting the seccomp profile for:
 #     - a specific container by using: "seccomp-profile.kubernetes.cri-o.io/<CONTAINER_NAME>"
 #     - a whole pod by using: "seccomp-profile.kubernetes.cri-o.io/POD"
@@ -1296,6 +1441,16 @@ const templateStringCrioRuntimeRuntimesRuntimeHandler = `# The "crio.runtime.run
 # Please be aware that CRI-O is not able to get notified if a syscall gets
 # blocked based on the seccomp defaultAction, which is a general runtime
 # limitation.
+#
+# If the value is "io.kubernetes.cri-o.seccompNotifierAction=record", then
+# CRI-O keeps enforcing the seccomp profile of the container while recording
+# the syscalls it allows and their architectures. Syscalls blocked by the
+# profile are not recorded. Once all processes of the container
+# exited, or the container got removed, a minimal seccomp profile allowing
+# only the recorded syscalls is written to
+# "<seccomp_profile_record_dir>/<container ID>.json". The profile can be used
+# as "Localhost" seccomp profile for the workload. The "write" syscall cannot
+# be traced and is therefore always part of the profile.
 
 {{ range $runtime_name, $runtime_handler := .Runtimes  }}
 {{ $.Comment }}[crio.runtime.runtimes.{{ $runtime_name }}]
@@ -1323,14 +1478,22 @@ const templateStringCrioRuntimeWorkloads = `# The workloads table defines ways t
 # that work based on annotations, rather than the CRI.
 # Note, the behavior of this table is EXPERIMENTAL and may change at any time.
 # Each workload, has a name, activation_annotation, annotation_prefix and set of resources it supports mutating.
-# The currently supported resources are "cpuperiod" "cpuquota", "cpushares", "cpulimit" and "cpuset". The values for "cpuperiod" and "cpuquota" are denoted in microseconds.
+# The currently supported resources are "cpuperiod" "cpuquota", "cpushares", "cpulimit", "cpuset", "memorylimit",
+# "memoryreservation", "memoryswap", "pidslimit", "blkioweight", "hugepagelimits", "rdtclass" and "blockioclass". The values for "cpuperiod" and "cpuquota" are denoted in microseconds.
 # The value for "cpulimit" is denoted in millicores, this value is used to calculate the "cpuquota" with the supplied "cpuperiod" or the default "cpuperiod".
 # Note that the "cpulimit" field overrides the "cpuquota" value supplied in this configuration.
+# The resources "memorylimit", "memoryreservation" and "memoryswap" are denoted in bytes, "memoryswap" is the
+# limit of memory plus swap and can be set to -1 for unlimited swap. "pidslimit" can be set to -1 for unlimited pids.
+# The "blkioweight" has to be in the range from 10 to 1000 and takes precedence over the weight of the "blockioclass".
+# The "hugepagelimits" are denoted in bytes per page size, like { "2MB" = 4194304 }.
+# The "rdtclass" and "blockioclass" select a class of the configured rdt_config_file and blockio_config_file.
+# Creating the container fails if RDT or blockio is disabled or the class does not exist.
 # Each resource can have a default value specified, or be empty.
 # For a container to opt-into this workload, the pod should be configured with the annotation $activation_annotation (key only, value is ignored).
 # To customize per-container, an annotation of the form $annotation_prefix.$resource/$ctrName = "value" can be specified
 # signifying for that resource type to override the default value.
 # If the annotation_prefix is not present, every container in the pod will be given the default values.
+# This option supports live configuration reload. Changed workloads only apply to newly created pods and containers.
 # Example:
 # [crio.runtime.workloads.workload-type]
 # activation_annotation = "io.crio/workload"
@@ -1357,7 +1520,17 @@ const templateStringCrioRuntimeWorkloads = `# The workloads table defines ways t
 {{ $.Comment }}cpuquota = {{ $workload_config.Resources.CPUQuota }}
 {{ $.Comment }}cpuperiod = {{ $workload_config.Resources.CPUPeriod }}
 {{ $.Comment }}cpushares = {{ $workload_config.Resources.CPUShares }}
-{{ $.Comment }}cpulimit = {{ $workload_config.Resources.CPULimit }}{{ end }}
+{{ $.Comment }}cpulimit = {{ $workload_config.Resources.CPULimit }}
+{{ $.Comment }}memorylimit = {{ $workload_config.Resources.MemoryLimit }}
+{{ $.Comment }}memoryreservation = {{ $workload_config.Resources.MemoryReservation }}
+{{ $.Comment }}memoryswap = {{ $workload_config.Resources.MemorySwap }}
+{{ $.Comment }}pidslimit = {{ $workload_config.Resources.PidsLimit }}
+{{ $.Comment }}blkioweight = {{ $workload_config.Resources.BlkioWeight }}
+{{ if $workload_config.Resources.HugepageLimits }}{{ $.Comment }}hugepagelimits = {
+{{- $first := true }}{{- range $key, $value := $workload_config.Resources.HugepageLimits }}
+{{- if not $first }},{{ end }} {{ printf "%q = %d" $key $value }}{{- $first = false }}{{- end }} }
+{{ end }}{{ $.Comment }}rdtclass = "{{ $workload_config.Resources.RDTClass }}"
+{{ $.Comment }}blockioclass = "{{ $workload_config.Resources.BlockIOClass }}"{{ end }}
 {{ end }}
 `
 
@@ -1375,6 +1548,15 @@ const templateStringCrioRuntimeDisableHostPortMapping = `# disable_hostport_mapp
 
 `
 
+const templateStringCrioRuntimeHostPortMappingBackend = `# hostport_mapping_backend is the backend used to program the container hostport mappings.
+# Supported values are "iptables" and "nftables". The nftables backend manages its own
+# "crio-hostports" table and does not require the iptables binaries. Unlike the
+# iptables backend, it does not restore mappings which got removed externally, for
+# example by flushing the whole nftables ruleset.
+{{ $.Comment }}hostport_mapping_backend = "{{ .HostPortMappingBackend }}"
+
+`
+
 const templateStringCrioRuntimeTimezone = `# timezone To set the timezone for a container in CRI-O.
 # If an empty string is provided, CRI-O retains its default behavior. Use 'Local' to match the timezone of the host machine.
 {{ $.Comment }}timezone = "{{ .Timezone }}"
@@ -1435,6 +1617,17 @@ const templateStringCrioImagePinnedImages = `# List of images to be excluded fro
 
 `
 
+const templateStringCrioImagePrePullImages = `# List of images which are pulled in the background on startup and on config
+# reload, retrying failed pulls with an exponential backoff. The state of the
+# pulls is available via "crio status prepull". Images which should not be
+# garbage collected have to be part of the pinned_images as well. A config
+# reload only starts pulling added images and stops pulling removed ones.
+# This option supports live configuration reload.
+{{ $.Comment }}pre_pull_images = [
+{{ range $opt := .PrePullImages }}{{ $.Comment }}{{ printf "\t%q,\n" $opt }}{{ end }}{{ $.Comment }}]
+
+`
+
 const templateStringCrioImageSignaturePolicy = `# Path to the file which decides what sort of policy we use when deciding
 # whether or not to trust an image that we've pulled. It is not recommended that
 # this option be used, as the default behavior of using the system-wide default
@@ -1472,6 +1665,44 @@ const templateStringCrioImageBigFilesTemporaryDir = `# Temporary directory to us
 
 `
 
+const templateStringCrioImageMaxConcurrentImagePulls = `# Maximum number of image pulls running at the same time. Further pulls are
+# queued and started in the order they have been requested. 0 means unlimited.
+{{ $.Comment }}max_concurrent_image_pulls = {{ .MaxConcurrentImagePulls }}
+
+`
+
+const templateStringCrioImageMaxConcurrentImagePullsPerRegistry = `# Maximum number of image pulls from a single registry running at the same
+# time. Pulls from other registries are not blocked by the queued ones.
+# 0 means unlimited.
+{{ $.Comment }}max_concurrent_image_pulls_per_registry = {{ .MaxConcurrentImagePullsPerRegistry }}
+
+`
+
+const templateStringCrioImageImageGCInterval = `# Interval in which CRI-O garbage collects unused images, independently of the
+# kubelet image garbage collection. Pinned images and images in use by
+# containers are never removed. 0 disables the image garbage collection.
+{{ $.Comment }}image_gc_interval = "{{ .ImageGCInterval }}"
+
+`
+
+const templateStringCrioImageImageGCMaxAge = `# Remove images which have not been used by any container for this duration.
+# 0 means no age limit.
+{{ $.Comment }}image_gc_max_age = "{{ .ImageGCMaxAge }}"
+
+`
+
+const templateStringCrioImageImageGCKeepPerRepository = `# Only keep this amount of the most recently used images per repository.
+# 0 means no limit.
+{{ $.Comment }}image_gc_keep_per_repository = {{ .ImageGCKeepPerRepository }}
+
+`
+
+const templateStringCrioImageImageGCMaxStoreSize = `# Remove the least recently used images until all images take less than this
+# amount of bytes. 0 means no limit.
+{{ $.Comment }}image_gc_max_store_size = {{ .ImageGCMaxStoreSize }}
+
+`
+
 const templateStringCrioNetwork = `# The crio.network table containers settings pertaining to the management of
 # CNI plugins.
 [crio.network]
@@ -1613,3 +1844,34 @@ const templateStringCrioNRIPluginRequestTimeout = `# Timeout for a plugin to han
 {{ $.Comment }}nri_plugin_request_timeout = "{{ .NRI.PluginRequestTimeout }}"
 
 `
+
+const templateStringCrioNRIEvictionGracePeriod = `# Grace period for containers evicted by an NRI plugin to stop before getting
+# killed. The grace period is rounded down to full seconds.
+{{ $.Comment }}nri_eviction_grace_period = "{{ .NRI.EvictionGracePeriod }}"
+
+`
+
+const templateStringCrioNRIPluginPolicies = `# Policies restricting the adjustments of NRI plugins. A policy applies to the
+# plugin with the same index and name joined by a dash, like "10-device-injector",
+# or to all plugins with the same name. The policy "*" applies to all plugins
+# without a policy of their own. Plugins without any policy are not restricted.
+# The supported adjustments are "annotations", "mounts", "env", "hooks", "devices",
+# "resources", "cgroups_path", "rlimits" and "evictions". Updating the resources of
+# other containers requires "resources". Requests containing adjustments not
+# allowed by the policy of the plugin are rejected and logged. Plugins are
+# identified by the index and name they register themselves with. Plugins
+# connecting to the NRI socket can choose any index and name, so policies only
+# restrict them reliably if access to the socket is limited to trusted plugins
+# or nri_disable_connections is set.
+# Example:
+# [crio.nri.nri_plugin_policies."10-device-injector"]
+# allowed_adjustments = [
+# 	"devices",
+# 	"mounts",
+# ]
+{{ range $name, $policy := .NRI.PluginPolicies }}
+{{ $.Comment }}[crio.nri.nri_plugin_policies.{{ printf "%q" $name }}]
+{{ $.Comment }}allowed_adjustments = [
+{{ if $policy }}{{ range $adjustment := $policy.AllowedAdjustments }}{{ $.Comment }}{{ printf "\t%q,\n" $adjustment }}{{ end }}{{ end }}{{ $.Comment }}]
+{{ end }}
+`
@@ -2,7 +2,9 @@ package config_test
 
 import (
 	"bytes"
+	"os"
 
+	"github.com/cri-o/cri-o/internal/config/nri"
 	"github.com/cri-o/cri-o/pkg/config"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -22,6 +24,63 @@ var _ = t.Describe("Config", func() {
 			// Then
 			Expect(err).ToNot(HaveOccurred())
 		})
+
+		It("should write the workload resources", func() {
+			// Given
+			var wr bytes.Buffer
+			resources := &config.Resources{
+				CPUShares:         10,
+				MemoryLimit:       1 << 30,
+				MemoryReservation: 1 << 29,
+				MemorySwap:        -1,
+				PidsLimit:         100,
+				BlkioWeight:       500,
+				HugepageLimits:    map[string]uint64{"2MB": 1 << 22, "1GB": 1 << 30},
+				RDTClass:          "gold",
+				BlockIOClass:      "slowreader",
+			}
+			sut.Workloads = config.Workloads{
+				"management": &config.WorkloadConfig{
+					ActivationAnnotation: "target.workload.openshift.io/management",
+					Resources:            resources,
+				},
+			}
+
+			// When
+			err := sut.WriteTemplate(true, &wr)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			tmpFile := t.MustTempFile("crio.conf")
+			Expect(os.WriteFile(tmpFile, wr.Bytes(), 0o644)).To(Succeed())
+			newConfig, err := config.DefaultConfig()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(newConfig.UpdateFromFile(tmpFile)).To(Succeed())
+			Expect(newConfig.Workloads).To(HaveKey("management"))
+			Expect(newConfig.Workloads["management"].Resources).To(Equal(resources))
+		})
+
+		It("should write the NRI plugin policies", func() {
+			// Given
+			var wr bytes.Buffer
+			policies := map[string]*nri.PluginPolicy{
+				"10-device-injector": {AllowedAdjustments: []string{"devices", "mounts"}},
+				"*":                  {AllowedAdjustments: []string{}},
+			}
+			sut.NRI.PluginPolicies = policies
+
+			// When
+			err := sut.WriteTemplate(true, &wr)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			tmpFile := t.MustTempFile("crio.conf")
+			Expect(os.WriteFile(tmpFile, wr.Bytes(), 0o644)).To(Succeed())
+			newConfig, err := config.DefaultConfig()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(newConfig.UpdateFromFile(tmpFile)).To(Succeed())
+			Expect(newConfig.NRI.PluginPolicies).To(Equal(policies))
+		})
 	})
 	t.Describe("RuntimesEqual", func() {
 		It("not equal if different length", func() {
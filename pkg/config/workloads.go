@@ -3,8 +3,13 @@ package config
 import (
 	"encoding/json"
 	"fmt"
+	"regexp"
+	"sort"
 	"strings"
 
+	"github.com/cri-o/cri-o/internal/config/blockio"
+	"github.com/cri-o/cri-o/internal/config/rdt"
+	rspec "github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/opencontainers/runtime-tools/generate"
 	"github.com/sirupsen/logrus"
 	"k8s.io/utils/cpuset"
@@ -18,8 +23,15 @@ const (
 	// defined here:
 	// https://github.com/torvalds/linux/blob/cac03ac368fabff0122853de2422d4e17a32de08/kernel/sched/core.c#L10546
 	minQuotaPeriod = 1000
+	// The range of the blkio weight defined here:
+	// https://github.com/opencontainers/runtime-spec/blob/main/config-linux.md#block-io
+	minBlkioWeight = 10
+	maxBlkioWeight = 1000
 )
 
+// hugepageSizeRegexp matches the hugepage sizes like "2MB" or "1GB".
+var hugepageSizeRegexp = regexp.MustCompile(`^[1-9][0-9]*[KMGTPE]?B$`)
+
 type Workloads map[string]*WorkloadConfig
 
 type WorkloadConfig struct {
@@ -46,6 +58,14 @@ type WorkloadConfig struct {
 	// `cpuperiod`: configure cpu period for a given container
 	// `cpuset`: configure cpuset for a given container
 	// `cpulimit`: configure cpu quota in millicores for a given container, overrides the `cpuquota` field
+	// `memorylimit`: configure the memory limit in bytes for a given container
+	// `memoryreservation`: configure the memory reservation in bytes for a given container
+	// `memoryswap`: configure the memory plus swap limit in bytes for a given container
+	// `pidslimit`: configure the pids limit for a given container
+	// `blkioweight`: configure the block IO weight for a given container
+	// `hugepagelimits`: configure the hugepage limits in bytes per page size for a given container
+	// `rdtclass`: configure the RDT class for a given container
+	// `blockioclass`: configure the block IO class for a given container
 	// The value of the map is the default value for that resource.
 	// If a container is configured to use this workload, and does not specify
 	// the annotation with the resource and value, the default value will apply.
@@ -67,6 +87,22 @@ type Resources struct {
 	CPUSet string `json:"cpuset,omitempty"`
 	// Specifies the CPU limit in millicores. This will be used to calculate the CPU quota.
 	CPULimit int64 `json:"cpulimit,omitempty"`
+	// Specifies the memory limit in bytes.
+	MemoryLimit int64 `json:"memorylimit,omitempty"`
+	// Specifies the memory soft limit in bytes.
+	MemoryReservation int64 `json:"memoryreservation,omitempty"`
+	// Specifies the memory plus swap limit in bytes, -1 means unlimited.
+	MemorySwap int64 `json:"memoryswap,omitempty"`
+	// Specifies the maximum number of processes, -1 means unlimited.
+	PidsLimit int64 `json:"pidslimit,omitempty"`
+	// Specifies the block IO weight in the range from 10 to 1000.
+	BlkioWeight uint16 `json:"blkioweight,omitempty"`
+	// Specifies the hugepage limits in bytes by page size, like "2MB".
+	HugepageLimits map[string]uint64 `json:"hugepagelimits,omitempty"`
+	// Specifies the RDT class of the container.
+	RDTClass string `json:"rdtclass,omitempty"`
+	// Specifies the block IO class of the container.
+	BlockIOClass string `json:"blockioclass,omitempty"`
 }
 
 func (w Workloads) Validate() error {
@@ -129,7 +165,10 @@ func (w Workloads) FilterDisallowedAnnotations(allowed []string, toFilter map[st
 	return nil
 }
 
-func (w Workloads) MutateSpecGivenAnnotations(ctrName string, specgen *generate.Generator, sboxAnnotations map[string]string) error {
+// MutateSpecGivenAnnotations applies the resources of the workload activated
+// by the sandbox annotations. The RDT and block IO classes are only assigned
+// if they are enabled and configured in the provided configs.
+func (w Workloads) MutateSpecGivenAnnotations(ctrName string, specgen *generate.Generator, sboxAnnotations map[string]string, rdtConfig *rdt.Config, blockioConfig *blockio.Config) error {
 	workload := w.workloadGivenActivationAnnotation(sboxAnnotations)
 	if workload == nil {
 		return nil
@@ -138,9 +177,10 @@ func (w Workloads) MutateSpecGivenAnnotations(ctrName string, specgen *generate.
 	if err != nil {
 		return err
 	}
-	resources.MutateSpec(specgen)
-
-	return nil
+	if err := resources.MutateSpec(specgen); err != nil {
+		return err
+	}
+	return resources.mutateSpecClasses(ctrName, specgen, rdtConfig, blockioConfig)
 }
 
 func (w Workloads) workloadGivenActivationAnnotation(sboxAnnotations map[string]string) *WorkloadConfig {
@@ -160,6 +200,9 @@ func resourcesFromAnnotation(prefix, ctrName string, allAnnotations map[string]s
 	if !ok {
 		return defaultResources, nil
 	}
+	if defaultResources == nil {
+		defaultResources = &Resources{}
+	}
 
 	var resources *Resources
 	if err := json.Unmarshal([]byte(value), &resources); err != nil {
@@ -184,12 +227,46 @@ func resourcesFromAnnotation(prefix, ctrName string, allAnnotations map[string]s
 	if resources.CPULimit == 0 {
 		resources.CPULimit = defaultResources.CPULimit
 	}
+	if resources.MemoryLimit == 0 {
+		resources.MemoryLimit = defaultResources.MemoryLimit
+	}
+	if resources.MemoryReservation == 0 {
+		resources.MemoryReservation = defaultResources.MemoryReservation
+	}
+	if resources.MemorySwap == 0 {
+		resources.MemorySwap = defaultResources.MemorySwap
+	}
+	if resources.PidsLimit == 0 {
+		resources.PidsLimit = defaultResources.PidsLimit
+	}
+	if resources.BlkioWeight == 0 {
+		resources.BlkioWeight = defaultResources.BlkioWeight
+	}
+	for pageSize, limit := range defaultResources.HugepageLimits {
+		if _, ok := resources.HugepageLimits[pageSize]; ok {
+			continue
+		}
+		if resources.HugepageLimits == nil {
+			resources.HugepageLimits = map[string]uint64{}
+		}
+		resources.HugepageLimits[pageSize] = limit
+	}
+	if resources.RDTClass == "" {
+		resources.RDTClass = defaultResources.RDTClass
+	}
+	if resources.BlockIOClass == "" {
+		resources.BlockIOClass = defaultResources.BlockIOClass
+	}
 
 	// If a CPU Limit in Milli is supplied via the annotation, calculate quota with the given CPU period.
 	if resources.CPULimit != 0 {
 		resources.CPUQuota = milliCPUToQuota(resources.CPULimit, int64(resources.CPUPeriod))
 	}
 
+	if err := resources.ValidateDefaults(); err != nil {
+		return nil, fmt.Errorf("invalid resources in annotation %s: %w", annotationKey, err)
+	}
+
 	return resources, nil
 }
 
@@ -228,13 +305,41 @@ func (r *Resources) ValidateDefaults() error {
 	if r.CPUPeriod != 0 && r.CPUPeriod < minQuotaPeriod {
 		return fmt.Errorf("cpuperiod %d cannot be less than 1000 microseconds", r.CPUPeriod)
 	}
+	if r.MemoryLimit < 0 {
+		return fmt.Errorf("memorylimit %d cannot be negative", r.MemoryLimit)
+	}
+	if r.MemoryReservation < 0 {
+		return fmt.Errorf("memoryreservation %d cannot be negative", r.MemoryReservation)
+	}
+	if r.MemoryLimit != 0 && r.MemoryReservation > r.MemoryLimit {
+		return fmt.Errorf("memoryreservation %d cannot be greater than memorylimit %d", r.MemoryReservation, r.MemoryLimit)
+	}
+	if r.MemorySwap < -1 {
+		return fmt.Errorf("memoryswap %d cannot be less than -1", r.MemorySwap)
+	}
+	if r.MemorySwap > 0 && r.MemorySwap < r.MemoryLimit {
+		return fmt.Errorf("memoryswap %d cannot be less than memorylimit %d", r.MemorySwap, r.MemoryLimit)
+	}
+	if r.PidsLimit < -1 {
+		return fmt.Errorf("pidslimit %d cannot be less than -1", r.PidsLimit)
+	}
+	if r.BlkioWeight != 0 && (r.BlkioWeight < minBlkioWeight || r.BlkioWeight > maxBlkioWeight) {
+		return fmt.Errorf("blkioweight %d has to be in the range from %d to %d", r.BlkioWeight, minBlkioWeight, maxBlkioWeight)
+	}
+	for pageSize := range r.HugepageLimits {
+		if !hugepageSizeRegexp.MatchString(pageSize) {
+			return fmt.Errorf("invalid hugepage size %q in hugepagelimits", pageSize)
+		}
+	}
 
 	return nil
 }
 
-func (r *Resources) MutateSpec(specgen *generate.Generator) {
+// MutateSpec applies the resources to the spec, except for the RDT and block
+// IO classes, which depend on the configuration of the host.
+func (r *Resources) MutateSpec(specgen *generate.Generator) error {
 	if r == nil {
-		return
+		return nil
 	}
 	if r.CPUSet != "" {
 		specgen.SetLinuxResourcesCPUCpus(r.CPUSet)
@@ -248,4 +353,64 @@ func (r *Resources) MutateSpec(specgen *generate.Generator) {
 	if r.CPUPeriod != 0 {
 		specgen.SetLinuxResourcesCPUPeriod(r.CPUPeriod)
 	}
+	if r.MemoryLimit != 0 {
+		specgen.SetLinuxResourcesMemoryLimit(r.MemoryLimit)
+	}
+	if r.MemoryReservation != 0 {
+		specgen.SetLinuxResourcesMemoryReservation(r.MemoryReservation)
+	}
+	if r.MemorySwap != 0 {
+		specgen.SetLinuxResourcesMemorySwap(r.MemorySwap)
+	}
+	if r.PidsLimit != 0 {
+		specgen.SetLinuxResourcesPidsLimit(r.PidsLimit)
+	}
+	if r.BlkioWeight != 0 {
+		specgen.SetLinuxResourcesBlockIOWeight(r.BlkioWeight)
+	}
+	pageSizes := make([]string, 0, len(r.HugepageLimits))
+	for pageSize := range r.HugepageLimits {
+		pageSizes = append(pageSizes, pageSize)
+	}
+	sort.Strings(pageSizes)
+	for _, pageSize := range pageSizes {
+		specgen.AddLinuxResourcesHugepageLimit(pageSize, r.HugepageLimits[pageSize])
+	}
+	return nil
+}
+
+// mutateSpecClasses applies the RDT and block IO classes to the spec, after
+// validating them against the configs like the classes set by annotations.
+func (r *Resources) mutateSpecClasses(ctrName string, specgen *generate.Generator, rdtConfig *rdt.Config, blockioConfig *blockio.Config) error {
+	if r == nil {
+		return nil
+	}
+	if r.BlockIOClass != "" {
+		linuxBlockIO, err := blockioConfig.OciLinuxBlockIO(ctrName, r.BlockIOClass)
+		if err != nil {
+			return fmt.Errorf("blockio class %q: %w", r.BlockIOClass, err)
+		}
+		// The explicit weight takes precedence over the one of the block IO class
+		if r.BlkioWeight != 0 {
+			weight := r.BlkioWeight
+			linuxBlockIO.Weight = &weight
+		}
+		if specgen.Config.Linux == nil {
+			specgen.Config.Linux = &rspec.Linux{}
+		}
+		if specgen.Config.Linux.Resources == nil {
+			specgen.Config.Linux.Resources = &rspec.LinuxResources{}
+		}
+		specgen.Config.Linux.Resources.BlockIO = linuxBlockIO
+	}
+	if r.RDTClass != "" {
+		if err := rdtConfig.ValidateClass(ctrName, r.RDTClass); err != nil {
+			return err
+		}
+		if specgen.Config.Linux == nil {
+			specgen.Config.Linux = &rspec.Linux{}
+		}
+		specgen.Config.Linux.IntelRdt = &rspec.LinuxIntelRdt{ClosID: rdt.ResctrlPrefix + r.RDTClass}
+	}
+	return nil
 }
@@ -1,6 +1,8 @@
 package config_test
 
 import (
+	"github.com/cri-o/cri-o/internal/config/blockio"
+	"github.com/cri-o/cri-o/internal/config/rdt"
 	"github.com/cri-o/cri-o/pkg/config"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -72,6 +74,32 @@ var _ = t.Describe("Workloads config", func() {
 		Expect(err).To(HaveOccurred())
 	})
 
+	DescribeTable("should fail on invalid resources",
+		func(resources *config.Resources) {
+			// Given
+			workloads := config.Workloads{
+				"management": &config.WorkloadConfig{
+					ActivationAnnotation: "target.workload.openshift.io/management",
+					AnnotationPrefix:     "resources.workload.openshift.io",
+					Resources:            resources,
+				},
+			}
+			// When
+			err := workloads.Validate()
+			// Then
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("negative memorylimit", &config.Resources{MemoryLimit: -1}),
+		Entry("negative memoryreservation", &config.Resources{MemoryReservation: -1}),
+		Entry("memoryreservation greater than memorylimit", &config.Resources{MemoryLimit: 1024, MemoryReservation: 2048}),
+		Entry("memoryswap less than -1", &config.Resources{MemorySwap: -2}),
+		Entry("memoryswap less than memorylimit", &config.Resources{MemoryLimit: 2048, MemorySwap: 1024}),
+		Entry("pidslimit less than -1", &config.Resources{PidsLimit: -2}),
+		Entry("blkioweight less than 10", &config.Resources{BlkioWeight: 9}),
+		Entry("blkioweight greater than 1000", &config.Resources{BlkioWeight: 1001}),
+		Entry("invalid hugepage size", &config.Resources{HugepageLimits: map[string]uint64{"2M": 1024}}),
+	)
+
 	It("should contain default values for resources", func() {
 		// Given
 		workloads := config.Workloads{
@@ -116,6 +144,29 @@ var _ = t.Describe("Workloads config", func() {
 					CPUSet: "0-1",
 				},
 			},
+			{
+				description: "when memory resources are provided",
+				resources: config.Resources{
+					MemoryLimit:       2048,
+					MemoryReservation: 1024,
+					MemorySwap:        4096,
+				},
+			},
+			{
+				description: "when unlimited memoryswap and pidslimit are provided",
+				resources: config.Resources{
+					MemoryLimit: 2048,
+					MemorySwap:  -1,
+					PidsLimit:   -1,
+				},
+			},
+			{
+				description: "when blkioweight and hugepagelimits are provided",
+				resources: config.Resources{
+					BlkioWeight:    10,
+					HugepageLimits: map[string]uint64{"2MB": 1 << 21, "1GB": 1 << 30},
+				},
+			},
 		}
 
 		for _, tc := range testCases {
@@ -159,7 +210,7 @@ var _ = t.Describe("Workloads config", func() {
 						},
 					},
 				}
-				tc.resources.MutateSpec(g)
+				Expect(tc.resources.MutateSpec(g)).To(Succeed())
 				Expect(g.Config.Linux.Resources.CPU.Quota).To(Equal(tc.expectedCPUQuota))
 				Expect(g.Config.Linux.Resources.CPU.Shares).To(Equal(tc.expectedCPUShare))
 				Expect(g.Config.Linux.Resources.CPU.Period).To(Equal(tc.expectedCPUPeriod))
@@ -168,6 +219,149 @@ var _ = t.Describe("Workloads config", func() {
 		}
 	})
 
+	It("resources should mutate the container spec beyond CPU", func() {
+		// Given
+		resources := config.Resources{
+			MemoryLimit:       2048,
+			MemoryReservation: 1024,
+			MemorySwap:        -1,
+			PidsLimit:         100,
+			BlkioWeight:       300,
+			HugepageLimits:    map[string]uint64{"2MB": 1 << 21, "1GB": 1 << 30},
+		}
+		g := &generate.Generator{
+			Config: &rspec.Spec{
+				Linux: &rspec.Linux{
+					Resources: &rspec.LinuxResources{},
+				},
+			},
+		}
+
+		// When
+		err := resources.MutateSpec(g)
+
+		// Then
+		Expect(err).NotTo(HaveOccurred())
+		Expect(g.Config.Linux.Resources.Memory.Limit).To(Equal(pointer(int64(2048))))
+		Expect(g.Config.Linux.Resources.Memory.Reservation).To(Equal(pointer(int64(1024))))
+		Expect(g.Config.Linux.Resources.Memory.Swap).To(Equal(pointer(int64(-1))))
+		Expect(g.Config.Linux.Resources.Pids.Limit).To(Equal(int64(100)))
+		Expect(g.Config.Linux.Resources.BlockIO.Weight).To(Equal(pointer(uint16(300))))
+		Expect(g.Config.Linux.Resources.HugepageLimits).To(Equal([]rspec.LinuxHugepageLimit{
+			{Pagesize: "1GB", Limit: 1 << 30},
+			{Pagesize: "2MB", Limit: 1 << 21},
+		}))
+	})
+
+	t.Describe("MutateSpecGivenAnnotations", func() {
+		const (
+			containerName = "limitbox"
+			prefix        = "resources.workload.openshift.io"
+			activation    = "target.workload.openshift.io/management"
+		)
+
+		mutateSpec := func(resources *config.Resources, annotations map[string]string) error {
+			workloads := config.Workloads{
+				"management": &config.WorkloadConfig{
+					AnnotationPrefix:     prefix,
+					ActivationAnnotation: activation,
+					Resources:            resources,
+				},
+			}
+			g := &generate.Generator{
+				Config: &rspec.Spec{
+					Linux: &rspec.Linux{
+						Resources: &rspec.LinuxResources{},
+					},
+				},
+			}
+			annotations[activation] = ""
+			return workloads.MutateSpecGivenAnnotations(containerName, g, annotations, rdt.New(), blockio.New())
+		}
+
+		It("should fail to set the RDT class if RDT is disabled", func() {
+			// Given
+			resources := &config.Resources{RDTClass: "gold"}
+
+			// When
+			err := mutateSpec(resources, map[string]string{})
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("RDT disabled"))
+		})
+
+		It("should fail to set the blockio class if blockio is disabled", func() {
+			// Given
+			resources := &config.Resources{}
+
+			// When
+			err := mutateSpec(resources, map[string]string{
+				prefix + "/" + containerName: `{"blockioclass":"slowreader"}`,
+			})
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("blockio disabled"))
+		})
+
+		It("should fail with invalid merged resources", func() {
+			// Given
+			resources := &config.Resources{MemoryLimit: 4096}
+
+			// When
+			err := mutateSpec(resources, map[string]string{
+				prefix + "/" + containerName: `{"memoryreservation":8192}`,
+			})
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("memoryreservation"))
+		})
+	})
+
+	It("should merge the resources of the annotation with the defaults", func() {
+		// Given
+		const (
+			containerName = "limitbox"
+			prefix        = "resources.workload.openshift.io"
+			activation    = "target.workload.openshift.io/management"
+		)
+		workloads := config.Workloads{
+			"management": &config.WorkloadConfig{
+				AnnotationPrefix:     prefix,
+				ActivationAnnotation: activation,
+				Resources: &config.Resources{
+					MemoryLimit:    4096,
+					PidsLimit:      50,
+					HugepageLimits: map[string]uint64{"2MB": 1 << 21, "1GB": 1 << 30},
+				},
+			},
+		}
+		g := &generate.Generator{
+			Config: &rspec.Spec{
+				Linux: &rspec.Linux{
+					Resources: &rspec.LinuxResources{},
+				},
+			},
+		}
+
+		// When
+		err := workloads.MutateSpecGivenAnnotations(containerName, g, map[string]string{
+			activation:                   "",
+			prefix + "/" + containerName: `{"memorylimit":8192,"hugepagelimits":{"2MB":4194304}}`,
+		}, rdt.New(), blockio.New())
+
+		// Then
+		Expect(err).NotTo(HaveOccurred())
+		Expect(g.Config.Linux.Resources.Memory.Limit).To(Equal(pointer(int64(8192))))
+		Expect(g.Config.Linux.Resources.Pids.Limit).To(Equal(int64(50)))
+		Expect(g.Config.Linux.Resources.HugepageLimits).To(Equal([]rspec.LinuxHugepageLimit{
+			{Pagesize: "1GB", Limit: 1 << 30},
+			{Pagesize: "2MB", Limit: 1 << 22},
+		}))
+	})
+
 	It("should mutate container spec based on annotation", func() {
 		const (
 			workloadsKey                = "management"
@@ -254,7 +448,7 @@ var _ = t.Describe("Workloads config", func() {
 						},
 					},
 				}
-				err := workloads.MutateSpecGivenAnnotations(containerName, g, tc.annotations)
+				err := workloads.MutateSpecGivenAnnotations(containerName, g, tc.annotations, rdt.New(), blockio.New())
 				Expect(err).NotTo(HaveOccurred())
 				Expect(g.Config.Linux.Resources.CPU.Quota).To(Equal(tc.expectedCPUQuota))
 				Expect(g.Config.Linux.Resources.CPU.Shares).To(Equal(tc.expectedCPUShare))
@@ -34,3 +34,168 @@ type CrioInfo struct {
 	CgroupDriver      string     `json:"cgroup_driver"`
 	DefaultIDMappings IDMappings `json:"default_id_mappings"`
 }
+
+// ImagePullLayerInfo stores the progress of a single blob of an image pull
+type ImagePullLayerInfo struct {
+	Digest    string `json:"digest"`
+	MediaType string `json:"media_type"`
+	Offset    uint64 `json:"offset"`
+	Size      int64  `json:"size"`
+	Done      bool   `json:"done"`
+}
+
+// ImagePullInfo stores information about an in-flight image pull
+type ImagePullInfo struct {
+	Image            string               `json:"image"`
+	StartTime        int64                `json:"start_time"`
+	LastProgressTime int64                `json:"last_progress_time"`
+	Layers           []ImagePullLayerInfo `json:"layers"`
+}
+
+// PrePullImageInfo stores the state of an image pulled in the background
+type PrePullImageInfo struct {
+	Image           string `json:"image"`
+	State           string `json:"state"`
+	Attempts        int    `json:"attempts"`
+	LastAttemptTime int64  `json:"last_attempt_time"`
+	LastError       string `json:"last_error,omitempty"`
+}
+
+// PrePullInfo stores the state of all images pulled in the background
+type PrePullInfo struct {
+	// Ready is true if all images are present in the local storage
+	Ready  bool               `json:"ready"`
+	Images []PrePullImageInfo `json:"images"`
+}
+
+// ContainerStatsInfo contains the block I/O, pressure and hugetlb statistics
+// of a container.
+type ContainerStatsInfo struct {
+	Blkio    []BlkioDeviceInfo      `json:"blkio"`
+	Pressure *PressureInfo          `json:"pressure,omitempty"`
+	Hugetlb  map[string]HugetlbInfo `json:"hugetlb"`
+}
+
+// BlkioDeviceInfo contains the block I/O usage for a single device.
+type BlkioDeviceInfo struct {
+	Major      uint64 `json:"major"`
+	Minor      uint64 `json:"minor"`
+	ReadBytes  uint64 `json:"read_bytes"`
+	WriteBytes uint64 `json:"write_bytes"`
+	ReadOps    uint64 `json:"read_ops"`
+	WriteOps   uint64 `json:"write_ops"`
+}
+
+// PressureInfo contains the pressure stall information per resource.
+type PressureInfo struct {
+	CPU    *PSIInfo `json:"cpu,omitempty"`
+	Memory *PSIInfo `json:"memory,omitempty"`
+	IO     *PSIInfo `json:"io,omitempty"`
+}
+
+// PSIInfo contains the pressure stall information of a single resource.
+type PSIInfo struct {
+	Some PSIDataInfo `json:"some"`
+	Full PSIDataInfo `json:"full"`
+}
+
+// PSIDataInfo contains the stall averages in percent and the total stall
+// time in microseconds.
+type PSIDataInfo struct {
+	Avg10  float64 `json:"avg10"`
+	Avg60  float64 `json:"avg60"`
+	Avg300 float64 `json:"avg300"`
+	Total  uint64  `json:"total"`
+}
+
+// HugetlbInfo contains the hugetlb usage for a single page size.
+type HugetlbInfo struct {
+	Usage    uint64 `json:"usage"`
+	MaxUsage uint64 `json:"max_usage"`
+	Failcnt  uint64 `json:"failcnt"`
+}
+
+// ConfigChange stores a configuration option changed by a reload
+type ConfigChange struct {
+	Option   string `json:"option"`
+	OldValue string `json:"old_value"`
+	NewValue string `json:"new_value"`
+}
+
+// ReloadInfo stores the result of the last configuration reload
+type ReloadInfo struct {
+	Time    int64          `json:"time"`
+	Success bool           `json:"success"`
+	Error   string         `json:"error,omitempty"`
+	Changes []ConfigChange `json:"changes"`
+	// RestartRequired are the changed options which are not applied on reload
+	RestartRequired []string `json:"restart_required"`
+}
+
+// ListFilter filters the pods and containers listed by CRI-O
+type ListFilter struct {
+	Namespace      string            `json:"namespace,omitempty"`
+	Labels         map[string]string `json:"labels,omitempty"`
+	State          string            `json:"state,omitempty"`
+	RuntimeHandler string            `json:"runtime_handler,omitempty"`
+}
+
+// PodInfo stores information about a pod sandbox
+type PodInfo struct {
+	ID             string            `json:"id"`
+	Name           string            `json:"name"`
+	Namespace      string            `json:"namespace"`
+	UID            string            `json:"uid"`
+	State          string            `json:"state"`
+	CreatedTime    int64             `json:"created_time"`
+	RuntimeHandler string            `json:"runtime_handler"`
+	CgroupParent   string            `json:"cgroup_parent"`
+	Pid            int               `json:"pid"`
+	NamespacePaths map[string]string `json:"namespace_paths"`
+	IPs            []string          `json:"ip_addresses"`
+	Labels         map[string]string `json:"labels"`
+	Containers     []string          `json:"containers"`
+}
+
+// ContainerListInfo stores information about a container of the container
+// list
+type ContainerListInfo struct {
+	ID             string            `json:"id"`
+	Name           string            `json:"name"`
+	PodID          string            `json:"pod_id"`
+	PodName        string            `json:"pod_name"`
+	PodNamespace   string            `json:"pod_namespace"`
+	State          string            `json:"state"`
+	CreatedTime    int64             `json:"created_time"`
+	Image          string            `json:"image"`
+	RuntimeHandler string            `json:"runtime_handler"`
+	Pid            int               `json:"pid"`
+	CgroupPath     string            `json:"cgroup_path"`
+	NamespacePaths map[string]string `json:"namespace_paths"`
+	IPs            []string          `json:"ip_addresses"`
+	Mounts         []MountInfo       `json:"mounts"`
+	Labels         map[string]string `json:"labels"`
+}
+
+// MountInfo stores information about a mount requested for a container
+type MountInfo struct {
+	ContainerPath string `json:"container_path"`
+	HostPath      string `json:"host_path"`
+	Readonly      bool   `json:"readonly"`
+	Propagation   string `json:"propagation"`
+}
+
+// RuntimeHandlerHealthInfo stores the result of the last health probe of a
+// runtime handler
+type RuntimeHandlerHealthInfo struct {
+	Name    string `json:"name"`
+	Healthy bool   `json:"healthy"`
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+	Version string `json:"version,omitempty"`
+	// The features supported by the runtime handler
+	IDMapMounts       bool  `json:"idmap_mounts"`
+	RecursiveReadOnly bool  `json:"recursive_read_only_mounts"`
+	CheckpointRestore bool  `json:"checkpoint_restore"`
+	LastProbeTime     int64 `json:"last_probe_time"`
+}
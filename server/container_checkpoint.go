@@ -2,27 +2,59 @@ package server
 
 import (
 	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 
 	metadata "github.com/checkpoint-restore/checkpointctl/lib"
+	istorage "github.com/containers/image/v5/storage"
 	"github.com/cri-o/cri-o/internal/lib"
 	"github.com/cri-o/cri-o/internal/log"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc/codes"
+	grpcmetadata "google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	types "k8s.io/cri-api/pkg/apis/runtime/v1"
 )
 
+// CheckpointPreDumpsKey is the gRPC metadata key of CheckpointContainer
+// requests to set the number of memory pre-dumps written while the container
+// keeps running, before the final checkpoint.
+const CheckpointPreDumpsKey = "crio-checkpoint-pre-dumps"
+
+// maxCheckpointPreDumps is the maximum number of memory pre-dumps of a single
+// checkpoint. Every pre-dump freezes the container processes again, so more
+// ones do not shorten the time of the final checkpoint any further.
+const maxCheckpointPreDumps = 10
+
+// checkpointImagePrefix is the location prefix for writing a checkpoint as OCI
+// image into the local storage, for example
+// "containers-storage:localhost/checkpoint:latest".
+var checkpointImagePrefix = istorage.Transport.Name() + ":"
+
 // CheckpointContainer checkpoints a container
 func (s *Server) CheckpointContainer(ctx context.Context, req *types.CheckpointContainerRequest) (*types.CheckpointContainerResponse, error) {
 	if !s.config.RuntimeConfig.CheckpointRestore() {
 		return nil, errors.New("checkpoint/restore support not available")
 	}
 
+	// Checkpoint the whole pod if the full ID of a pod sandbox is provided.
+	// Short IDs always refer to containers, which avoids checkpointing a whole
+	// pod if the short ID of a container is also the prefix of a pod ID.
+	if sandboxID, err := s.PodIDIndex().Get(req.ContainerId); err == nil && sandboxID == req.ContainerId {
+		return s.checkpointPodSandbox(ctx, sandboxID, req)
+	}
+
 	_, err := s.GetContainerFromShortID(ctx, req.ContainerId)
 	if err != nil {
 		return nil, status.Errorf(codes.NotFound, "could not find container %q: %v", req.ContainerId, err)
 	}
 
+	preDumps, err := checkpointPreDumps(ctx)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
 	log.Infof(ctx, "Checkpointing container: %s", req.ContainerId)
 	config := &metadata.ContainerConfig{
 		ID: req.ContainerId,
@@ -32,6 +64,11 @@ func (s *Server) CheckpointContainer(ctx context.Context, req *types.CheckpointC
 		// For the forensic container checkpointing use case we
 		// keep the container running after checkpointing it.
 		KeepRunning: true,
+		PreDumps:    preDumps,
+	}
+	if image, ok := strings.CutPrefix(req.Location, checkpointImagePrefix); ok {
+		opts.TargetFile = ""
+		opts.TargetImage = image
 	}
 
 	_, err = s.ContainerServer.ContainerCheckpoint(ctx, config, opts)
@@ -43,3 +80,55 @@ func (s *Server) CheckpointContainer(ctx context.Context, req *types.CheckpointC
 
 	return &types.CheckpointContainerResponse{}, nil
 }
+
+// checkpointPodSandbox checkpoints all containers of a pod sandbox into a
+// single archive.
+func (s *Server) checkpointPodSandbox(ctx context.Context, sandboxID string, req *types.CheckpointContainerRequest) (*types.CheckpointContainerResponse, error) {
+	sb, err := s.getPodSandboxFromRequest(ctx, sandboxID)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "could not find pod sandbox %q: %v", req.ContainerId, err)
+	}
+	if strings.HasPrefix(req.Location, checkpointImagePrefix) {
+		return nil, status.Errorf(codes.InvalidArgument, "pod sandbox checkpoints cannot be written as image: %s", req.Location)
+	}
+
+	preDumps, err := checkpointPreDumps(ctx)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	log.Infof(ctx, "Checkpointing pod sandbox: %s", sb.ID())
+	opts := &lib.ContainerCheckpointOptions{
+		TargetFile:  req.Location,
+		KeepRunning: true,
+		PreDumps:    preDumps,
+	}
+	if err := s.ContainerServer.PodCheckpoint(ctx, sb, opts); err != nil {
+		return nil, err
+	}
+
+	log.Infof(ctx, "Checkpointed pod sandbox: %s", sb.ID())
+
+	return &types.CheckpointContainerResponse{}, nil
+}
+
+// checkpointPreDumps parses the number of memory pre-dumps from the incoming
+// gRPC metadata.
+func checkpointPreDumps(ctx context.Context) (int, error) {
+	md, ok := grpcmetadata.FromIncomingContext(ctx)
+	if !ok {
+		return 0, nil
+	}
+	values := md.Get(CheckpointPreDumpsKey)
+	if len(values) == 0 {
+		return 0, nil
+	}
+	preDumps, err := strconv.Atoi(values[len(values)-1])
+	if err != nil {
+		return 0, fmt.Errorf("parse %s: %w", CheckpointPreDumpsKey, err)
+	}
+	if preDumps < 0 || preDumps > maxCheckpointPreDumps {
+		return 0, fmt.Errorf("invalid %s: %d must be between 0 and %d", CheckpointPreDumpsKey, preDumps, maxCheckpointPreDumps)
+	}
+	return preDumps, nil
+}
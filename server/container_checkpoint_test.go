@@ -6,9 +6,13 @@ import (
 
 	criu "github.com/checkpoint-restore/go-criu/v7/utils"
 	"github.com/cri-o/cri-o/internal/oci"
+	"github.com/cri-o/cri-o/server"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 	types "k8s.io/cri-api/pkg/apis/runtime/v1"
 )
 
@@ -55,6 +59,61 @@ var _ = t.Describe("ContainerCheckpoint", func() {
 			Expect(err).ToNot(HaveOccurred())
 		})
 
+		It("should fail with invalid pre-dumps", func() {
+			// Given
+			addContainerAndSandbox()
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+				server.CheckpointPreDumpsKey, "-1",
+			))
+
+			// When
+			_, err := sut.CheckpointContainer(ctx,
+				&types.CheckpointContainerRequest{
+					ContainerId: testContainer.ID(),
+				},
+			)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		})
+
+		It("should fail with too many pre-dumps", func() {
+			// Given
+			addContainerAndSandbox()
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+				server.CheckpointPreDumpsKey, "11",
+			))
+
+			// When
+			_, err := sut.CheckpointContainer(ctx,
+				&types.CheckpointContainerRequest{
+					ContainerId: testContainer.ID(),
+				},
+			)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		})
+
+		It("should not checkpoint the pod sandbox by a short ID", func() {
+			// Given
+			addContainerAndSandbox()
+
+			// When
+			_, err := sut.CheckpointContainer(
+				context.Background(),
+				&types.CheckpointContainerRequest{
+					ContainerId: testSandbox.ID()[:4],
+				},
+			)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(status.Code(err)).To(Equal(codes.NotFound))
+		})
+
 		It("should fail with invalid container id", func() {
 			// Given
 			// When
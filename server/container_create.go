@@ -416,6 +416,12 @@ func (s *Server) CreateContainer(ctx context.Context, req *types.CreateContainer
 	}
 
 	s.resourceStore.SetStageForResource(ctx, ctr.Name(), "container creating")
+	defer func() {
+		// the creation request failed or timed out during the current stage
+		if retErr != nil {
+			s.resourceStore.FinishStageForResource(ctr.Name(), resourcestore.StageResultFailure)
+		}
+	}()
 
 	resourceCleaner.Add(ctx, "createCtr: releasing container name "+ctr.Name(), func() error {
 		s.ReleaseContainerName(ctx, ctr.Name())
@@ -478,6 +484,7 @@ func (s *Server) CreateContainer(ctx context.Context, req *types.CreateContainer
 	}
 
 	// Since it's not a context error, we can delete the resource from the store, it will be tracked in the server from now on.
+	s.resourceStore.FinishStageForResource(ctr.Name(), resourcestore.StageResultSuccess)
 	s.resourceStore.Delete(ctr.Name())
 
 	newContainer.SetCreated()
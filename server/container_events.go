@@ -1,76 +1,343 @@
 package server
 
 import (
+	"context"
+	"fmt"
+	"strconv"
 	"sync"
+	"time"
 
+	"github.com/cri-o/cri-o/internal/log"
+	"github.com/cri-o/cri-o/server/metrics"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	types "k8s.io/cri-api/pkg/apis/runtime/v1"
 )
 
-type containerEventConn struct {
-	wg  sync.WaitGroup
-	err error
+// The CRI GetEventsRequest does not carry any fields, which is why the
+// GetContainerEvents options are passed as gRPC metadata of the stream.
+//
+// The sequence number of an event is its CreatedAt timestamp, which is
+// strictly increasing for the events generated by CRI-O. A client resumes
+// after a disconnect by providing the CreatedAt of the last received event
+// as ContainerEventsSinceSequenceKey, along with its previous filters.
+const (
+	// ContainerEventsSinceSequenceKey is the gRPC metadata key to replay all
+	// buffered events with a sequence number greater than the provided one.
+	ContainerEventsSinceSequenceKey = "crio-events-since-sequence"
+
+	// ContainerEventsSinceKey is the gRPC metadata key to replay all buffered
+	// events created at or after the provided unix timestamp in nanoseconds.
+	ContainerEventsSinceKey = "crio-events-since"
+
+	// ContainerEventsPodSandboxIDKey is the gRPC metadata key to only receive
+	// the events of the provided pod sandbox IDs. It can be specified multiple times.
+	ContainerEventsPodSandboxIDKey = "crio-events-pod-sandbox-id"
+
+	// ContainerEventsTypeKey is the gRPC metadata key to only receive events of
+	// the provided types, like CONTAINER_STARTED_EVENT. It can be specified multiple times.
+	ContainerEventsTypeKey = "crio-events-type"
+
+	// ContainerEventsSequenceKey is the gRPC header key sent to the client,
+	// which contains the sequence number of the latest event at the time of
+	// subscription.
+	ContainerEventsSequenceKey = "crio-events-sequence"
+
+	// containerEventsBufferSize is the amount of recent events kept for replay.
+	containerEventsBufferSize = 1000
+
+	// containerEventsClientBufferSize is the amount of events which can be
+	// queued for a single client before it gets disconnected.
+	containerEventsClientBufferSize = 1000
+)
+
+// containerEventFilter selects the events sent to a client.
+type containerEventFilter struct {
+	sinceSequence  *int64
+	sinceCreatedAt *int64
+	podSandboxIDs  map[string]bool
+	eventTypes     map[types.ContainerEventType]bool
 }
 
-// GetContainerEvents sends the stream of container events to clients
-func (s *Server) GetContainerEvents(_ *types.GetEventsRequest, ces types.RuntimeService_GetContainerEventsServer) error {
-	if !s.Config().EnablePodEvents {
-		return nil
+// newContainerEventFilter parses the filter from the incoming gRPC metadata.
+func newContainerEventFilter(ctx context.Context) (*containerEventFilter, error) {
+	filter := &containerEventFilter{}
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return filter, nil
 	}
 
-	s.containerEventStreamBroadcaster.Do(func() {
-		// note that this function will run indefinitely until ContainerEventsChan is closed
-		go s.broadcastEvents()
-	})
-
-	conn := &containerEventConn{
-		wg: sync.WaitGroup{},
+	if values := md.Get(ContainerEventsSinceSequenceKey); len(values) > 0 {
+		sequence, err := strconv.ParseInt(values[len(values)-1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", ContainerEventsSinceSequenceKey, err)
+		}
+		filter.sinceSequence = &sequence
 	}
 
-	s.containerEventClients.Store(ces, conn)
-	conn.wg.Add(1)
+	if values := md.Get(ContainerEventsSinceKey); len(values) > 0 {
+		since, err := strconv.ParseInt(values[len(values)-1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parse %s: %w", ContainerEventsSinceKey, err)
+		}
+		filter.sinceCreatedAt = &since
+	}
 
-	// wait here until we don't want to send events to this client anymore
-	conn.wg.Wait()
-	s.containerEventClients.Delete(ces)
-	return conn.err
-}
+	for _, id := range md.Get(ContainerEventsPodSandboxIDKey) {
+		if filter.podSandboxIDs == nil {
+			filter.podSandboxIDs = make(map[string]bool)
+		}
+		filter.podSandboxIDs[id] = true
+	}
 
-func (s *Server) broadcastEvents() {
-	// notify all connections that ContainerEventsChan has been closed
-	defer s.containerEventClients.Range(func(_, value any) bool { // nolint: unparam
-		conn, ok := value.(*containerEventConn)
+	for _, eventType := range md.Get(ContainerEventsTypeKey) {
+		value, ok := types.ContainerEventType_value[eventType]
 		if !ok {
-			return true
+			return nil, fmt.Errorf("unknown container event type %q", eventType)
+		}
+		if filter.eventTypes == nil {
+			filter.eventTypes = make(map[types.ContainerEventType]bool)
 		}
-		conn.wg.Done()
+		filter.eventTypes[types.ContainerEventType(value)] = true
+	}
+
+	return filter, nil
+}
+
+// matches returns true if the event should be sent to the client.
+func (f *containerEventFilter) matches(event *types.ContainerEventResponse) bool {
+	if f.podSandboxIDs != nil && !f.podSandboxIDs[event.GetPodSandboxStatus().GetId()] {
+		return false
+	}
+	if f.eventTypes != nil && !f.eventTypes[event.GetContainerEventType()] {
+		return false
+	}
+	return true
+}
+
+// unfiltered returns true if the client receives all events.
+func (f *containerEventFilter) unfiltered() bool {
+	return f.podSandboxIDs == nil && f.eventTypes == nil
+}
+
+// since returns true if the event is newer than requested by the replay
+// options, if any.
+func (f *containerEventFilter) since(event *types.ContainerEventResponse) bool {
+	createdAt := event.GetCreatedAt()
+	if f.sinceSequence != nil && createdAt <= *f.sinceSequence {
+		return false
+	}
+	if f.sinceCreatedAt != nil && createdAt < *f.sinceCreatedAt {
+		return false
+	}
+	return true
+}
+
+// replays returns true if the buffered event should be sent to the client on
+// subscription. Without replay options, only the events which have not been
+// delivered to any unfiltered client yet get replayed.
+func (f *containerEventFilter) replays(entry *bufferedContainerEvent) bool {
+	if f.sinceSequence == nil && f.sinceCreatedAt == nil {
+		return !entry.delivered
+	}
+	return f.since(entry.event)
+}
+
+// containerEventClient is a single GetContainerEvents stream.
+type containerEventClient struct {
+	filter *containerEventFilter
+	events chan *types.ContainerEventResponse
+	err    error
+}
+
+// bufferedContainerEvent is a buffered event along with its delivery state.
+type bufferedContainerEvent struct {
+	event *types.ContainerEventResponse
+	// delivered is true if the event has been queued for any unfiltered
+	// client, so filtered clients do not hide events from other clients.
+	delivered bool
+}
+
+// containerEventBuffer is a bounded ring buffer of the recent container
+// events, which dispatches new events to all subscribed clients.
+type containerEventBuffer struct {
+	mu      sync.Mutex
+	events  []*bufferedContainerEvent
+	next    int
+	clients map[*containerEventClient]struct{}
+	closed  bool
+
+	// publishMu serializes generating events, which keeps their sequence
+	// numbers in the order of the events channel.
+	publishMu     sync.Mutex
+	lastCreatedAt int64
+}
+
+func newContainerEventBuffer(size int) *containerEventBuffer {
+	return &containerEventBuffer{
+		events:  make([]*bufferedContainerEvent, 0, size),
+		clients: make(map[*containerEventClient]struct{}),
+	}
+}
+
+// publish stamps the event with a strictly increasing creation timestamp and
+// sends it to the events channel without blocking. It returns false if the
+// channel is full.
+func (b *containerEventBuffer) publish(events chan<- types.ContainerEventResponse, event types.ContainerEventResponse) bool {
+	b.publishMu.Lock()
+	defer b.publishMu.Unlock()
+
+	event.CreatedAt = max(time.Now().UnixNano(), b.lastCreatedAt+1)
+	select {
+	case events <- event:
+		b.lastCreatedAt = event.CreatedAt
 		return true
-	})
+	default:
+		return false
+	}
+}
 
-	for containerEvent := range s.ContainerEventsChan {
-		s.containerEventClients.Range(func(key, value any) bool {
-			stream, ok := key.(types.RuntimeService_GetContainerEventsServer)
-			if !ok {
-				return true
-			}
+// add buffers the event and dispatches it to all matching clients. Clients
+// which do not keep up get disconnected, they can resume afterwards.
+func (b *containerEventBuffer) add(event *types.ContainerEventResponse) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	entry := &bufferedContainerEvent{event: event}
+	if len(b.events) < cap(b.events) {
+		b.events = append(b.events, entry)
+	} else {
+		b.events[b.next] = entry
+	}
+	b.next = (b.next + 1) % cap(b.events)
+
+	for client := range b.clients {
+		if !client.filter.matches(event) || !client.filter.since(event) {
+			continue
+		}
+		select {
+		case client.events <- event:
+			entry.delivered = entry.delivered || client.filter.unfiltered()
+		default:
+			metrics.Instance().MetricContainersEventsDroppedInc()
+			client.err = status.Errorf(codes.ResourceExhausted,
+				"client does not keep up with the container events, resume from sequence %d", event.GetCreatedAt()-1)
+			b.unsubscribeLocked(client)
+		}
+	}
+}
+
+// subscribe registers a new client and returns the sequence number of the
+// latest event. The buffered events selected by the filter are queued first.
+func (b *containerEventBuffer) subscribe(filter *containerEventFilter) (*containerEventClient, int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	client := &containerEventClient{
+		filter: filter,
+		events: make(chan *types.ContainerEventResponse, containerEventsClientBufferSize+cap(b.events)),
+	}
+	var sequence int64
+	if len(b.events) > 0 {
+		sequence = b.events[(b.next+len(b.events)-1)%len(b.events)].event.GetCreatedAt()
+	}
+	if b.closed {
+		close(client.events)
+		return client, sequence
+	}
+
+	// the oldest event is the next one to be overwritten once the buffer is full
+	start := 0
+	if len(b.events) == cap(b.events) {
+		start = b.next
+	}
+	for i := range b.events {
+		entry := b.events[(start+i)%len(b.events)]
+		if filter.matches(entry.event) && filter.replays(entry) {
+			client.events <- entry.event
+			entry.delivered = entry.delivered || filter.unfiltered()
+		}
+	}
+
+	b.clients[client] = struct{}{}
+	return client, sequence
+}
+
+// unsubscribe removes the client, which stops all further events.
+func (b *containerEventBuffer) unsubscribe(client *containerEventClient) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.unsubscribeLocked(client)
+}
+
+func (b *containerEventBuffer) unsubscribeLocked(client *containerEventClient) {
+	if _, ok := b.clients[client]; !ok {
+		return
+	}
+	delete(b.clients, client)
+	close(client.events)
+}
+
+// close disconnects all clients after their queued events have been sent.
+func (b *containerEventBuffer) close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for client := range b.clients {
+		b.unsubscribeLocked(client)
+	}
+}
+
+// GetContainerEvents sends the stream of container events to clients
+func (s *Server) GetContainerEvents(_ *types.GetEventsRequest, ces types.RuntimeService_GetContainerEventsServer) error {
+	if !s.Config().EnablePodEvents {
+		return nil
+	}
+
+	ctx := ces.Context()
+	filter, err := newContainerEventFilter(ctx)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid container events request: %v", err)
+	}
 
-			conn, ok := value.(*containerEventConn)
+	client, sequence := s.containerEventBuffer.subscribe(filter)
+	defer s.containerEventBuffer.unsubscribe(client)
+
+	if err := ces.SendHeader(metadata.Pairs(ContainerEventsSequenceKey, strconv.FormatInt(sequence, 10))); err != nil {
+		log.Debugf(ctx, "Unable to send container events header: %v", err)
+	}
+
+	for {
+		select {
+		case event, ok := <-client.events:
 			if !ok {
-				return true
+				// the events buffer has been closed or the client got disconnected
+				return client.err
 			}
-
-			if err := stream.Send(&containerEvent); err != nil {
+			if err := ces.Send(event); err != nil {
 				code, _ := status.FromError(err)
 				// when the client closes the connection this error is expected
-				// so only log non transport closing errors
+				// so only return non transport closing errors
 				if code.Code() != codes.Unavailable && code.Message() != "transport is closing" {
-					conn.err = err
+					return err
 				}
-				// notify our waiting client connection that we are done
-				conn.wg.Done()
+				return nil
 			}
-			return true
-		})
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// broadcastEvents buffers and dispatches the events until
+// ContainerEventsChan gets closed.
+func (s *Server) broadcastEvents() {
+	// notify all connections that ContainerEventsChan has been closed
+	defer s.containerEventBuffer.close()
+
+	for containerEvent := range s.ContainerEventsChan {
+		event := containerEvent
+		s.containerEventBuffer.add(&event)
 	}
 }
@@ -1,11 +1,17 @@
 package server_test
 
 import (
+	"context"
 	"time"
 
+	"github.com/cri-o/cri-o/server"
 	containereventservermock "github.com/cri-o/cri-o/test/mocks/containereventserver"
+	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 	types "k8s.io/cri-api/pkg/apis/runtime/v1"
 )
 
@@ -21,6 +27,21 @@ var events = []types.ContainerEventResponse{
 	},
 }
 
+// mockContainerEventsClient returns a mocked events stream using the
+// provided gRPC metadata as request options.
+func mockContainerEventsClient(md metadata.MD) *containereventservermock.MockRuntimeService_GetContainerEventsServer {
+	return mockContainerEventsClientWithContext(context.Background(), md)
+}
+
+// mockContainerEventsClientWithContext returns a mocked events stream which
+// gets disconnected once the provided context is done.
+func mockContainerEventsClientWithContext(ctx context.Context, md metadata.MD) *containereventservermock.MockRuntimeService_GetContainerEventsServer {
+	ces := containereventservermock.NewMockRuntimeService_GetContainerEventsServer(mockCtrl)
+	ces.EXPECT().Context().Return(metadata.NewIncomingContext(ctx, md)).AnyTimes()
+	ces.EXPECT().SendHeader(gomock.Any()).Return(nil).AnyTimes()
+	return ces
+}
+
 var _ = t.Describe("ContainerEvents", func() {
 	BeforeEach(func() {
 		beforeEach()
@@ -28,9 +49,12 @@ var _ = t.Describe("ContainerEvents", func() {
 
 		// close after all events have been processed,
 		// so we are not waiting for move events to come.
+		// The channel of this spec gets closed, even if the next
+		// spec already replaced the sut.
+		eventsChan := sut.ContainerEventsChan
 		go func() {
 			time.Sleep(2 * time.Second)
-			close(sut.ContainerEventsChan)
+			close(eventsChan)
 		}()
 	})
 
@@ -38,7 +62,7 @@ var _ = t.Describe("ContainerEvents", func() {
 
 	t.Describe("ContainerEvents", func() {
 		It("should send events to single client", func() {
-			cesMock := containereventservermock.NewMockRuntimeService_GetContainerEventsServer(mockCtrl)
+			cesMock := mockContainerEventsClient(nil)
 			// EXPECT expects the exact object, so we can't use the copy range gives us
 			for i := range events {
 				cesMock.EXPECT().Send(&events[i]).Return(nil)
@@ -53,8 +77,8 @@ var _ = t.Describe("ContainerEvents", func() {
 		})
 
 		It("should send events all events to both clients", func() {
-			client1 := containereventservermock.NewMockRuntimeService_GetContainerEventsServer(mockCtrl)
-			client2 := containereventservermock.NewMockRuntimeService_GetContainerEventsServer(mockCtrl)
+			client1 := mockContainerEventsClient(nil)
+			client2 := mockContainerEventsClient(nil)
 
 			for i := range events {
 				client1.EXPECT().Send(&events[i]).Return(nil)
@@ -77,5 +101,160 @@ var _ = t.Describe("ContainerEvents", func() {
 				sut.ContainerEventsChan <- event
 			}
 		})
+
+		It("should replay events already sent to other clients", func() {
+			client1 := mockContainerEventsClient(nil)
+			client2 := mockContainerEventsClient(metadata.Pairs(server.ContainerEventsSinceKey, "0"))
+
+			received := make(chan struct{}, len(events))
+			for i := range events {
+				client1.EXPECT().Send(&events[i]).DoAndReturn(func(*types.ContainerEventResponse) error {
+					received <- struct{}{}
+					return nil
+				})
+				client2.EXPECT().Send(&events[i]).Return(nil)
+			}
+
+			for _, event := range events {
+				sut.ContainerEventsChan <- event
+			}
+
+			go func() {
+				defer GinkgoRecover()
+				err := sut.GetContainerEvents(nil, client1)
+				Expect(err).ToNot(HaveOccurred())
+			}()
+			for range events {
+				Eventually(received).Should(Receive())
+			}
+
+			err := sut.GetContainerEvents(nil, client2)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should replay events after the provided sequence", func() {
+			cesMock := mockContainerEventsClient(metadata.Pairs(server.ContainerEventsSinceSequenceKey, "10"))
+			sequenced := []types.ContainerEventResponse{
+				{ContainerId: "1", CreatedAt: 10},
+				{ContainerId: "2", CreatedAt: 20},
+				{ContainerId: "3", CreatedAt: 30},
+			}
+			cesMock.EXPECT().Send(&sequenced[1]).Return(nil)
+			cesMock.EXPECT().Send(&sequenced[2]).Return(nil)
+
+			for _, event := range sequenced {
+				sut.ContainerEventsChan <- event
+			}
+
+			err := sut.GetContainerEvents(nil, cesMock)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should replay events since the provided timestamp", func() {
+			cesMock := mockContainerEventsClient(metadata.Pairs(server.ContainerEventsSinceKey, "20"))
+			event := types.ContainerEventResponse{ContainerId: "2", CreatedAt: 20}
+			cesMock.EXPECT().Send(&event).Return(nil)
+
+			sut.ContainerEventsChan <- types.ContainerEventResponse{ContainerId: "1", CreatedAt: 10}
+			sut.ContainerEventsChan <- event
+
+			err := sut.GetContainerEvents(nil, cesMock)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should filter events by pod sandbox ID and type", func() {
+			cesMock := mockContainerEventsClient(metadata.Pairs(
+				server.ContainerEventsPodSandboxIDKey, "pod1",
+				server.ContainerEventsTypeKey, types.ContainerEventType_CONTAINER_STARTED_EVENT.String(),
+			))
+			event := types.ContainerEventResponse{
+				ContainerId:        "1",
+				ContainerEventType: types.ContainerEventType_CONTAINER_STARTED_EVENT,
+				PodSandboxStatus:   &types.PodSandboxStatus{Id: "pod1"},
+			}
+			cesMock.EXPECT().Send(&event).Return(nil)
+
+			sut.ContainerEventsChan <- types.ContainerEventResponse{
+				ContainerId:        "1",
+				ContainerEventType: types.ContainerEventType_CONTAINER_CREATED_EVENT,
+				PodSandboxStatus:   &types.PodSandboxStatus{Id: "pod1"},
+			}
+			sut.ContainerEventsChan <- event
+			sut.ContainerEventsChan <- types.ContainerEventResponse{
+				ContainerId:        "2",
+				ContainerEventType: types.ContainerEventType_CONTAINER_STARTED_EVENT,
+				PodSandboxStatus:   &types.PodSandboxStatus{Id: "pod2"},
+			}
+
+			err := sut.GetContainerEvents(nil, cesMock)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should resume after a disconnect with a filter", func() {
+			podEvent := func(pod string, createdAt int64) types.ContainerEventResponse {
+				return types.ContainerEventResponse{
+					ContainerId:      pod + "-ctr",
+					CreatedAt:        createdAt,
+					PodSandboxStatus: &types.PodSandboxStatus{Id: pod},
+				}
+			}
+			before := []types.ContainerEventResponse{podEvent("pod1", 10), podEvent("pod2", 20)}
+			after := []types.ContainerEventResponse{podEvent("pod1", 30), podEvent("pod2", 40), podEvent("pod1", 50)}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			client := mockContainerEventsClientWithContext(ctx, metadata.Pairs(server.ContainerEventsPodSandboxIDKey, "pod1"))
+			received := make(chan struct{})
+			client.EXPECT().Send(&before[0]).DoAndReturn(func(*types.ContainerEventResponse) error {
+				close(received)
+				return nil
+			})
+			resumed := mockContainerEventsClient(metadata.Pairs(
+				server.ContainerEventsPodSandboxIDKey, "pod1",
+				server.ContainerEventsSinceSequenceKey, "10",
+			))
+			gomock.InOrder(
+				resumed.EXPECT().Send(&after[0]).Return(nil),
+				resumed.EXPECT().Send(&after[2]).Return(nil),
+			)
+			// the filtered clients do not hide any events from unfiltered ones
+			unfiltered := mockContainerEventsClient(nil)
+			calls := []*gomock.Call{}
+			for _, events := range [][]types.ContainerEventResponse{before, after} {
+				for i := range events {
+					calls = append(calls, unfiltered.EXPECT().Send(&events[i]).Return(nil))
+				}
+			}
+			gomock.InOrder(calls...)
+
+			for _, event := range before {
+				sut.ContainerEventsChan <- event
+			}
+			disconnected := make(chan error)
+			go func() {
+				disconnected <- sut.GetContainerEvents(nil, client)
+			}()
+			Eventually(received).Should(BeClosed())
+			cancel()
+			Eventually(disconnected).Should(Receive(BeNil()))
+
+			for _, event := range after {
+				sut.ContainerEventsChan <- event
+			}
+			go func() {
+				defer GinkgoRecover()
+				err := sut.GetContainerEvents(nil, unfiltered)
+				Expect(err).ToNot(HaveOccurred())
+			}()
+			err := sut.GetContainerEvents(nil, resumed)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should fail with invalid options", func() {
+			cesMock := mockContainerEventsClient(metadata.Pairs(server.ContainerEventsTypeKey, "wrong"))
+
+			err := sut.GetContainerEvents(nil, cesMock)
+			Expect(err).To(HaveOccurred())
+			Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+		})
 	})
 })
@@ -0,0 +1,96 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"syscall"
+
+	"github.com/cri-o/cri-o/internal/config/cgmgr"
+	"github.com/cri-o/cri-o/internal/lib/sandbox"
+	"github.com/cri-o/cri-o/internal/log"
+	"github.com/cri-o/cri-o/internal/oci"
+	"github.com/cri-o/cri-o/server/metrics"
+	"golang.org/x/sys/unix"
+)
+
+// maxExitSignal is the highest signal number which can be encoded in an exit
+// code as 128 plus the signal number.
+const maxExitSignal = 64
+
+// containerExitReason classifies why the stopped container exited and
+// returns the reason and message reported in its status.
+func containerExitReason(state *oci.ContainerState) (reason, message string) {
+	exitCode := int32(-1)
+	if state.ExitCode != nil {
+		exitCode = *state.ExitCode
+	}
+
+	switch {
+	case state.SeccompKilled:
+		return seccompKilledReason, state.Error
+	case state.StartFailed:
+		return startErrorReason, state.Error
+	case state.EvictedBy != "":
+		return evictedReason, fmt.Sprintf("The container got evicted by NRI plugin %q: %s", state.EvictedBy, state.EvictionReason)
+	case state.OOMKilled && state.PodOOMKilled:
+		return oomKilledReason, "The container got OOM killed because the pod ran out of memory"
+	case state.OOMKilled:
+		return oomKilledReason, ""
+	case state.StopTimedOut:
+		return stopTimeoutReason, "The container did not stop within its stop timeout and got killed"
+	case exitCode == 0:
+		return completedReason, ""
+	}
+
+	if sig, ok := exitSignal(exitCode); ok {
+		return signaledReason, "The container got killed by signal " + unix.SignalName(sig)
+	}
+	return errorReason, state.Error
+}
+
+// exitSignal returns the signal which killed the container process if the
+// exit code encodes one.
+func exitSignal(exitCode int32) (syscall.Signal, bool) {
+	if exitCode <= 128 || exitCode > 128+maxExitSignal {
+		return 0, false
+	}
+	return syscall.Signal(exitCode - 128), true
+}
+
+// recordPodOOMCount stores the current number of OOM events of the pod
+// cgroup, to be able to tell later whether the pod ran out of memory while
+// the container was running.
+func (s *Server) recordPodOOMCount(ctx context.Context, c *oci.Container, sb *sandbox.Sandbox) {
+	count, err := cgmgr.PodOOMCount(s.config.CgroupManager(), sb.CgroupParent(), c.ID())
+	if err != nil {
+		log.Debugf(ctx, "Unable to get pod OOM count for container %s: %v", c.ID(), err)
+		return
+	}
+	c.SetPodOOMCount(count)
+}
+
+// recordContainerExit classifies the exit of the container and collects it
+// as metric. An OOM killed container is considered to be killed by the pod
+// running out of memory if the pod cgroup invoked the OOM killer since the
+// container got started.
+func (s *Server) recordContainerExit(ctx context.Context, c *oci.Container, sb *sandbox.Sandbox) {
+	state := c.State()
+	if state.Status != oci.ContainerStateStopped {
+		return
+	}
+
+	if state.OOMKilled {
+		count, err := cgmgr.PodOOMCount(s.config.CgroupManager(), sb.CgroupParent(), c.ID())
+		if err != nil {
+			log.Debugf(ctx, "Unable to get pod OOM count for container %s: %v", c.ID(), err)
+		} else if c.SetPodOOMKilled(count) {
+			if err := s.ContainerStateToDisk(ctx, c); err != nil {
+				log.Warnf(ctx, "Unable to write container %s state to disk: %v", c.ID(), err)
+			}
+		}
+	}
+
+	reason, _ := containerExitReason(c.State())
+	log.Infof(ctx, "Container %s exited with reason %s", c.ID(), reason)
+	metrics.Instance().MetricContainersExitsTotalInc(reason)
+}
@@ -4,13 +4,23 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
+	"strings"
 
 	"github.com/containers/storage/pkg/pools"
+	"github.com/cri-o/cri-o/internal/lib/sandbox"
 	"github.com/cri-o/cri-o/internal/log"
+	"github.com/cri-o/cri-o/internal/oci"
+	libconfig "github.com/cri-o/cri-o/pkg/config"
 	"golang.org/x/net/context"
 	types "k8s.io/cri-api/pkg/apis/runtime/v1"
 )
 
+// PortForwardProtocolHeader is the header of a port forward data stream, which
+// selects the protocol ("tcp" or "udp") to forward the port with. Every UDP
+// datagram is framed on the stream by a two byte big endian length prefix.
+const PortForwardProtocolHeader = "protocol"
+
 // PortForward prepares a streaming endpoint to forward ports from a PodSandbox.
 func (s *Server) PortForward(ctx context.Context, req *types.PortForwardRequest) (*types.PortForwardResponse, error) {
 	resp, err := s.getPortForward(req)
@@ -52,15 +62,64 @@ func (s StreamService) PortForward(ctx context.Context, podSandboxID string, por
 		return fmt.Errorf("sandbox %s is not running", podSandboxID)
 	}
 
+	protocol, err := portForwardProtocol(stream)
+	if err != nil {
+		return err
+	}
+
+	ctr := sb.InfraContainer()
 	netNsPath := sb.NetNsPath()
 	if netNsPath == "" {
-		return fmt.Errorf(
-			"network namespace path of sandbox %s is empty", sb.ID(),
-		)
+		// VM based runtimes are able to dial the port inside the guest, which
+		// requires a running container providing the port forwarder.
+		runtimeType, err := s.runtimeServer.Runtime().RuntimeType(sb.RuntimeHandler())
+		if err != nil || runtimeType != libconfig.RuntimeTypeVM {
+			return fmt.Errorf(
+				"network namespace path of sandbox %s is empty", sb.ID(),
+			)
+		}
+		ctr = runningWorkloadContainer(sb)
+		if ctr == nil {
+			return fmt.Errorf("sandbox %s has no running container to forward the port", sb.ID())
+		}
 	}
 
 	// defer responsibility of emptying stream to PortForwardContainer
 	emptyStreamOnError = false
 
-	return s.runtimeServer.Runtime().PortForwardContainer(ctx, sb.InfraContainer(), netNsPath, port, stream)
+	return s.runtimeServer.Runtime().PortForwardContainer(ctx, ctr, netNsPath, port, protocol, stream)
+}
+
+// portForwardProtocol returns the protocol to forward the port with, which
+// gets selected by the PortForwardProtocolHeader of the data stream. Streams
+// without the header, like the ones of the websocket protocol, get forwarded
+// as TCP.
+func portForwardProtocol(stream io.ReadWriteCloser) (types.Protocol, error) {
+	headerStream, ok := stream.(interface{ Headers() http.Header })
+	if !ok {
+		return types.Protocol_TCP, nil
+	}
+	switch value := headerStream.Headers().Get(PortForwardProtocolHeader); strings.ToLower(value) {
+	case "", "tcp":
+		return types.Protocol_TCP, nil
+	case "udp":
+		return types.Protocol_UDP, nil
+	default:
+		return types.Protocol_TCP, fmt.Errorf("unsupported port forward protocol %q", value)
+	}
+}
+
+// runningWorkloadContainer returns the oldest running container of the
+// sandbox, excluding the infra container.
+func runningWorkloadContainer(sb *sandbox.Sandbox) *oci.Container {
+	var res *oci.Container
+	for _, ctr := range sb.Containers().List() {
+		if ctr.StateNoLock().Status != oci.ContainerStateRunning {
+			continue
+		}
+		if res == nil || ctr.CreatedAt().Before(res.CreatedAt()) {
+			res = ctr
+		}
+	}
+	return res
 }
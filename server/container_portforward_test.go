@@ -1,13 +1,33 @@
 package server_test
 
 import (
+	"bytes"
 	"context"
+	"net/http"
 
+	"github.com/cri-o/cri-o/internal/oci"
+	"github.com/cri-o/cri-o/server"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	specs "github.com/opencontainers/runtime-spec/specs-go"
 	types "k8s.io/cri-api/pkg/apis/runtime/v1"
 )
 
+// headerStream is a port forward stream carrying headers, like the data
+// streams of the SPDY protocol.
+type headerStream struct {
+	bytes.Buffer
+	headers http.Header
+}
+
+func (s *headerStream) Close() error {
+	return nil
+}
+
+func (s *headerStream) Headers() http.Header {
+	return s.headers
+}
+
 // The actual test suite
 var _ = t.Describe("ContainerPortforward", func() {
 	// Prepare the sut
@@ -54,5 +74,23 @@ var _ = t.Describe("ContainerPortforward", func() {
 			// Then
 			Expect(err).To(HaveOccurred())
 		})
+
+		It("should fail on an unsupported protocol of the stream", func() {
+			// Given
+			addContainerAndSandbox()
+			testStreamService.SetRuntimeServer(sut)
+			testContainer.SetState(&oci.ContainerState{
+				State: specs.State{Status: oci.ContainerStateRunning},
+			})
+			stream := &headerStream{headers: http.Header{}}
+			stream.headers.Set(server.PortForwardProtocolHeader, "sctp")
+
+			// When
+			err := testStreamService.PortForward(context.Background(), testSandbox.ID(), 5353, stream)
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("unsupported port forward protocol"))
+		})
 	})
 })
@@ -11,6 +11,7 @@ import (
 	"github.com/cri-o/cri-o/internal/factory/container"
 	"github.com/cri-o/cri-o/internal/lib/sandbox"
 	"github.com/cri-o/cri-o/internal/log"
+	"github.com/cri-o/cri-o/internal/oci"
 	"github.com/cri-o/cri-o/internal/storage"
 	"github.com/cri-o/cri-o/pkg/annotations"
 	spec "github.com/opencontainers/runtime-spec/specs-go"
@@ -108,6 +109,7 @@ func (s *Server) CRImportCheckpoint(
 				metadata.NetworkStatusFile,
 				metadata.DeletedFilesFile,
 				metadata.CheckpointDirectory,
+				oci.CheckpointPreDumpDirectory,
 			},
 		}
 		mountPoint, err = os.MkdirTemp("", "checkpoint")
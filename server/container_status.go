@@ -18,6 +18,10 @@ import (
 const (
 	oomKilledReason     = "OOMKilled"
 	seccompKilledReason = "seccomp killed"
+	signaledReason      = "Signaled"
+	startErrorReason    = "StartError"
+	stopTimeoutReason   = "StopTimeout"
+	evictedReason       = "Evicted"
 	completedReason     = "Completed"
 	errorReason         = "Error"
 )
@@ -100,18 +104,7 @@ func (s *Server) ContainerStatus(ctx context.Context, req *types.ContainerStatus
 		} else {
 			resp.Status.ExitCode = *cState.ExitCode
 		}
-		switch {
-		case cState.OOMKilled:
-			resp.Status.Reason = oomKilledReason
-		case cState.SeccompKilled:
-			resp.Status.Reason = seccompKilledReason
-			resp.Status.Message = cState.Error
-		case resp.Status.ExitCode == 0:
-			resp.Status.Reason = completedReason
-		default:
-			resp.Status.Reason = errorReason
-			resp.Status.Message = cState.Error
-		}
+		resp.Status.Reason, resp.Status.Message = containerExitReason(cState)
 	}
 
 	resp.Status.State = rStatus
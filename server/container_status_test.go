@@ -87,6 +87,67 @@ var _ = t.Describe("ContainerStatus", func() {
 			}, types.ContainerState_CONTAINER_EXITED, false),
 		)
 
+		DescribeTable("should report the exit reason", func(
+			givenState *oci.ContainerState,
+			expectedReason, expectedMessage string,
+		) {
+			// Given
+			setupSUT()
+			addContainerAndSandbox()
+			givenState.Status = oci.ContainerStateStopped
+			testContainer.SetStateAndSpoofPid(givenState)
+			testContainer.SetSpec(&specs.Spec{Version: "1.0.0"})
+
+			// When
+			response, err := sut.ContainerStatus(context.Background(),
+				&types.ContainerStatusRequest{ContainerId: testContainer.ID()})
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Status.State).To(Equal(types.ContainerState_CONTAINER_EXITED))
+			Expect(response.Status.Reason).To(Equal(expectedReason))
+			Expect(response.Status.Message).To(Equal(expectedMessage))
+		},
+			Entry("Completed", &oci.ContainerState{
+				ExitCode: utils.Int32Ptr(0),
+			}, "Completed", ""),
+			Entry("Error", &oci.ContainerState{
+				ExitCode: utils.Int32Ptr(1),
+				Error:    "error",
+			}, "Error", "error"),
+			Entry("Signaled", &oci.ContainerState{
+				ExitCode: utils.Int32Ptr(143),
+			}, "Signaled", "The container got killed by signal SIGTERM"),
+			Entry("OOMKilled", &oci.ContainerState{
+				ExitCode:  utils.Int32Ptr(137),
+				OOMKilled: true,
+			}, "OOMKilled", ""),
+			Entry("PodOOMKilled", &oci.ContainerState{
+				ExitCode:     utils.Int32Ptr(137),
+				OOMKilled:    true,
+				PodOOMKilled: true,
+			}, "OOMKilled", "The container got OOM killed because the pod ran out of memory"),
+			Entry("SeccompKilled", &oci.ContainerState{
+				ExitCode:      utils.Int32Ptr(137),
+				SeccompKilled: true,
+				Error:         "Used forbidden syscalls: mkdir",
+			}, "seccomp killed", "Used forbidden syscalls: mkdir"),
+			Entry("StartError", &oci.ContainerState{
+				ExitCode:    utils.Int32Ptr(255),
+				StartFailed: true,
+				Error:       "exec: no such file or directory",
+			}, "StartError", "exec: no such file or directory"),
+			Entry("StopTimeout", &oci.ContainerState{
+				ExitCode:     utils.Int32Ptr(137),
+				StopTimedOut: true,
+			}, "StopTimeout", "The container did not stop within its stop timeout and got killed"),
+			Entry("Evicted", &oci.ContainerState{
+				ExitCode:       utils.Int32Ptr(143),
+				EvictedBy:      "10-evictor",
+				EvictionReason: "node is overcommitted",
+			}, "Evicted", `The container got evicted by NRI plugin "10-evictor": node is overcommitted`),
+		)
+
 		It("should fail with invalid container ID", func() {
 			// Given
 			// When
@@ -3,6 +3,7 @@ package server_test
 import (
 	"context"
 
+	"github.com/containerd/nri/pkg/api"
 	"github.com/cri-o/cri-o/internal/oci"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo/v2"
@@ -53,4 +54,32 @@ var _ = t.Describe("ContainerStop", func() {
 			Expect(err).To(HaveOccurred())
 		})
 	})
+
+	t.Describe("EvictContainer", func() {
+		It("should not evict a stopped container", func() {
+			// Given
+			addContainerAndSandbox()
+			testContainer.SetState(&oci.ContainerState{
+				State: specs.State{Status: oci.ContainerStateStopped},
+			})
+
+			// When
+			err := sut.EvictContainer(context.Background(), "10-evictor",
+				&api.ContainerEviction{ContainerId: testContainer.ID(), Reason: "test"})
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(testContainer.State().EvictedBy).To(BeEmpty())
+		})
+
+		It("should ignore an unknown container", func() {
+			// Given
+			// When
+			err := sut.EvictContainer(context.Background(), "10-evictor",
+				&api.ContainerEviction{ContainerId: "id", Reason: "test"})
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+		})
+	})
 })
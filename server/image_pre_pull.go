@@ -0,0 +1,186 @@
+package server
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/cri-o/cri-o/internal/log"
+	crioTypes "github.com/cri-o/cri-o/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+// The states of an image pulled in the background.
+const (
+	prePullStatePending = "pending"
+	prePullStatePulling = "pulling"
+	prePullStatePresent = "present"
+	prePullStateFailed  = "failed"
+)
+
+// The backoff between the attempts to pull an image in the background.
+const (
+	prePullInitialBackoff = 5 * time.Second
+	prePullMaxBackoff     = 5 * time.Minute
+)
+
+// imagePrePuller makes sure that the configured images are present in the
+// local storage.
+type imagePrePuller struct {
+	images []*prePullImage
+	mutex  sync.Mutex
+}
+
+// prePullImage is an image pulled in the background.
+type prePullImage struct {
+	info   *crioTypes.PrePullImageInfo
+	cancel context.CancelFunc
+}
+
+// startImagePrePull pulls all configured images in the background. Images
+// which are already pulled in the background are kept as they are, while the
+// pre-pull of images no longer configured gets cancelled.
+func (s *Server) startImagePrePull(ctx context.Context) {
+	p := s.imagePrePuller
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	previous := make(map[string]*prePullImage, len(p.images))
+	for _, image := range p.images {
+		previous[image.info.Image] = image
+	}
+
+	images := make([]*prePullImage, 0, len(s.config.PrePullImages))
+	seen := make(map[string]bool, len(s.config.PrePullImages))
+	for _, name := range s.config.PrePullImages {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		image, ok := previous[name]
+		if ok {
+			delete(previous, name)
+		} else {
+			imageCtx, cancel := context.WithCancel(ctx)
+			image = &prePullImage{
+				info: &crioTypes.PrePullImageInfo{
+					Image: name,
+					State: prePullStatePending,
+				},
+				cancel: cancel,
+			}
+			go s.prePullImage(imageCtx, image.info)
+		}
+		images = append(images, image)
+	}
+
+	for _, image := range previous {
+		log.Infof(ctx, "Stopping to pre-pull image %s", image.info.Image)
+		image.cancel()
+	}
+	p.images = images
+}
+
+// stopImagePrePull cancels all outstanding background pulls.
+func (s *Server) stopImagePrePull() {
+	p := s.imagePrePuller
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	for _, image := range p.images {
+		image.cancel()
+	}
+	p.images = nil
+}
+
+// prePullImage pulls the image until it succeeded or the context got cancelled.
+func (s *Server) prePullImage(ctx context.Context, info *crioTypes.PrePullImageInfo) {
+	backoff := wait.Backoff{
+		Duration: prePullInitialBackoff,
+		Factor:   2,
+		Jitter:   0.1,
+		Steps:    math.MaxInt32,
+		Cap:      prePullMaxBackoff,
+	}
+
+	for {
+		s.imagePrePuller.update(info, func(info *crioTypes.PrePullImageInfo) {
+			info.State = prePullStatePulling
+			info.Attempts++
+			info.LastAttemptTime = time.Now().UnixNano()
+		})
+
+		err := s.ensureImagePresent(ctx, info.Image)
+		if err == nil {
+			s.imagePrePuller.update(info, func(info *crioTypes.PrePullImageInfo) {
+				info.State = prePullStatePresent
+				info.LastError = ""
+			})
+			return
+		}
+
+		if ctx.Err() != nil {
+			// The pre-pull got cancelled, for example by a config reload
+			return
+		}
+		delay := backoff.Step()
+		log.Warnf(ctx, "Unable to pre-pull image %s, retrying in %v: %v", info.Image, delay, err)
+		s.imagePrePuller.update(info, func(info *crioTypes.PrePullImageInfo) {
+			info.State = prePullStateFailed
+			info.LastError = err.Error()
+		})
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+	}
+}
+
+// ensureImagePresent pulls the image if it is not available locally.
+func (s *Server) ensureImagePresent(ctx context.Context, image string) error {
+	candidates, err := s.StorageImageServer().CandidatesForPotentiallyShortImageName(s.config.SystemContext, image)
+	if err != nil {
+		return err
+	}
+	for _, candidate := range candidates {
+		if _, err := s.StorageImageServer().ImageStatusByName(s.config.SystemContext, candidate); err == nil {
+			log.Debugf(ctx, "Image %s to pre-pull is already present", image)
+			return nil
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	_, err = s.PullImage(ctx, &types.PullImageRequest{Image: &types.ImageSpec{Image: image}})
+	return err
+}
+
+// update modifies the info while holding the lock.
+func (p *imagePrePuller) update(info *crioTypes.PrePullImageInfo, modify func(*crioTypes.PrePullImageInfo)) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	modify(info)
+}
+
+// info returns the state of all images pulled in the background.
+func (p *imagePrePuller) info() crioTypes.PrePullInfo {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	res := crioTypes.PrePullInfo{
+		Ready:  true,
+		Images: make([]crioTypes.PrePullImageInfo, 0, len(p.images)),
+	}
+	for _, image := range p.images {
+		if image.info.State != prePullStatePresent {
+			res.Ready = false
+		}
+		res.Images = append(res.Images, *image.info)
+	}
+	return res
+}
@@ -0,0 +1,113 @@
+package server_test
+
+import (
+	"context"
+
+	"github.com/cri-o/cri-o/internal/storage"
+	"github.com/cri-o/cri-o/internal/storage/references"
+	"github.com/golang/mock/gomock"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// The actual test suite
+var _ = t.Describe("ImagePrePull", func() {
+	imageCandidate, err := references.ParseRegistryImageReferenceFromOutOfProcessData("docker.io/library/image:latest")
+	Expect(err).ToNot(HaveOccurred())
+	otherCandidate, err := references.ParseRegistryImageReferenceFromOutOfProcessData("docker.io/library/other:latest")
+	Expect(err).ToNot(HaveOccurred())
+	imageID, err := storage.ParseStorageImageIDFromOutOfProcessData("2a03a6059f21e150ae84b0973863609494aad70f0a80eaeb64bddd8d92465812")
+	Expect(err).ToNot(HaveOccurred())
+
+	// Prepare the sut
+	BeforeEach(func() {
+		beforeEach()
+		setupSUT()
+	})
+	AfterEach(func() {
+		sut.StopImagePrePull()
+		afterEach()
+	})
+
+	expectImagePresent := func(image string, candidate storage.RegistryImageReference) {
+		gomock.InOrder(
+			imageServerMock.EXPECT().CandidatesForPotentiallyShortImageName(
+				gomock.Any(), image).
+				Return([]storage.RegistryImageReference{candidate}, nil),
+			imageServerMock.EXPECT().ImageStatusByName(
+				gomock.Any(), candidate).
+				Return(&storage.ImageResult{ID: imageID}, nil),
+		)
+	}
+
+	imageStates := func() map[string]string {
+		res := map[string]string{}
+		for _, info := range sut.ImagePrePullInfo().Images {
+			res[info.Image] = info.State
+		}
+		return res
+	}
+
+	t.Describe("StartImagePrePull", func() {
+		It("should be ready without images", func() {
+			// Given
+			// When
+			sut.StartImagePrePull(context.Background(), nil)
+
+			// Then
+			info := sut.ImagePrePullInfo()
+			Expect(info.Ready).To(BeTrue())
+			Expect(info.Images).To(BeEmpty())
+		})
+
+		It("should report already present images", func() {
+			// Given
+			expectImagePresent("image", imageCandidate)
+
+			// When
+			sut.StartImagePrePull(context.Background(), []string{"image"})
+
+			// Then
+			Eventually(imageStates).Should(Equal(map[string]string{"image": "present"}))
+			info := sut.ImagePrePullInfo()
+			Expect(info.Ready).To(BeTrue())
+			Expect(info.Images[0].Attempts).To(BeEquivalentTo(1))
+			Expect(info.Images[0].LastError).To(BeEmpty())
+		})
+
+		It("should only pre-pull the added images on restart", func() {
+			// Given
+			expectImagePresent("image", imageCandidate)
+			sut.StartImagePrePull(context.Background(), []string{"image"})
+			Eventually(imageStates).Should(Equal(map[string]string{"image": "present"}))
+			expectImagePresent("other", otherCandidate)
+
+			// When
+			sut.StartImagePrePull(context.Background(), []string{"image", "other"})
+
+			// Then
+			Eventually(imageStates).Should(Equal(map[string]string{
+				"image": "present",
+				"other": "present",
+			}))
+			info := sut.ImagePrePullInfo()
+			Expect(info.Ready).To(BeTrue())
+			Expect(info.Images[0].Attempts).To(BeEquivalentTo(1))
+		})
+
+		It("should stop pre-pulling the removed images on restart", func() {
+			// Given
+			expectImagePresent("image", imageCandidate)
+			sut.StartImagePrePull(context.Background(), []string{"image"})
+			Eventually(imageStates).Should(Equal(map[string]string{"image": "present"}))
+
+			// When
+			sut.StartImagePrePull(context.Background(), []string{})
+
+			// Then
+			info := sut.ImagePrePullInfo()
+			Expect(info.Ready).To(BeTrue())
+			Expect(info.Images).To(BeEmpty())
+		})
+	})
+})
@@ -239,13 +239,26 @@ func (s *Server) pullImageCandidate(ctx context.Context, sourceCtx *imageTypes.S
 		log.Debugf(ctx, "Image in store has different ID, re-pulling %s", remoteCandidateName)
 	}
 
+	// Wait for a free pull slot, which has to happen before watching the
+	// progress to not count the time in the queue as stalled pull.
+	release, err := s.pullScheduler.Acquire(ctx, remoteCandidateName.Registry())
+	if err != nil {
+		log.Debugf(ctx, "Error waiting to pull image %s: %v", remoteCandidateName, err)
+		return err
+	}
+	defer release()
+
+	// Expose the pull progress via the inspect endpoint
+	pullStatus := s.pullProgress.start(remoteCandidateName)
+	defer s.pullProgress.finish(pullStatus)
+
 	// Collect pull progress metrics
 	progress := make(chan imageTypes.ProgressProperties)
 	defer close(progress) // nolint:gocritic
 
 	// Cancel the pull if no progress is made
 	pullCtx, cancel := context.WithCancel(context.Background())
-	go consumeImagePullProgress(ctx, cancel, progress, remoteCandidateName)
+	go s.consumeImagePullProgress(ctx, cancel, progress, remoteCandidateName, pullStatus)
 
 	_, err = s.StorageImageServer().PullImage(pullCtx, remoteCandidateName, &storage.ImageCopyOptions{
 		SourceCtx:        sourceCtx,
@@ -269,8 +282,9 @@ func (s *Server) pullImageCandidate(ctx context.Context, sourceCtx *imageTypes.S
 // consumeImagePullProgress consumes progress and turns it into metrics updates.
 // It also checks if progress is being made within a constant timeout.
 // If the timeout is reached because no progress updates have been made, then
-// the cancel function will be called.
-func consumeImagePullProgress(ctx context.Context, cancel context.CancelFunc, progress <-chan imageTypes.ProgressProperties, remoteCandidateName storage.RegistryImageReference) {
+// the cancel function will be called. The progress of every blob is recorded
+// in the pullStatus.
+func (s *Server) consumeImagePullProgress(ctx context.Context, cancel context.CancelFunc, progress <-chan imageTypes.ProgressProperties, remoteCandidateName storage.RegistryImageReference, pullStatus *imagePullStatus) {
 	// The progress interval is 1s, but we give it a bit more time just in case
 	// that the connection revives.
 	const timeout = 10 * time.Second
@@ -283,6 +297,7 @@ func consumeImagePullProgress(ctx context.Context, cancel context.CancelFunc, pr
 
 	for p := range progress {
 		timer.Reset(timeout)
+		s.pullProgress.update(pullStatus, &p)
 
 		if p.Event == imageTypes.ProgressEventSkipped {
 			// Skipped digests metrics
@@ -0,0 +1,106 @@
+package server
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	imageTypes "github.com/containers/image/v5/types"
+	"github.com/cri-o/cri-o/internal/storage"
+	"github.com/cri-o/cri-o/pkg/types"
+)
+
+// imagePullProgress tracks the progress of all in-flight image pulls.
+type imagePullProgress struct {
+	pulls map[*imagePullStatus]struct{}
+	mutex sync.Mutex
+}
+
+// imagePullStatus is the progress of a single in-flight image pull.
+type imagePullStatus struct {
+	image        string
+	started      time.Time
+	lastProgress time.Time
+	layers       map[string]*types.ImagePullLayerInfo
+	order        []string
+}
+
+func newImagePullProgress() *imagePullProgress {
+	return &imagePullProgress{
+		pulls: make(map[*imagePullStatus]struct{}),
+	}
+}
+
+// start registers a new in-flight pull of the image.
+func (p *imagePullProgress) start(image storage.RegistryImageReference) *imagePullStatus {
+	now := time.Now()
+	status := &imagePullStatus{
+		image:        image.StringForOutOfProcessConsumptionOnly(),
+		started:      now,
+		lastProgress: now,
+		layers:       make(map[string]*types.ImagePullLayerInfo),
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.pulls[status] = struct{}{}
+	return status
+}
+
+// finish removes the pull once it succeeded or failed.
+func (p *imagePullProgress) finish(status *imagePullStatus) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	delete(p.pulls, status)
+}
+
+// update records the progress properties of a single blob.
+func (p *imagePullProgress) update(status *imagePullStatus, properties *imageTypes.ProgressProperties) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	status.lastProgress = time.Now()
+	digest := properties.Artifact.Digest.String()
+	layer, ok := status.layers[digest]
+	if !ok {
+		layer = &types.ImagePullLayerInfo{
+			Digest:    digest,
+			MediaType: properties.Artifact.MediaType,
+			Size:      properties.Artifact.Size,
+		}
+		status.layers[digest] = layer
+		status.order = append(status.order, digest)
+	}
+	layer.Offset = properties.Offset
+	switch properties.Event {
+	case imageTypes.ProgressEventDone, imageTypes.ProgressEventSkipped:
+		layer.Done = true
+		if layer.Size > 0 {
+			layer.Offset = uint64(layer.Size)
+		}
+	default:
+	}
+}
+
+// list returns the in-flight pulls sorted by their start time.
+func (p *imagePullProgress) list() []types.ImagePullInfo {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	res := make([]types.ImagePullInfo, 0, len(p.pulls))
+	for status := range p.pulls {
+		info := types.ImagePullInfo{
+			Image:            status.image,
+			StartTime:        status.started.UnixNano(),
+			LastProgressTime: status.lastProgress.UnixNano(),
+			Layers:           make([]types.ImagePullLayerInfo, 0, len(status.order)),
+		}
+		for _, digest := range status.order {
+			info.Layers = append(info.Layers, *status.layers[digest])
+		}
+		res = append(res, info)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].StartTime < res[j].StartTime
+	})
+	return res
+}
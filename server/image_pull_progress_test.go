@@ -0,0 +1,60 @@
+package server
+
+import (
+	"testing"
+
+	imageTypes "github.com/containers/image/v5/types"
+	"github.com/cri-o/cri-o/internal/storage/references"
+	digest "github.com/opencontainers/go-digest"
+)
+
+func TestImagePullProgress(t *testing.T) {
+	image, err := references.ParseRegistryImageReferenceFromOutOfProcessData("example.com/some-image:latest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	layer := imageTypes.BlobInfo{
+		Digest:    digest.FromString("layer"),
+		Size:      100,
+		MediaType: "application/vnd.oci.image.layer.v1.tar+gzip",
+	}
+
+	p := newImagePullProgress()
+	status := p.start(image)
+	p.update(status, &imageTypes.ProgressProperties{
+		Event:    imageTypes.ProgressEventRead,
+		Artifact: layer,
+		Offset:   42,
+	})
+
+	pulls := p.list()
+	if len(pulls) != 1 {
+		t.Fatalf("expected 1 pull, got %d", len(pulls))
+	}
+	if pulls[0].Image != "example.com/some-image:latest" {
+		t.Fatalf("expected 'example.com/some-image:latest', got %q", pulls[0].Image)
+	}
+	if pulls[0].LastProgressTime < pulls[0].StartTime {
+		t.Fatalf("expected last progress %d after start %d", pulls[0].LastProgressTime, pulls[0].StartTime)
+	}
+	if len(pulls[0].Layers) != 1 {
+		t.Fatalf("expected 1 layer, got %d", len(pulls[0].Layers))
+	}
+	if l := pulls[0].Layers[0]; l.Digest != layer.Digest.String() || l.Offset != 42 || l.Size != 100 || l.Done {
+		t.Fatalf("unexpected layer progress %+v", l)
+	}
+
+	p.update(status, &imageTypes.ProgressProperties{
+		Event:    imageTypes.ProgressEventDone,
+		Artifact: layer,
+		Offset:   90,
+	})
+	if l := p.list()[0].Layers[0]; l.Offset != 100 || !l.Done {
+		t.Fatalf("unexpected layer progress %+v", l)
+	}
+
+	p.finish(status)
+	if pulls := p.list(); len(pulls) != 0 {
+		t.Fatalf("expected no pulls, got %d", len(pulls))
+	}
+}
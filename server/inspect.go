@@ -6,16 +6,23 @@ import (
 	"math"
 	"net/http"
 	"net/http/pprof"
+	"net/url"
+	"slices"
+	"strings"
 
 	"github.com/containers/storage/pkg/idtools"
+	"github.com/cri-o/cri-o/internal/config/cgmgr"
 	"github.com/cri-o/cri-o/internal/lib/sandbox"
 	"github.com/cri-o/cri-o/internal/log"
 	"github.com/cri-o/cri-o/internal/oci"
 	"github.com/cri-o/cri-o/pkg/types"
 	"github.com/go-chi/chi/v5"
 	json "github.com/json-iterator/go"
+	rspec "github.com/opencontainers/runtime-spec/specs-go"
 	"github.com/sirupsen/logrus"
 	"golang.org/x/net/context"
+	"k8s.io/apimachinery/pkg/labels"
+	cri "k8s.io/cri-api/pkg/apis/runtime/v1"
 )
 
 func (s *Server) getIDMappingsInfo() types.IDMappings {
@@ -117,14 +124,301 @@ func (s *Server) getContainerInfo(ctx context.Context, id string, getContainerFu
 	}, nil
 }
 
+// getContainerStatsInfo returns the block I/O, pressure and hugetlb
+// statistics of the container.
+func (s *Server) getContainerStatsInfo(ctx context.Context, id string) (*types.ContainerStatsInfo, error) {
+	ctr := s.GetContainer(ctx, id)
+	if ctr == nil {
+		return nil, errCtrNotFound
+	}
+	sb := s.getSandbox(ctx, ctr.Sandbox())
+	if sb == nil {
+		return nil, errSandboxNotFound
+	}
+	stats, err := s.Runtime().ContainerStats(ctx, ctr, sb.CgroupParent())
+	if err != nil {
+		return nil, err
+	}
+	return cgroupStatsToContainerStatsInfo(stats), nil
+}
+
+func cgroupStatsToContainerStatsInfo(stats *cgmgr.CgroupStats) *types.ContainerStatsInfo {
+	res := &types.ContainerStatsInfo{
+		Blkio:   make([]types.BlkioDeviceInfo, 0, len(stats.Blkio)),
+		Hugetlb: make(map[string]types.HugetlbInfo, len(stats.Hugetlb)),
+	}
+	for _, blkio := range stats.Blkio {
+		res.Blkio = append(res.Blkio, types.BlkioDeviceInfo{
+			Major:      blkio.Major,
+			Minor:      blkio.Minor,
+			ReadBytes:  blkio.ReadBytes,
+			WriteBytes: blkio.WriteBytes,
+			ReadOps:    blkio.ReadOps,
+			WriteOps:   blkio.WriteOps,
+		})
+	}
+	for pageSize, hugetlb := range stats.Hugetlb {
+		res.Hugetlb[pageSize] = types.HugetlbInfo{
+			Usage:    hugetlb.Usage,
+			MaxUsage: hugetlb.MaxUsage,
+			Failcnt:  hugetlb.Failcnt,
+		}
+	}
+	if pressure := stats.Pressure; pressure != nil {
+		res.Pressure = &types.PressureInfo{
+			CPU:    psiStatsToPSIInfo(pressure.CPU),
+			Memory: psiStatsToPSIInfo(pressure.Memory),
+			IO:     psiStatsToPSIInfo(pressure.IO),
+		}
+	}
+	return res
+}
+
+func psiStatsToPSIInfo(psi *cgmgr.PSIStats) *types.PSIInfo {
+	if psi == nil {
+		return nil
+	}
+	return &types.PSIInfo{
+		Some: types.PSIDataInfo(psi.Some),
+		Full: types.PSIDataInfo(psi.Full),
+	}
+}
+
+// procNamespaceNames maps the OCI namespace types to their names in /proc.
+var procNamespaceNames = map[rspec.LinuxNamespaceType]string{
+	rspec.PIDNamespace:     "pid",
+	rspec.NetworkNamespace: "net",
+	rspec.MountNamespace:   "mnt",
+	rspec.IPCNamespace:     "ipc",
+	rspec.UTSNamespace:     "uts",
+	rspec.UserNamespace:    "user",
+	rspec.CgroupNamespace:  "cgroup",
+	rspec.TimeNamespace:    "time",
+}
+
+var (
+	podStates = []string{
+		podStateName(cri.PodSandboxState_SANDBOX_READY),
+		podStateName(cri.PodSandboxState_SANDBOX_NOTREADY),
+	}
+	containerStates = []string{
+		containerStateName(cri.ContainerState_CONTAINER_CREATED),
+		containerStateName(cri.ContainerState_CONTAINER_RUNNING),
+		containerStateName(cri.ContainerState_CONTAINER_EXITED),
+		containerStateName(cri.ContainerState_CONTAINER_UNKNOWN),
+	}
+)
+
+// podStateName returns the name of the pod state used by the pod list, like
+// "ready".
+func podStateName(state cri.PodSandboxState) string {
+	return strings.ToLower(strings.TrimPrefix(state.String(), "SANDBOX_"))
+}
+
+// containerStateName returns the name of the container state used by the
+// container list, like "running".
+func containerStateName(state cri.ContainerState) string {
+	return strings.ToLower(strings.TrimPrefix(state.String(), "CONTAINER_"))
+}
+
+// listFilterFromQuery parses the filter of the pod and container list
+// endpoints from the query parameters.
+func listFilterFromQuery(query url.Values, states []string) (*types.ListFilter, error) {
+	filter := &types.ListFilter{
+		Namespace:      query.Get(InspectNamespaceFilter),
+		Labels:         map[string]string{},
+		State:          query.Get(InspectStateFilter),
+		RuntimeHandler: query.Get(InspectRuntimeHandlerFilter),
+	}
+	for _, label := range query[InspectLabelFilter] {
+		key, value, ok := strings.Cut(label, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid label filter %q, expected key=value", label)
+		}
+		filter.Labels[key] = value
+	}
+	if filter.State != "" && !slices.Contains(states, filter.State) {
+		return nil, fmt.Errorf("invalid state filter %q, expected one of: %s", filter.State, strings.Join(states, ", "))
+	}
+	return filter, nil
+}
+
+// matchesListFilter returns true if the provided attributes match the filter.
+func matchesListFilter(filter *types.ListFilter, namespace string, objLabels map[string]string, state, runtimeHandler string) bool {
+	if filter.Namespace != "" && filter.Namespace != namespace {
+		return false
+	}
+	if filter.State != "" && filter.State != state {
+		return false
+	}
+	if filter.RuntimeHandler != "" && filter.RuntimeHandler != runtimeHandler {
+		return false
+	}
+	return labels.SelectorFromSet(filter.Labels).Matches(labels.Set(objLabels))
+}
+
+// listPodInfos returns the information about all created pod sandboxes
+// matching the filter.
+func (s *Server) listPodInfos(filter *types.ListFilter) []types.PodInfo {
+	res := []types.PodInfo{}
+	for _, sb := range s.ContainerServer.ListSandboxes() {
+		if !sb.Created() {
+			continue
+		}
+		state := podStateName(sb.State())
+		if !matchesListFilter(filter, sb.Namespace(), sb.Labels(), state, sb.RuntimeHandler()) {
+			continue
+		}
+
+		info := types.PodInfo{
+			ID:             sb.ID(),
+			Name:           sb.Metadata().GetName(),
+			Namespace:      sb.Namespace(),
+			UID:            sb.Metadata().GetUid(),
+			State:          state,
+			CreatedTime:    sb.CreatedAt(),
+			RuntimeHandler: sb.RuntimeHandler(),
+			CgroupParent:   sb.CgroupParent(),
+			NamespacePaths: podNamespacePaths(sb),
+			IPs:            sb.IPs(),
+			Labels:         sb.Labels(),
+			Containers:     []string{},
+		}
+		if infra := sb.InfraContainer(); infra != nil {
+			if state := infra.State(); state != nil {
+				info.Pid = state.InitPid
+			}
+		}
+		for _, ctr := range sb.Containers().List() {
+			info.Containers = append(info.Containers, ctr.ID())
+		}
+		res = append(res, info)
+	}
+	return res
+}
+
+// podNamespacePaths returns the paths of the namespaces of the pod sandbox,
+// indexed by their names in /proc.
+func podNamespacePaths(sb *sandbox.Sandbox) map[string]string {
+	res := map[string]string{}
+	for name, path := range map[string]string{
+		procNamespaceNames[rspec.NetworkNamespace]: sb.NetNsPath(),
+		procNamespaceNames[rspec.IPCNamespace]:     sb.IpcNsPath(),
+		procNamespaceNames[rspec.UTSNamespace]:     sb.UtsNsPath(),
+		procNamespaceNames[rspec.UserNamespace]:    sb.UserNsPath(),
+		procNamespaceNames[rspec.PIDNamespace]:     sb.PidNsPath(),
+	} {
+		if path != "" {
+			res[name] = path
+		}
+	}
+	return res
+}
+
+// listContainerInfos returns the information about all created containers
+// matching the filter.
+func (s *Server) listContainerInfos(ctx context.Context, filter *types.ListFilter) ([]types.ContainerListInfo, error) {
+	ctrs, err := s.ContainerServer.ListContainers()
+	if err != nil {
+		return nil, err
+	}
+
+	res := []types.ContainerListInfo{}
+	for _, ctr := range ctrs {
+		if !ctr.Created() {
+			continue
+		}
+		sb := s.getSandbox(ctx, ctr.Sandbox())
+		if sb == nil {
+			log.Debugf(ctx, "Can't find sandbox %s for container %s", ctr.Sandbox(), ctr.ID())
+			continue
+		}
+		criContainer := ctr.CRIContainer()
+		state := containerStateName(criContainer.State)
+		if !matchesListFilter(filter, sb.Namespace(), criContainer.Labels, state, sb.RuntimeHandler()) {
+			continue
+		}
+
+		info := types.ContainerListInfo{
+			ID:             ctr.ID(),
+			Name:           ctr.Metadata().GetName(),
+			PodID:          sb.ID(),
+			PodName:        sb.Metadata().GetName(),
+			PodNamespace:   sb.Namespace(),
+			State:          state,
+			CreatedTime:    criContainer.CreatedAt,
+			RuntimeHandler: sb.RuntimeHandler(),
+			IPs:            sb.IPs(),
+			Mounts:         []types.MountInfo{},
+			Labels:         criContainer.Labels,
+		}
+		if imageName := ctr.ImageName(); imageName != nil {
+			info.Image = imageName.StringForOutOfProcessConsumptionOnly()
+		}
+		if state := ctr.State(); state != nil {
+			info.Pid = state.InitPid
+		}
+		spec := ctr.Spec()
+		if spec.Linux != nil {
+			info.CgroupPath = spec.Linux.CgroupsPath
+		}
+		info.NamespacePaths = containerNamespacePaths(&spec, info.Pid)
+		for _, volume := range ctr.Volumes() {
+			info.Mounts = append(info.Mounts, types.MountInfo{
+				ContainerPath: volume.ContainerPath,
+				HostPath:      volume.HostPath,
+				Readonly:      volume.Readonly,
+				Propagation:   volume.Propagation.String(),
+			})
+		}
+		res = append(res, info)
+	}
+	return res, nil
+}
+
+// containerNamespacePaths returns the paths of the namespaces of the
+// container, indexed by their names in /proc. Namespaces which are not shared
+// with the pod are referenced by the container process.
+func containerNamespacePaths(spec *rspec.Spec, pid int) map[string]string {
+	res := map[string]string{}
+	if spec.Linux == nil {
+		return res
+	}
+	for _, namespace := range spec.Linux.Namespaces {
+		name, ok := procNamespaceNames[namespace.Type]
+		if !ok {
+			continue
+		}
+		switch {
+		case namespace.Path != "":
+			res[name] = namespace.Path
+		case pid > 0:
+			res[name] = fmt.Sprintf("/proc/%d/ns/%s", pid, name)
+		}
+	}
+	return res
+}
+
 const (
 	InspectConfigEndpoint     = "/config"
 	InspectContainersEndpoint = "/containers"
 	InspectInfoEndpoint       = "/info"
 	InspectPauseEndpoint      = "/pause"
+	InspectPodsEndpoint       = "/pods"
+	InspectPrePullEndpoint    = "/prepull"
+	InspectPullsEndpoint      = "/pulls"
+	InspectReloadEndpoint     = "/reload"
 	InspectUnpauseEndpoint    = "/unpause"
 )
 
+// Query parameters of the pod and container list endpoints.
+const (
+	InspectLabelFilter          = "label"
+	InspectNamespaceFilter      = "namespace"
+	InspectRuntimeHandlerFilter = "runtime_handler"
+	InspectStateFilter          = "state"
+)
+
 // GetExtendInterfaceMux returns the mux used to serve extend interface requests
 func (s *Server) GetExtendInterfaceMux(enableProfile bool) *chi.Mux {
 	mux := chi.NewMux()
@@ -155,6 +449,89 @@ func (s *Server) GetExtendInterfaceMux(enableProfile bool) *chi.Mux {
 		}
 	}))
 
+	mux.Get(InspectPrePullEndpoint, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		js, err := json.Marshal(s.imagePrePuller.info())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write(js); err != nil {
+			logrus.Errorf("Unable to write response JSON: %v", err)
+		}
+	}))
+
+	mux.Get(InspectPullsEndpoint, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		js, err := json.Marshal(s.pullProgress.list())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write(js); err != nil {
+			logrus.Errorf("Unable to write response JSON: %v", err)
+		}
+	}))
+
+	mux.Get(InspectReloadEndpoint, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		info := s.config.LastReload()
+		if info == nil {
+			info = &types.ReloadInfo{
+				Changes:         []types.ConfigChange{},
+				RestartRequired: []string{},
+			}
+		}
+		js, err := json.Marshal(info)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write(js); err != nil {
+			logrus.Errorf("Unable to write response JSON: %v", err)
+		}
+	}))
+
+	mux.Get(InspectPodsEndpoint, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		filter, err := listFilterFromQuery(req.URL.Query(), podStates)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		js, err := json.Marshal(s.listPodInfos(filter))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write(js); err != nil {
+			logrus.Errorf("Unable to write response JSON: %v", err)
+		}
+	}))
+
+	mux.Get(InspectContainersEndpoint, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := context.TODO()
+		filter, err := listFilterFromQuery(req.URL.Query(), containerStates)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		infos, err := s.listContainerInfos(ctx, filter)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		js, err := json.Marshal(infos)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write(js); err != nil {
+			logrus.Errorf("Unable to write response JSON: %v", err)
+		}
+	}))
+
 	mux.Get(InspectContainersEndpoint+"/{id}", http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		ctx := context.TODO()
 		containerID := chi.URLParam(req, "id")
@@ -183,6 +560,32 @@ func (s *Server) GetExtendInterfaceMux(enableProfile bool) *chi.Mux {
 		}
 	}))
 
+	mux.Get(InspectContainersEndpoint+"/{id}/stats", http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ctx := context.TODO()
+		containerID := chi.URLParam(req, "id")
+		stats, err := s.getContainerStatsInfo(ctx, containerID)
+		if err != nil {
+			switch {
+			case errors.Is(err, errCtrNotFound):
+				http.Error(w, "can't find the container with id "+containerID, http.StatusNotFound)
+			case errors.Is(err, errSandboxNotFound):
+				http.Error(w, "can't find the sandbox for container id "+containerID, http.StatusNotFound)
+			default:
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+			return
+		}
+		js, err := json.Marshal(stats)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write(js); err != nil {
+			logrus.Errorf("Unable to write response JSON: %v", err)
+		}
+	}))
+
 	mux.Get(InspectPauseEndpoint+"/{id}", http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
 		containerID := chi.URLParam(req, "id")
 		ctx := context.TODO()
@@ -6,7 +6,9 @@ import (
 	"net/http/httptest"
 
 	"github.com/cri-o/cri-o/internal/oci"
+	"github.com/cri-o/cri-o/pkg/types"
 	"github.com/go-chi/chi/v5"
+	json "github.com/json-iterator/go"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/opencontainers/runtime-spec/specs-go"
@@ -44,6 +46,45 @@ var _ = t.Describe("Inspect", func() {
 			Expect(recorder.Code).To(BeEquivalentTo(http.StatusOK))
 		})
 
+		It("should succeed with /pulls route", func() {
+			// Given
+			// When
+			request, err := http.NewRequest(http.MethodGet, "/pulls", http.NoBody)
+			mux.ServeHTTP(recorder, request)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(request).NotTo(BeNil())
+			Expect(recorder.Code).To(BeEquivalentTo(http.StatusOK))
+			Expect(recorder.Body.String()).To(Equal("[]"))
+		})
+
+		It("should succeed with /prepull route", func() {
+			// Given
+			// When
+			request, err := http.NewRequest(http.MethodGet, "/prepull", http.NoBody)
+			mux.ServeHTTP(recorder, request)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(request).NotTo(BeNil())
+			Expect(recorder.Code).To(BeEquivalentTo(http.StatusOK))
+			Expect(recorder.Body.String()).To(Equal(`{"ready":true,"images":[]}`))
+		})
+
+		It("should succeed with /reload route", func() {
+			// Given
+			// When
+			request, err := http.NewRequest(http.MethodGet, "/reload", http.NoBody)
+			mux.ServeHTTP(recorder, request)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(request).NotTo(BeNil())
+			Expect(recorder.Code).To(BeEquivalentTo(http.StatusOK))
+			Expect(recorder.Body.String()).To(Equal(`{"time":0,"success":false,"changes":[],"restart_required":[]}`))
+		})
+
 		It("should succeed with valid /containers route", func() {
 			ctx := context.TODO()
 			// Given
@@ -103,7 +144,7 @@ var _ = t.Describe("Inspect", func() {
 				To(BeEquivalentTo(http.StatusInternalServerError))
 		})
 
-		It("should fail with empty with /containers route", func() {
+		It("should succeed with empty /containers list route", func() {
 			// Given
 			// When
 			request, err := http.NewRequest(http.MethodGet, "/containers", http.NoBody)
@@ -112,7 +153,102 @@ var _ = t.Describe("Inspect", func() {
 			// Then
 			Expect(err).ToNot(HaveOccurred())
 			Expect(request).NotTo(BeNil())
-			Expect(recorder.Code).To(BeEquivalentTo(http.StatusNotFound))
+			Expect(recorder.Code).To(BeEquivalentTo(http.StatusOK))
+			Expect(recorder.Body.String()).To(Equal("[]"))
+		})
+
+		It("should succeed with /containers list route", func() {
+			// Given
+			addContainerAndSandbox()
+			testContainer.SetStateAndSpoofPid(&oci.ContainerState{
+				State: specs.State{Status: oci.ContainerStateRunning},
+			})
+
+			// When
+			request, err := http.NewRequest(http.MethodGet, "/containers", http.NoBody)
+			mux.ServeHTTP(recorder, request)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(recorder.Code).To(BeEquivalentTo(http.StatusOK))
+			containers := []types.ContainerListInfo{}
+			Expect(json.Unmarshal(recorder.Body.Bytes(), &containers)).To(Succeed())
+			Expect(containers).To(HaveLen(1))
+			Expect(containers[0].ID).To(Equal(testContainer.ID()))
+			Expect(containers[0].PodID).To(Equal(testSandbox.ID()))
+			Expect(containers[0].State).To(Equal("running"))
+		})
+
+		It("should filter the /containers list route", func() {
+			// Given
+			addContainerAndSandbox()
+			testContainer.SetStateAndSpoofPid(&oci.ContainerState{})
+
+			// When
+			request, err := http.NewRequest(http.MethodGet, "/containers?state=running", http.NoBody)
+			mux.ServeHTTP(recorder, request)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(recorder.Code).To(BeEquivalentTo(http.StatusOK))
+			Expect(recorder.Body.String()).To(Equal("[]"))
+		})
+
+		It("should fail with invalid state filter on /containers list route", func() {
+			// Given
+			// When
+			request, err := http.NewRequest(http.MethodGet, "/containers?state=invalid", http.NoBody)
+			mux.ServeHTTP(recorder, request)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(recorder.Code).To(BeEquivalentTo(http.StatusBadRequest))
+		})
+
+		It("should succeed with /pods route", func() {
+			// Given
+			addContainerAndSandbox()
+			testContainer.SetStateAndSpoofPid(&oci.ContainerState{})
+
+			// When
+			request, err := http.NewRequest(http.MethodGet, "/pods", http.NoBody)
+			mux.ServeHTTP(recorder, request)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(recorder.Code).To(BeEquivalentTo(http.StatusOK))
+			pods := []types.PodInfo{}
+			Expect(json.Unmarshal(recorder.Body.Bytes(), &pods)).To(Succeed())
+			Expect(pods).To(HaveLen(1))
+			Expect(pods[0].ID).To(Equal(testSandbox.ID()))
+			Expect(pods[0].State).To(Equal("notready"))
+			Expect(pods[0].Containers).To(ConsistOf(testContainer.ID()))
+		})
+
+		It("should filter the /pods route by label", func() {
+			// Given
+			addContainerAndSandbox()
+			testContainer.SetStateAndSpoofPid(&oci.ContainerState{})
+
+			// When
+			request, err := http.NewRequest(http.MethodGet, "/pods?label=app%3Dtest", http.NoBody)
+			mux.ServeHTTP(recorder, request)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(recorder.Code).To(BeEquivalentTo(http.StatusOK))
+			Expect(recorder.Body.String()).To(Equal("[]"))
+		})
+
+		It("should fail with invalid label filter on /pods route", func() {
+			// Given
+			// When
+			request, err := http.NewRequest(http.MethodGet, "/pods?label=invalid", http.NoBody)
+			mux.ServeHTTP(recorder, request)
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(recorder.Code).To(BeEquivalentTo(http.StatusBadRequest))
 		})
 
 		It("should fail with invalid container ID on /containers route", func() {
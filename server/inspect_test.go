@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/cri-o/cri-o/internal/config/cgmgr"
 	"github.com/cri-o/cri-o/internal/lib/sandbox"
 	"github.com/cri-o/cri-o/internal/oci"
 	"github.com/cri-o/cri-o/internal/storage"
@@ -247,3 +248,31 @@ func TestGetContainerInfoSandboxNotFound(t *testing.T) {
 		t.Fatalf("expected errSandboxNotFound error, got %v", err)
 	}
 }
+
+func TestCgroupStatsToContainerStatsInfo(t *testing.T) {
+	stats := &cgmgr.CgroupStats{
+		Blkio: []cgmgr.BlkioDeviceStats{
+			{Major: 8, Minor: 0, ReadBytes: 100, WriteBytes: 200, ReadOps: 1, WriteOps: 2},
+		},
+		Pressure: &cgmgr.PressureStats{
+			IO: &cgmgr.PSIStats{Some: cgmgr.PSIData{Avg10: 1.5, Total: 1234}},
+		},
+		Hugetlb: map[string]cgmgr.HugetlbStats{
+			"2MB": {Usage: 2097152, MaxUsage: 4194304, Failcnt: 1},
+		},
+	}
+
+	info := cgroupStatsToContainerStatsInfo(stats)
+	if len(info.Blkio) != 1 || info.Blkio[0].ReadBytes != 100 || info.Blkio[0].WriteOps != 2 {
+		t.Fatalf("unexpected blkio stats: %+v", info.Blkio)
+	}
+	if info.Pressure == nil || info.Pressure.CPU != nil || info.Pressure.IO == nil {
+		t.Fatalf("unexpected pressure stats: %+v", info.Pressure)
+	}
+	if info.Pressure.IO.Some.Avg10 != 1.5 || info.Pressure.IO.Some.Total != 1234 {
+		t.Fatalf("unexpected io pressure: %+v", info.Pressure.IO)
+	}
+	if hugetlb := info.Hugetlb["2MB"]; hugetlb.Usage != 2097152 || hugetlb.MaxUsage != 4194304 || hugetlb.Failcnt != 1 {
+		t.Fatalf("unexpected hugetlb stats: %+v", info.Hugetlb)
+	}
+}
@@ -0,0 +1,38 @@
+package server_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+// The actual test suite
+var _ = t.Describe("ListMetricDescriptors", func() {
+	// Prepare the sut
+	BeforeEach(func() {
+		beforeEach()
+		setupSUT()
+	})
+	AfterEach(afterEach)
+
+	t.Describe("ListMetricDescriptors", func() {
+		It("should succeed", func() {
+			// Given
+			// When
+			response, err := sut.ListMetricDescriptors(context.Background(),
+				&types.ListMetricDescriptorsRequest{})
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response).NotTo(BeNil())
+			Expect(response.Descriptors).NotTo(BeEmpty())
+			for _, desc := range response.Descriptors {
+				Expect(desc.Name).NotTo(BeEmpty())
+				Expect(desc.Help).NotTo(BeEmpty())
+				Expect(desc.LabelKeys).To(ContainElements("id", "name", "image"))
+			}
+		})
+	})
+})
@@ -82,6 +82,13 @@ type Metrics struct {
 	metricContainersOOMCountTotal             *prometheus.CounterVec
 	metricContainersSeccompNotifierCountTotal *prometheus.CounterVec
 	metricResourcesStalledAtStage             *prometheus.CounterVec
+	metricResourcesStageLatencySeconds        *prometheus.HistogramVec
+	metricHostportRepairsTotal                *prometheus.CounterVec
+	metricContainersCheckpointPagesWritten    *prometheus.HistogramVec
+	metricContainersCheckpointFrozenSeconds   *prometheus.HistogramVec
+	metricContainersExitsTotal                *prometheus.CounterVec
+	metricImagePullsQueueDepth                *prometheus.GaugeVec
+	metricImagePullsQueueWaitSeconds          *prometheus.HistogramVec
 }
 
 var instance *Metrics
@@ -238,6 +245,70 @@ func New(config *libconfig.MetricsConfig) *Metrics {
 			},
 			[]string{"stage"},
 		),
+		metricResourcesStageLatencySeconds: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Subsystem: collectors.Subsystem,
+				Name:      collectors.ResourcesStageLatencySeconds.String(),
+				Help:      "Latency in seconds of the stages in container and pod creation. Broken down by stage and result.",
+				// 5ms up to ~80s
+				Buckets: prometheus.ExponentialBuckets(0.005, 2, 15),
+			},
+			[]string{"stage", "result"},
+		),
+		metricHostportRepairsTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Subsystem: collectors.Subsystem,
+				Name:      collectors.HostportRepairsTotal.String(),
+				Help:      "Amount of pod hostport rules restored after being removed externally by IP family.",
+			},
+			[]string{"family"},
+		),
+		metricImagePullsQueueDepth: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Subsystem: collectors.Subsystem,
+				Name:      collectors.ImagePullsQueueDepth.String(),
+				Help:      "Amount of image pulls waiting for a free pull slot by registry.",
+			},
+			[]string{"registry"},
+		),
+		metricImagePullsQueueWaitSeconds: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Subsystem: collectors.Subsystem,
+				Name:      collectors.ImagePullsQueueWaitSeconds.String(),
+				Help:      "Time in seconds image pulls waited for a free pull slot by registry.",
+				// 5ms up to ~80s
+				Buckets: prometheus.ExponentialBuckets(0.005, 2, 15),
+			},
+			[]string{"registry"},
+		),
+		metricContainersCheckpointPagesWritten: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Subsystem: collectors.Subsystem,
+				Name:      collectors.ContainersCheckpointPagesWritten.String(),
+				Help:      "Amount of memory pages written by a container checkpoint by dump, either pre-dump or final.",
+				// 1 page up to ~16M pages (64GiB with 4KiB pages)
+				Buckets: prometheus.ExponentialBuckets(1, 4, 13),
+			},
+			[]string{"dump"},
+		),
+		metricContainersCheckpointFrozenSeconds: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Subsystem: collectors.Subsystem,
+				Name:      collectors.ContainersCheckpointFrozenSeconds.String(),
+				Help:      "Time in seconds the container processes were frozen during a checkpoint by dump, either pre-dump or final.",
+				// 1ms up to ~65s
+				Buckets: prometheus.ExponentialBuckets(0.001, 2, 17),
+			},
+			[]string{"dump"},
+		),
+		metricContainersExitsTotal: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Subsystem: collectors.Subsystem,
+				Name:      collectors.ContainersExitsTotal.String(),
+				Help:      "Amount of exited containers by their exit reason",
+			},
+			[]string{"reason"},
+		),
 	}
 	return Instance()
 }
@@ -400,17 +471,86 @@ func (m *Metrics) MetricResourcesStalledAtStage(stage string) {
 	c.Inc()
 }
 
+func (m *Metrics) MetricResourcesStageLatencyObserve(stage, result string, start time.Time) {
+	o, err := m.metricResourcesStageLatencySeconds.GetMetricWithLabelValues(stage, result)
+	if err != nil {
+		logrus.Warnf("Unable to write resource stage latency metric: %v", err)
+		return
+	}
+	o.Observe(SinceInSeconds(start))
+}
+
+func (m *Metrics) MetricHostportRepairsInc(family string) {
+	c, err := m.metricHostportRepairsTotal.GetMetricWithLabelValues(family)
+	if err != nil {
+		logrus.Warnf("Unable to write hostport repairs metric: %v", err)
+		return
+	}
+	c.Inc()
+}
+
+func (m *Metrics) MetricImagePullsQueueDepthSet(registry string, depth int) {
+	g, err := m.metricImagePullsQueueDepth.GetMetricWithLabelValues(registry)
+	if err != nil {
+		logrus.Warnf("Unable to write image pulls queue depth metric: %v", err)
+		return
+	}
+	g.Set(float64(depth))
+}
+
+func (m *Metrics) MetricImagePullsQueueWaitObserve(registry string, start time.Time) {
+	o, err := m.metricImagePullsQueueWaitSeconds.GetMetricWithLabelValues(registry)
+	if err != nil {
+		logrus.Warnf("Unable to write image pulls queue wait metric: %v", err)
+		return
+	}
+	o.Observe(SinceInSeconds(start))
+}
+
+func (m *Metrics) MetricContainersCheckpointPagesWrittenObserve(dump string, pages uint64) {
+	o, err := m.metricContainersCheckpointPagesWritten.GetMetricWithLabelValues(dump)
+	if err != nil {
+		logrus.Warnf("Unable to write container checkpoint pages metric: %v", err)
+		return
+	}
+	o.Observe(float64(pages))
+}
+
+func (m *Metrics) MetricContainersCheckpointFrozenObserve(dump string, frozen time.Duration) {
+	o, err := m.metricContainersCheckpointFrozenSeconds.GetMetricWithLabelValues(dump)
+	if err != nil {
+		logrus.Warnf("Unable to write container checkpoint frozen time metric: %v", err)
+		return
+	}
+	o.Observe(frozen.Seconds())
+}
+
+func (m *Metrics) MetricContainersExitsTotalInc(reason string) {
+	c, err := m.metricContainersExitsTotal.GetMetricWithLabelValues(reason)
+	if err != nil {
+		logrus.Warnf("Unable to write container exits metric: %v", err)
+		return
+	}
+	c.Inc()
+}
+
 // createEndpoint creates a /metrics endpoint for prometheus monitoring.
 func (m *Metrics) createEndpoint() (*http.ServeMux, error) {
 	for collector, metric := range map[collectors.Collector]prometheus.Collector{
+		collectors.ContainersCheckpointFrozenSeconds:   m.metricContainersCheckpointFrozenSeconds,
+		collectors.ContainersCheckpointPagesWritten:    m.metricContainersCheckpointPagesWritten,
 		collectors.ContainersEventsDropped:             m.metricContainersEventsDropped,
+		collectors.ContainersExitsTotal:                m.metricContainersExitsTotal,
 		collectors.ContainersOOMCountTotal:             m.metricContainersOOMCountTotal,
 		collectors.ContainersOOMTotal:                  m.metricContainersOOMTotal,
 		collectors.ContainersSeccompNotifierCountTotal: m.metricContainersSeccompNotifierCountTotal,
+		collectors.HostportRepairsTotal:                m.metricHostportRepairsTotal,
 		collectors.ImageLayerReuseTotal:                m.metricImageLayerReuseTotal,
 		collectors.ImagePullsBytesTotal:                m.metricImagePullsBytesTotal,
 		collectors.ImagePullsFailureTotal:              m.metricImagePullsFailureTotal,
 		collectors.ImagePullsLayerSize:                 m.metricImagePullsLayerSize,
+		collectors.ImagePullsQueueDepth:                m.metricImagePullsQueueDepth,
+		collectors.ImagePullsQueueWaitSeconds:          m.metricImagePullsQueueWaitSeconds,
 		collectors.ImagePullsSkippedBytesTotal:         m.metricImagePullsSkippedBytesTotal,
 		collectors.ImagePullsSuccessTotal:              m.metricImagePullsSuccessTotal,
 		collectors.OperationsErrorsTotal:               m.metricOperationsErrorsTotal,
@@ -419,6 +559,7 @@ func (m *Metrics) createEndpoint() (*http.ServeMux, error) {
 		collectors.OperationsTotal:                     m.metricOperationsTotal,
 		collectors.ProcessesDefunct:                    m.metricProcessesDefunct,
 		collectors.ResourcesStalledAtStage:             m.metricResourcesStalledAtStage,
+		collectors.ResourcesStageLatencySeconds:        m.metricResourcesStageLatencySeconds,
 	} {
 		if m.config.MetricsCollectors.Contains(collector) {
 			logrus.Debugf("Enabling metric: %s", collector.Stripped())
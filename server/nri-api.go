@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/cri-o/cri-o/internal/log"
 	"github.com/intel/goresctrl/pkg/blockio"
@@ -23,6 +24,10 @@ import (
 	"github.com/cri-o/cri-o/internal/nri"
 )
 
+// unknownNRIPlugin is recorded as evicting plugin if the plugin requesting
+// the eviction of a container is not known.
+const unknownNRIPlugin = "unknown"
+
 type nriAPI struct {
 	cri *Server
 	nri nri.API
@@ -410,18 +415,38 @@ func (a *nriAPI) UpdateContainer(ctx context.Context, u *api.ContainerUpdate) er
 	return nil
 }
 
-func (a *nriAPI) EvictContainer(ctx context.Context, e *api.ContainerEviction) error {
+func (a *nriAPI) EvictContainer(ctx context.Context, plugin string, e *api.ContainerEviction) error {
 	ctr, err := a.cri.GetContainerFromShortID(context.TODO(), e.ContainerId)
 	if err != nil {
 		// We blindly assume container with given ID not found and ignore it.
 		log.Errorf(ctx, "Failed to evict CRI container %q: %v", e.ContainerId, err)
 		return nil
 	}
-	if err = a.cri.stopContainer(ctx, ctr, 0); err != nil {
-		log.Errorf(ctx, "Failed to evict CRI container %q: %v", e.ContainerId, err)
-		return err
+
+	switch ctr.State().Status {
+	case oci.ContainerStateCreated, oci.ContainerStateRunning, oci.ContainerStatePaused:
+	default:
+		return nil
 	}
 
+	if plugin == "" {
+		plugin = unknownNRIPlugin
+	}
+	gracePeriod := a.cri.config.NRI.EvictionGracePeriod
+	log.Infof(ctx, "Evicting CRI container %s on behalf of NRI plugin %q (grace period: %v): %s",
+		ctr.ID(), plugin, gracePeriod, e.Reason)
+	ctr.SetEvicted(plugin, e.Reason)
+
+	// Stopping the container can take up to the grace period, which must not
+	// block the NRI request the eviction is part of.
+	stopCtx := context.WithoutCancel(ctx)
+	go func() {
+		if err := a.cri.stopContainer(stopCtx, ctr, int64(gracePeriod/time.Second)); err != nil {
+			ctr.SetEvicted("", "")
+			log.Errorf(stopCtx, "Failed to evict CRI container %q: %v", e.ContainerId, err)
+		}
+	}()
+
 	return nil
 }
 
@@ -63,6 +63,27 @@ const (
 
 	// ResourcesStalledAtStage is the key for the resources stalled at different stages in container and pod creation.
 	ResourcesStalledAtStage Collector = crioPrefix + "resources_stalled_at_stage"
+
+	// ResourcesStageLatencySeconds is the key for the time spent in the different stages of container and pod creation.
+	ResourcesStageLatencySeconds Collector = crioPrefix + "resources_stage_latency_seconds"
+
+	// HostportRepairsTotal is the key for the CRI-O hostport rule repairs after external modifications.
+	HostportRepairsTotal Collector = crioPrefix + "hostport_repairs_total"
+
+	// ImagePullsQueueDepth is the key for the CRI-O image pulls waiting for a free pull slot.
+	ImagePullsQueueDepth Collector = crioPrefix + "image_pulls_queue_depth"
+
+	// ImagePullsQueueWaitSeconds is the key for the time CRI-O image pulls waited for a free pull slot.
+	ImagePullsQueueWaitSeconds Collector = crioPrefix + "image_pulls_queue_wait_seconds"
+
+	// ContainersCheckpointPagesWritten is the key for the memory pages written by CRIU per checkpoint dump.
+	ContainersCheckpointPagesWritten Collector = crioPrefix + "containers_checkpoint_pages_written"
+
+	// ContainersCheckpointFrozenSeconds is the key for the time the container processes were frozen per checkpoint dump.
+	ContainersCheckpointFrozenSeconds Collector = crioPrefix + "containers_checkpoint_frozen_seconds"
+
+	// ContainersExitsTotal is the key for the CRI-O container exits per exit reason.
+	ContainersExitsTotal Collector = crioPrefix + "containers_exits_total"
 )
 
 // FromSlice converts a string slice to a Collectors type.
@@ -101,6 +122,13 @@ func All() Collectors {
 		ContainersOOMCountTotal.Stripped(),
 		ContainersSeccompNotifierCountTotal.Stripped(),
 		ResourcesStalledAtStage.Stripped(),
+		ResourcesStageLatencySeconds.Stripped(),
+		HostportRepairsTotal.Stripped(),
+		ImagePullsQueueDepth.Stripped(),
+		ImagePullsQueueWaitSeconds.Stripped(),
+		ContainersCheckpointPagesWritten.Stripped(),
+		ContainersCheckpointFrozenSeconds.Stripped(),
+		ContainersExitsTotal.Stripped(),
 	}
 }
 
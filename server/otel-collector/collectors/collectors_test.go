@@ -52,11 +52,18 @@ var _ = t.Describe("Collectors", func() {
 				collectors.ContainersOOMCountTotal,
 				collectors.ContainersSeccompNotifierCountTotal,
 				collectors.ResourcesStalledAtStage,
+				collectors.ResourcesStageLatencySeconds,
+				collectors.HostportRepairsTotal,
+				collectors.ImagePullsQueueDepth,
+				collectors.ImagePullsQueueWaitSeconds,
+				collectors.ContainersCheckpointPagesWritten,
+				collectors.ContainersCheckpointFrozenSeconds,
+				collectors.ContainersExitsTotal,
 			} {
 				Expect(all.Contains(collector)).To(BeTrue())
 			}
 
-			Expect(all).To(HaveLen(16))
+			Expect(all).To(HaveLen(23))
 		})
 	})
 
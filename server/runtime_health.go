@@ -0,0 +1,182 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	criu "github.com/checkpoint-restore/go-criu/v7/utils"
+	"github.com/containers/podman/v4/pkg/checkpoint/crutils"
+	"github.com/cri-o/cri-o/pkg/config"
+	crioTypes "github.com/cri-o/cri-o/pkg/types"
+	"github.com/cri-o/cri-o/utils/cmdrunner"
+	"github.com/sirupsen/logrus"
+)
+
+// The reasons reported for an unhealthy runtime handler.
+const (
+	runtimeBinaryNotFoundReason = "RuntimeBinaryNotFound"
+	runtimeVersionInvalidReason = "RuntimeVersionInvalid"
+)
+
+// runtimeVersionUnknownReason is reported for a healthy runtime handler whose
+// version output does not contain a parsable version.
+const runtimeVersionUnknownReason = "RuntimeVersionUnknown"
+
+// runtimeVersionProbeTimeout is the maximum time a runtime binary has to
+// report its version.
+const runtimeVersionProbeTimeout = 10 * time.Second
+
+// runtimeVersionRegexp matches the version in the output of the runtime
+// binaries, like "runc version 1.1.12" or "version: 3.2.0" for containerd
+// shims.
+var runtimeVersionRegexp = regexp.MustCompile(`(?i)\bversion:?\s+v?(\d+\.\d+[^\s,]*)`)
+
+// runtimeHealth caches the results of the last runtime handler health probes.
+type runtimeHealth struct {
+	handlers map[string]*crioTypes.RuntimeHandlerHealthInfo
+	mutex    sync.RWMutex
+}
+
+func newRuntimeHealth() *runtimeHealth {
+	return &runtimeHealth{
+		handlers: map[string]*crioTypes.RuntimeHandlerHealthInfo{},
+	}
+}
+
+// get returns the result of the last probe of the runtime handler or nil if
+// it has not been probed yet.
+func (h *runtimeHealth) get(handler string) *crioTypes.RuntimeHandlerHealthInfo {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	return h.handlers[handler]
+}
+
+// list returns the results of the last probes sorted by the runtime handler
+// name.
+func (h *runtimeHealth) list() []crioTypes.RuntimeHandlerHealthInfo {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	res := make([]crioTypes.RuntimeHandlerHealthInfo, 0, len(h.handlers))
+	for _, info := range h.handlers {
+		res = append(res, *info)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Name < res[j].Name
+	})
+	return res
+}
+
+// update replaces the cached results and logs all runtime handlers which
+// changed their health.
+func (h *runtimeHealth) update(handlers map[string]*crioTypes.RuntimeHandlerHealthInfo) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	for name, info := range handlers {
+		previous, ok := h.handlers[name]
+		switch {
+		case !info.Healthy && (!ok || previous.Healthy):
+			logrus.Warnf("Runtime handler %q is not healthy: %s", name, info.Message)
+		case info.Healthy && ok && !previous.Healthy:
+			logrus.Infof("Runtime handler %q is healthy again", name)
+		}
+		if info.Healthy && info.Reason != "" && (!ok || previous.Reason != info.Reason) {
+			logrus.Warnf("Runtime handler %q: %s", name, info.Message)
+		}
+	}
+	h.handlers = handlers
+}
+
+// runRuntimeHealthChecks probes the runtime handlers right away and then in
+// the configured interval until the stop channel gets closed.
+func (s *Server) runRuntimeHealthChecks(stopCh <-chan struct{}) {
+	if s.config.RuntimeHealthCheckInterval == 0 {
+		return
+	}
+	s.probeRuntimeHandlers()
+	ticker := time.NewTicker(s.config.RuntimeHealthCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+			s.probeRuntimeHandlers()
+		}
+	}
+}
+
+// probeRuntimeHandlers probes all configured runtime handlers and caches the
+// results.
+func (s *Server) probeRuntimeHandlers() {
+	handlers := make(map[string]*crioTypes.RuntimeHandlerHealthInfo, len(s.config.Runtimes))
+	for name, handler := range s.config.Runtimes {
+		handlers[name] = probeRuntimeHandler(name, handler, s.config.CheckpointRestore())
+	}
+	s.runtimeHealth.update(handlers)
+}
+
+// checkRuntimeHandlerHealth returns an error if the last probe of the runtime
+// handler failed. Runtime handlers which have not been probed yet are
+// considered healthy.
+func (s *Server) checkRuntimeHandlerHealth(handler string) error {
+	if handler == "" {
+		handler = s.config.DefaultRuntime
+	}
+	if info := s.runtimeHealth.get(handler); info != nil && !info.Healthy {
+		return fmt.Errorf("runtime handler %q is not healthy: %s", handler, info.Message)
+	}
+	return nil
+}
+
+// probeRuntimeHandler checks that the binary of the runtime handler is
+// present and reports its version. A version output without a parsable
+// version does not make the runtime handler unhealthy. The supported ID-mapped and
+// recursive read-only mounts are the features loaded from the runtime
+// handler, while the checkpoint/restore support gets probed.
+func probeRuntimeHandler(name string, handler *config.RuntimeHandler, checkpointRestore bool) *crioTypes.RuntimeHandlerHealthInfo {
+	info := &crioTypes.RuntimeHandlerHealthInfo{
+		Name:          name,
+		LastProbeTime: time.Now().UnixNano(),
+	}
+
+	stat, err := os.Stat(handler.RuntimePath)
+	if err != nil {
+		info.Reason = runtimeBinaryNotFoundReason
+		info.Message = fmt.Sprintf("runtime binary %q not found: %v", handler.RuntimePath, err)
+		return info
+	}
+	if stat.IsDir() || stat.Mode()&0o111 == 0 {
+		info.Reason = runtimeBinaryNotFoundReason
+		info.Message = fmt.Sprintf("runtime binary %q is not executable", handler.RuntimePath)
+		return info
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), runtimeVersionProbeTimeout)
+	defer cancel()
+	output, err := cmdrunner.CommandContext(ctx, handler.RuntimePath, "--version").CombinedOutput()
+	if err != nil {
+		info.Reason = runtimeVersionInvalidReason
+		info.Message = fmt.Sprintf("unable to get version of runtime binary %q: %v", handler.RuntimePath, err)
+		return info
+	}
+	if match := runtimeVersionRegexp.FindSubmatch(output); match != nil {
+		info.Version = string(match[1])
+	} else {
+		info.Reason = runtimeVersionUnknownReason
+		info.Message = fmt.Sprintf("unable to parse version of runtime binary %q from %q", handler.RuntimePath, output)
+	}
+
+	info.Healthy = true
+	info.IDMapMounts = handler.RuntimeSupportsIDMap()
+	info.RecursiveReadOnly = handler.RuntimeSupportsRROMounts()
+	info.CheckpointRestore = checkpointRestore &&
+		handler.RuntimeType != config.RuntimeTypeVM &&
+		criu.CheckForCriu(criu.PodCriuVersion) == nil &&
+		crutils.CRRuntimeSupportsCheckpointRestore(handler.RuntimePath)
+	return info
+}
@@ -0,0 +1,125 @@
+package server_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/cri-o/cri-o/pkg/config"
+	crioTypes "github.com/cri-o/cri-o/pkg/types"
+	json "github.com/json-iterator/go"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+// The actual test suite
+var _ = t.Describe("RuntimeHealth", func() {
+	// Prepare the sut with a probed default runtime handler
+	setupRuntimeHandler := func(script string) {
+		beforeEach()
+		runtimePath := filepath.Join(t.MustTempDir("runtime"), "runtime")
+		Expect(os.WriteFile(runtimePath,
+			[]byte("#!/bin/sh\n"+script+"\n"), 0o755),
+		).To(Succeed())
+		serverConfig.Runtimes[serverConfig.DefaultRuntime] = &config.RuntimeHandler{
+			RuntimePath: runtimePath,
+		}
+		serverConfig.RuntimeHealthCheckInterval = time.Hour
+		setupSUT()
+	}
+
+	// runtimeHandlers waits for the first probe and returns its results
+	runtimeHandlers := func() []crioTypes.RuntimeHandlerHealthInfo {
+		handlers := []crioTypes.RuntimeHandlerHealthInfo{}
+		Eventually(func() []crioTypes.RuntimeHandlerHealthInfo {
+			response, err := sut.Status(context.Background(),
+				&types.StatusRequest{Verbose: true})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(json.Unmarshal([]byte(response.Info["runtimeHandlers"]), &handlers)).To(Succeed())
+			return handlers
+		}).Should(HaveLen(1))
+		return handlers
+	}
+
+	AfterEach(afterEach)
+
+	t.Describe("Status", func() {
+		It("should report the runtime handlers in a verbose response", func() {
+			// Given
+			setupRuntimeHandler("echo 'runc version 1.1.12'")
+			handlers := runtimeHandlers()
+
+			// When
+			response, err := sut.Status(context.Background(),
+				&types.StatusRequest{Verbose: true})
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			for _, condition := range response.Status.Conditions {
+				Expect(condition.Status).To(BeTrue())
+			}
+			Expect(handlers[0].Name).To(Equal(serverConfig.DefaultRuntime))
+			Expect(handlers[0].Healthy).To(BeTrue())
+			Expect(handlers[0].Version).To(Equal("1.1.12"))
+			Expect(handlers[0].LastProbeTime).NotTo(BeZero())
+		})
+
+		It("should stay runtime ready if the version is not parsable", func() {
+			// Given
+			setupRuntimeHandler("echo 'runsc version release-20240401.0'")
+			handlers := runtimeHandlers()
+
+			// When
+			response, err := sut.Status(context.Background(),
+				&types.StatusRequest{Verbose: true})
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Status.Conditions[0].Type).To(Equal(types.RuntimeReady))
+			Expect(response.Status.Conditions[0].Status).To(BeTrue())
+			Expect(handlers[0].Healthy).To(BeTrue())
+			Expect(handlers[0].Reason).To(Equal("RuntimeVersionUnknown"))
+			Expect(handlers[0].Version).To(BeEmpty())
+		})
+
+		It("should not be runtime ready if the default handler is not healthy", func() {
+			// Given
+			setupRuntimeHandler("exit 1")
+			handlers := runtimeHandlers()
+
+			// When
+			response, err := sut.Status(context.Background(),
+				&types.StatusRequest{Verbose: true})
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.Status.Conditions[0].Type).To(Equal(types.RuntimeReady))
+			Expect(response.Status.Conditions[0].Status).To(BeFalse())
+			Expect(response.Status.Conditions[0].Reason).To(Equal("RuntimeHandlerNotHealthy"))
+			Expect(handlers[0].Healthy).To(BeFalse())
+			Expect(handlers[0].Reason).To(Equal("RuntimeVersionInvalid"))
+		})
+	})
+
+	t.Describe("RunPodSandbox", func() {
+		It("should fail fast if the runtime handler is not healthy", func() {
+			// Given
+			setupRuntimeHandler("exit 1")
+			runtimeHandlers()
+
+			// When
+			response, err := sut.RunPodSandbox(context.Background(),
+				&types.RunPodSandboxRequest{
+					Config:         &types.PodSandboxConfig{},
+					RuntimeHandler: serverConfig.DefaultRuntime,
+				})
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("is not healthy"))
+			Expect(response).To(BeNil())
+		})
+	})
+})
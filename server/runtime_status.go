@@ -11,6 +11,10 @@ import (
 // networkNotReadyReason is the reason reported when network is not ready.
 const networkNotReadyReason = "NetworkPluginNotReady"
 
+// runtimeNotReadyReason is the reason reported when the default runtime
+// handler is not healthy.
+const runtimeNotReadyReason = "RuntimeHandlerNotHealthy"
+
 // Status returns the status of the runtime
 func (s *Server) Status(ctx context.Context, req *types.StatusRequest) (*types.StatusResponse, error) {
 	runtimeCondition := &types.RuntimeCondition{
@@ -22,6 +26,12 @@ func (s *Server) Status(ctx context.Context, req *types.StatusRequest) (*types.S
 		Status: true,
 	}
 
+	if err := s.checkRuntimeHandlerHealth(s.config.DefaultRuntime); err != nil {
+		runtimeCondition.Status = false
+		runtimeCondition.Reason = runtimeNotReadyReason
+		runtimeCondition.Message = err.Error()
+	}
+
 	if err := s.config.CNIPluginReadyOrError(); err != nil {
 		networkCondition.Status = false
 		networkCondition.Reason = networkNotReadyReason
@@ -78,5 +88,12 @@ func (s *Server) createRuntimeInfo() (map[string]string, error) {
 	if err != nil {
 		return nil, fmt.Errorf("marshal data: %w", err)
 	}
-	return map[string]string{"config": string(bytes)}, nil
+	handlers, err := json.Marshal(s.runtimeHealth.list())
+	if err != nil {
+		return nil, fmt.Errorf("marshal runtime handler health: %w", err)
+	}
+	return map[string]string{
+		"config":          string(bytes),
+		"runtimeHandlers": string(handlers),
+	}, nil
 }
@@ -0,0 +1,48 @@
+package server_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+)
+
+// The actual test suite
+var _ = t.Describe("ListPodSandboxMetrics", func() {
+	// Prepare the sut
+	BeforeEach(func() {
+		beforeEach()
+		setupSUT()
+	})
+	AfterEach(afterEach)
+
+	t.Describe("ListPodSandboxMetrics", func() {
+		It("should succeed without sandboxes", func() {
+			// Given
+			// When
+			response, err := sut.ListPodSandboxMetrics(context.Background(),
+				&types.ListPodSandboxMetricsRequest{})
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response).NotTo(BeNil())
+			Expect(response.PodMetrics).To(BeEmpty())
+		})
+
+		It("should succeed with sandbox", func() {
+			// Given
+			addContainerAndSandbox()
+
+			// When
+			response, err := sut.ListPodSandboxMetrics(context.Background(),
+				&types.ListPodSandboxMetricsRequest{})
+
+			// Then
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response).NotTo(BeNil())
+			Expect(response.PodMetrics).To(HaveLen(1))
+			Expect(response.PodMetrics[0].PodSandboxId).To(Equal(testSandbox.ID()))
+		})
+	})
+})
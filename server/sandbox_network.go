@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"net"
 	"time"
 
 	cnitypes "github.com/containernetworking/cni/pkg/types"
@@ -128,6 +129,43 @@ func (s *Server) networkStart(ctx context.Context, sb *sandbox.Sandbox) (podIPs
 	return podIPs, result, err
 }
 
+// restoreHostportMappings hands the port mappings of a sandbox, which has been
+// restored after a restart, back to the hostport manager. This allows it to
+// reconcile them like the ones added by networkStart.
+func (s *Server) restoreHostportMappings(sb *sandbox.Sandbox, podIPs []string) {
+	restorer, ok := s.hostportManager.(hostport.HostPortRestorer)
+	if !ok || sb.HostNetwork() || sb.NetworkStopped() || len(sb.PortMappings()) == 0 {
+		return
+	}
+
+	// only the first IP of each IP family got mapped by networkStart
+	foundIPv4 := false
+	foundIPv6 := false
+	for _, podIP := range podIPs {
+		ip := net.ParseIP(podIP)
+		if ip == nil {
+			continue
+		}
+		if utilnet.IsIPv6(ip) {
+			if foundIPv6 {
+				continue
+			}
+			foundIPv6 = true
+		} else {
+			if foundIPv4 {
+				continue
+			}
+			foundIPv4 = true
+		}
+		restorer.Restore(sb.ID(), &hostport.PodPortMapping{
+			Name:         sb.Name(),
+			PortMappings: sb.PortMappings(),
+			IP:           ip,
+			HostNetwork:  false,
+		}, "")
+	}
+}
+
 // getSandboxIP retrieves the IP address for the sandbox
 func (s *Server) getSandboxIPs(ctx context.Context, sb *sandbox.Sandbox) ([]string, error) {
 	ctx, span := log.StartSpan(ctx)
@@ -0,0 +1,132 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	metadata "github.com/checkpoint-restore/checkpointctl/lib"
+	"github.com/containers/storage/pkg/archive"
+	"github.com/cri-o/cri-o/internal/lib"
+	"github.com/cri-o/cri-o/internal/log"
+	"github.com/cri-o/cri-o/pkg/annotations"
+	"golang.org/x/net/context"
+	types "k8s.io/cri-api/pkg/apis/runtime/v1"
+	kubetypes "k8s.io/kubelet/pkg/types"
+)
+
+// podCheckpointArchive returns the pod checkpoint archive to restore the pod
+// sandbox from. The archive annotation is ignored if it is not allowed for
+// the runtime handler or workload of the pod sandbox.
+func (s *Server) podCheckpointArchive(req *types.RunPodSandboxRequest) (string, error) {
+	sbAnnotations := req.GetConfig().GetAnnotations()
+	archive := sbAnnotations[annotations.CheckpointAnnotationPodArchive]
+	if archive == "" {
+		return "", nil
+	}
+
+	toFilter := map[string]string{annotations.CheckpointAnnotationPodArchive: archive}
+	if err := s.FilterDisallowedAnnotations(sbAnnotations, toFilter, req.RuntimeHandler); err != nil {
+		return "", err
+	}
+	return toFilter[annotations.CheckpointAnnotationPodArchive], nil
+}
+
+// restorePodSandbox recreates a pod sandbox and restores all its containers
+// from the provided pod checkpoint archive.
+func (s *Server) restorePodSandbox(
+	ctx context.Context,
+	req *types.RunPodSandboxRequest,
+	input string,
+) (resp *types.RunPodSandboxResponse, retErr error) {
+	if !s.config.CheckpointRestore() {
+		return nil, errors.New("checkpoint/restore support not available")
+	}
+
+	log.Infof(ctx, "Restoring pod sandbox from checkpoint archive %s", input)
+
+	archiveFile, err := os.Open(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open pod checkpoint archive %s for import: %w", input, err)
+	}
+	defer func(f *os.File) {
+		if err := f.Close(); err != nil {
+			log.Errorf(ctx, "Unable to close file %s: %q", f.Name(), err)
+		}
+	}(archiveFile)
+
+	dir, err := os.MkdirTemp("", "pod-checkpoint")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := os.RemoveAll(dir); err != nil {
+			log.Errorf(ctx, "Could not recursively remove %s: %q", dir, err)
+		}
+	}()
+	if err := archive.Untar(archiveFile, dir, &archive.TarOptions{}); err != nil {
+		return nil, fmt.Errorf("unpacking of pod checkpoint archive %s failed: %w", input, err)
+	}
+
+	podConfig := &lib.PodCheckpointConfig{}
+	if _, err := metadata.ReadJSONFile(podConfig, dir, lib.PodCheckpointDumpFile); err != nil {
+		return nil, fmt.Errorf("failed to read %q: %w", lib.PodCheckpointDumpFile, err)
+	}
+
+	sandboxConfig := podConfig.SandboxConfig(req.Config)
+	runtimeHandler := req.RuntimeHandler
+	if runtimeHandler == "" {
+		runtimeHandler = podConfig.RuntimeHandler
+	}
+
+	resp, err = s.runPodSandbox(ctx, &types.RunPodSandboxRequest{
+		Config:         sandboxConfig,
+		RuntimeHandler: runtimeHandler,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if retErr == nil {
+			return
+		}
+		log.Infof(ctx, "RestorePod: removing pod sandbox %s", resp.PodSandboxId)
+		if _, err := s.StopPodSandbox(ctx, &types.StopPodSandboxRequest{PodSandboxId: resp.PodSandboxId}); err != nil {
+			log.Warnf(ctx, "Failed to stop pod sandbox %s: %v", resp.PodSandboxId, err)
+		}
+		if _, err := s.RemovePodSandbox(ctx, &types.RemovePodSandboxRequest{PodSandboxId: resp.PodSandboxId}); err != nil {
+			log.Warnf(ctx, "Failed to remove pod sandbox %s: %v", resp.PodSandboxId, err)
+		}
+	}()
+
+	for _, ctr := range podConfig.Containers {
+		// The pod identity labels get updated by the container restore
+		labels := map[string]string{kubetypes.KubernetesContainerNameLabel: ctr.Name}
+		if md := sandboxConfig.Metadata; md != nil {
+			labels[kubetypes.KubernetesPodNameLabel] = md.Name
+			labels[kubetypes.KubernetesPodNamespaceLabel] = md.Namespace
+		}
+
+		createResp, err := s.CreateContainer(ctx, &types.CreateContainerRequest{
+			PodSandboxId: resp.PodSandboxId,
+			Config: &types.ContainerConfig{
+				Metadata: &types.ContainerMetadata{Name: ctr.Name},
+				Image:    &types.ImageSpec{Image: filepath.Join(dir, ctr.Archive)},
+				Labels:   labels,
+				Linux:    &types.LinuxContainerConfig{},
+			},
+			SandboxConfig: sandboxConfig,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore container %s: %w", ctr.ID, err)
+		}
+		if _, err := s.StartContainer(ctx, &types.StartContainerRequest{ContainerId: createResp.ContainerId}); err != nil {
+			return nil, fmt.Errorf("failed to start restored container %s: %w", createResp.ContainerId, err)
+		}
+		log.Infof(ctx, "Restored container %s of pod sandbox %s as %s", ctr.ID, resp.PodSandboxId, createResp.ContainerId)
+	}
+
+	log.Infof(ctx, "Restored pod sandbox %s from checkpoint of %s", resp.PodSandboxId, podConfig.ID)
+	return resp, nil
+}
@@ -65,6 +65,19 @@ func (s *Server) runtimeHandler(req *types.RunPodSandboxRequest) (string, error)
 
 // RunPodSandbox creates and runs a pod-level sandbox.
 func (s *Server) RunPodSandbox(ctx context.Context, req *types.RunPodSandboxRequest) (*types.RunPodSandboxResponse, error) {
+	// Fail before reserving any resources if the runtime handler is broken
+	if err := s.checkRuntimeHandlerHealth(req.RuntimeHandler); err != nil {
+		return nil, err
+	}
+
+	archive, err := s.podCheckpointArchive(req)
+	if err != nil {
+		return nil, err
+	}
+	if archive != "" {
+		return s.restorePodSandbox(ctx, req, archive)
+	}
+
 	// platform dependent call
 	return s.runPodSandbox(ctx, req)
 }
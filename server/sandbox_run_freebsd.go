@@ -88,6 +88,12 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 	})
 
 	s.resourceStore.SetStageForResource(ctx, sbox.Name(), "sandbox creating")
+	defer func() {
+		// the creation request failed or timed out during the current stage
+		if retErr != nil {
+			s.resourceStore.FinishStageForResource(sbox.Name(), resourcestore.StageResultFailure)
+		}
+	}()
 
 	var securityContext *types.LinuxSandboxSecurityContext
 	if sbox.Config().Linux != nil && sbox.Config().Linux.SecurityContext != nil {
@@ -548,6 +554,7 @@ func (s *Server) runPodSandbox(ctx context.Context, req *types.RunPodSandboxRequ
 	}
 
 	// Since it's not a context error, we can delete the resource from the store, it will be tracked in the server from now on.
+	s.resourceStore.FinishStageForResource(sbox.Name(), resourcestore.StageResultSuccess)
 	s.resourceStore.Delete(sbox.Name())
 
 	sb.SetCreated()
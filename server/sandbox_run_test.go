@@ -2,8 +2,11 @@ package server_test
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 
 	"github.com/cri-o/cri-o/internal/storage"
+	"github.com/cri-o/cri-o/pkg/annotations"
 	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -142,3 +145,108 @@ var _ = t.Describe("RunPodSandbox", func() {
 		})
 	})
 })
+
+var _ = t.Describe("RunPodSandbox from pod checkpoint", func() {
+	restoreRequest := func(archive string) *types.RunPodSandboxRequest {
+		return &types.RunPodSandboxRequest{Config: &types.PodSandboxConfig{
+			Metadata: &types.PodSandboxMetadata{
+				Name:      "name",
+				Namespace: "default",
+				Uid:       "uid",
+			},
+			Annotations: map[string]string{
+				annotations.CheckpointAnnotationPodArchive: archive,
+			},
+		}}
+	}
+
+	t.Describe("with CheckpointRestore set to true", func() {
+		BeforeEach(func() {
+			beforeEach()
+			serverConfig.SetCheckpointRestore(true)
+			serverConfig.Runtimes[serverConfig.DefaultRuntime].AllowedAnnotations = []string{
+				annotations.CheckpointAnnotationPodArchive,
+			}
+			setupSUT()
+		})
+
+		AfterEach(afterEach)
+
+		It("should fail with non existing archive", func() {
+			// When
+			response, err := sut.RunPodSandbox(context.Background(),
+				restoreRequest("/does/not/exist.tar"))
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("failed to open pod checkpoint archive"))
+			Expect(response).To(BeNil())
+		})
+
+		It("should fail with invalid archive", func() {
+			// Given
+			archive := filepath.Join(t.MustTempDir("pod-checkpoint"), "pod.tar")
+			Expect(os.WriteFile(archive, []byte("invalid"), 0o600)).To(Succeed())
+
+			// When
+			response, err := sut.RunPodSandbox(context.Background(),
+				restoreRequest(archive))
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(response).To(BeNil())
+		})
+	})
+
+	t.Describe("with the archive annotation not allowed", func() {
+		BeforeEach(func() {
+			beforeEach()
+			serverConfig.SetCheckpointRestore(true)
+			setupSUT()
+		})
+
+		AfterEach(afterEach)
+
+		It("should not open the archive", func() {
+			// Given
+			runtimeServerMock.EXPECT().CreatePodSandbox(gomock.Any(),
+				gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+				gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+				gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+				Return(storage.ContainerInfo{}, t.TestError)
+
+			// When
+			response, err := sut.RunPodSandbox(context.Background(),
+				restoreRequest("/does/not/exist.tar"))
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(t.TestError.Error()))
+			Expect(response).To(BeNil())
+		})
+	})
+
+	t.Describe("with CheckpointRestore set to false", func() {
+		BeforeEach(func() {
+			beforeEach()
+			serverConfig.SetCheckpointRestore(false)
+			serverConfig.Runtimes[serverConfig.DefaultRuntime].AllowedAnnotations = []string{
+				annotations.CheckpointAnnotationPodArchive,
+			}
+			setupSUT()
+		})
+
+		AfterEach(afterEach)
+
+		It("should fail with checkpoint/restore support not available", func() {
+			// When
+			response, err := sut.RunPodSandbox(context.Background(),
+				restoreRequest("/pod.tar"))
+
+			// Then
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("checkpoint/restore support not available"))
+			Expect(response).To(BeNil())
+		})
+	})
+})
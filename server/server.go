@@ -27,6 +27,7 @@ import (
 	"github.com/cri-o/cri-o/internal/lib/sandbox"
 	"github.com/cri-o/cri-o/internal/log"
 	"github.com/cri-o/cri-o/internal/oci"
+	"github.com/cri-o/cri-o/internal/pullscheduler"
 	"github.com/cri-o/cri-o/internal/resourcestore"
 	"github.com/cri-o/cri-o/internal/runtimehandlerhooks"
 	"github.com/cri-o/cri-o/internal/signals"
@@ -80,14 +81,23 @@ type Server struct {
 	pullOperationsInProgress map[pullArguments]*pullOperation
 	// pullOperationsLock is used to synchronize pull operations.
 	pullOperationsLock sync.Mutex
+	// pullScheduler limits the amount of concurrent image pulls.
+	pullScheduler *pullscheduler.Scheduler
+	// pullProgress tracks the progress of the in-flight image pulls.
+	pullProgress *imagePullProgress
+	// imageGC garbage collects unused images.
+	imageGC *storage.ImageGC
+	// imagePrePuller pulls the configured images in the background.
+	imagePrePuller *imagePrePuller
+	// runtimeHealth caches the results of the runtime handler health probes.
+	runtimeHealth *runtimeHealth
 
 	resourceStore *resourcestore.ResourceStore
 
 	seccompNotifierChan chan seccomp.Notification
 	seccompNotifiers    sync.Map
 
-	containerEventClients           sync.Map
-	containerEventStreamBroadcaster sync.Once
+	containerEventBuffer *containerEventBuffer
 
 	// NRI runtime interface
 	nri *nriAPI
@@ -300,7 +310,7 @@ func (s *Server) restore(ctx context.Context) []storage.StorageImageID {
 		}
 	}()
 
-	// Restore sandbox IPs
+	// Restore sandbox IPs and the hostport mappings using them
 	for _, sb := range s.ListSandboxes() {
 		ips, err := s.getSandboxIPs(ctx, sb)
 		if err != nil {
@@ -308,6 +318,7 @@ func (s *Server) restore(ctx context.Context) []storage.StorageImageID {
 			continue
 		}
 		sb.AddIPs(ips)
+		s.restoreHostportMappings(sb, ips)
 	}
 
 	// Return a slice of images to remove, if internal_wipe is set.
@@ -323,6 +334,7 @@ func (s *Server) restore(ctx context.Context) []storage.StorageImageID {
 func (s *Server) Shutdown(ctx context.Context) error {
 	s.config.CNIManagerShutdown()
 	s.resourceStore.Close()
+	s.stopImagePrePull()
 
 	if err := s.ContainerServer.Shutdown(); err != nil {
 		return err
@@ -419,9 +431,12 @@ func New(
 
 	// Check for hostport mapping
 	var hostportManager hostport.HostPortManager
-	if config.RuntimeConfig.DisableHostPortMapping {
+	switch {
+	case config.RuntimeConfig.DisableHostPortMapping:
 		hostportManager = hostport.NewNoopHostportManager()
-	} else {
+	case config.RuntimeConfig.HostPortMappingBackend == libconfig.HostPortMappingBackendNFTables:
+		hostportManager = hostport.NewMetaNftablesHostportManager()
+	default:
 		hostportManager = hostport.NewMetaHostportManager()
 	}
 
@@ -448,11 +463,19 @@ func New(
 		minimumMappableUID:       config.MinimumMappableUID,
 		minimumMappableGID:       config.MinimumMappableGID,
 		pullOperationsInProgress: make(map[pullArguments]*pullOperation),
+		pullScheduler:            pullscheduler.New(config.MaxConcurrentImagePulls, config.MaxConcurrentImagePullsPerRegistry),
+		pullProgress:             newImagePullProgress(),
+		imagePrePuller:           &imagePrePuller{},
+		runtimeHealth:            newRuntimeHealth(),
 		resourceStore:            resourcestore.New(),
 	}
+	s.imageGC = storage.NewImageGC(s.Store(), &s.config.ImageConfig, s.imagesInUse)
 	if s.config.EnablePodEvents {
 		// creating a container events channel only if the evented pleg is enabled
 		s.ContainerEventsChan = make(chan types.ContainerEventResponse, 1000)
+		s.containerEventBuffer = newContainerEventBuffer(containerEventsBufferSize)
+		// note that this function will run indefinitely until ContainerEventsChan is closed
+		go s.broadcastEvents()
 	}
 	if err := configureMaxThreads(); err != nil {
 		return nil, err
@@ -472,6 +495,9 @@ func New(
 	deletedImages := s.restore(ctx)
 	s.wipeIfAppropriate(ctx, deletedImages)
 
+	// Make sure that the configured images are present
+	s.startImagePrePull(ctx)
+
 	var bindAddressStr string
 	bindAddress := net.ParseIP(config.StreamAddress)
 	if bindAddress != nil {
@@ -541,6 +567,18 @@ func New(
 		logrus.Debug("Metrics are disabled")
 	}
 
+	// Restore hostport mappings which got removed externally, for example by a firewall reload
+	if reconciler, ok := s.hostportManager.(hostport.HostPortReconciler); ok {
+		go reconciler.Reconcile(s.monitorsChan)
+	}
+
+	// Garbage collect unused images independently of the kubelet
+	go s.imageGC.Run(s.monitorsChan)
+
+	// Probe the runtime handlers in the background, starting right away so
+	// that broken ones get reported soon
+	go s.runRuntimeHealthChecks(s.monitorsChan)
+
 	if err := s.startSeccompNotifierWatcher(ctx); err != nil {
 		return nil, fmt.Errorf("start seccomp notifier watcher: %w", err)
 	}
@@ -577,6 +615,10 @@ func (s *Server) startReloadWatcher(ctx context.Context) {
 				logrus.Errorf("Unable to reload configuration: %v", err)
 				continue
 			}
+			s.startImagePrePull(ctx)
+			if s.config.RuntimeHealthCheckInterval > 0 {
+				s.probeRuntimeHandlers()
+			}
 		}
 	}()
 
@@ -704,6 +746,22 @@ func (s *Server) removeContainer(ctx context.Context, c *oci.Container) {
 	s.ContainerServer.RemoveContainer(ctx, c)
 }
 
+// imagesInUse returns the IDs of all images used by containers, which must
+// not be garbage collected.
+func (s *Server) imagesInUse() (map[string]bool, error) {
+	ctrs, err := s.ContainerServer.ListContainers()
+	if err != nil {
+		return nil, fmt.Errorf("list containers: %w", err)
+	}
+	inUse := make(map[string]bool)
+	for _, ctr := range ctrs {
+		if id := ctr.ImageID(); id != nil {
+			inUse[id.IDStringForOutOfProcessConsumptionOnly()] = true
+		}
+	}
+	return inUse, nil
+}
+
 func (s *Server) removeInfraContainer(ctx context.Context, c *oci.Container) {
 	ctx, span := log.StartSpan(ctx)
 	defer span.End()
@@ -810,6 +868,7 @@ func (s *Server) handleExit(ctx context.Context, event fsnotify.Event) {
 	}
 
 	if nriCtr != nil {
+		s.recordContainerExit(ctx, nriCtr, sb)
 		if err := s.nri.stopContainer(ctx, nil, nriCtr); err != nil {
 			log.Warnf(ctx, "NRI stop container request of %s failed: %v", nriCtr.ID(), err)
 		}
@@ -922,14 +981,12 @@ func (s *Server) generateCRIEvent(ctx context.Context, container *oci.Container,
 		return
 	}
 
-	select {
-	case s.ContainerEventsChan <- types.ContainerEventResponse{ContainerId: container.ID(), ContainerEventType: eventType, CreatedAt: time.Now().UnixNano(), PodSandboxStatus: sandboxStatuses, ContainersStatuses: containerStatuses}:
-		log.Debugf(ctx, "Container event %s generated for %s", eventType, container.ID())
-	default:
+	if !s.containerEventBuffer.publish(s.ContainerEventsChan, types.ContainerEventResponse{ContainerId: container.ID(), ContainerEventType: eventType, PodSandboxStatus: sandboxStatuses, ContainersStatuses: containerStatuses}) {
 		log.Errorf(ctx, "GenerateCRIEvent: failed to generate event %s for container %s", eventType, container.ID())
 		metrics.Instance().MetricContainersEventsDroppedInc()
 		return
 	}
+	log.Debugf(ctx, "Container event %s generated for %s", eventType, container.ID())
 }
 
 func isNotFound(err error) bool {
@@ -53,7 +53,9 @@ func (s *Server) startSeccompNotifierWatcher(ctx context.Context) error {
 			}
 
 			// Restart the notifier
-			notifier, err := seccomp.NewNotifier(context.Background(), s.seccompNotifierChan, id, path, ctr.Annotations())
+			notifier, err := seccomp.NewNotifier(
+				context.Background(), s.seccompNotifierChan, id, path, s.config.Seccomp().ProfileRecordDir(), ctr.Annotations(),
+			)
 			if err != nil {
 				logrus.Errorf("Unable to run restored notifier: %v", err)
 				return nil
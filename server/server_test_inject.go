@@ -7,6 +7,10 @@
 package server
 
 import (
+	"context"
+
+	"github.com/containerd/nri/pkg/api"
+	crioTypes "github.com/cri-o/cri-o/pkg/types"
 	"github.com/cri-o/ocicni/pkg/ocicni"
 )
 
@@ -20,3 +24,25 @@ func (s *StreamService) SetRuntimeServer(server *Server) {
 func (s *Server) SetCNIPlugin(plugin ocicni.CNIPlugin) error {
 	return s.config.SetCNIPlugin(plugin)
 }
+
+// EvictContainer evicts the container like requested by the NRI plugin.
+func (s *Server) EvictContainer(ctx context.Context, plugin string, eviction *api.ContainerEviction) error {
+	return s.nri.EvictContainer(ctx, plugin, eviction)
+}
+
+// StartImagePrePull configures the images to pre-pull and starts pulling them
+// in the background, like a config reload does.
+func (s *Server) StartImagePrePull(ctx context.Context, images []string) {
+	s.config.PrePullImages = images
+	s.startImagePrePull(ctx)
+}
+
+// StopImagePrePull cancels all outstanding background pulls.
+func (s *Server) StopImagePrePull() {
+	s.stopImagePrePull()
+}
+
+// ImagePrePullInfo returns the state of all images pulled in the background.
+func (s *Server) ImagePrePullInfo() crioTypes.PrePullInfo {
+	return s.imagePrePuller.info()
+}
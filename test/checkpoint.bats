@@ -143,3 +143,33 @@ function teardown() {
 	[[ "$container_name" == "restored-sleep-container" ]]
 	[[ "$pod_name" == "restoresandbox2" ]]
 }
+
+@test "checkpoint and restore a whole pod" {
+	create_runtime_with_allowed_annotation "checkpoint" "io.kubernetes.cri-o.annotations.checkpoint.pod.archive"
+	CONTAINER_ENABLE_CRIU_SUPPORT=true start_crio
+	pod_id=$(crictl runp "$TESTDATA"/sandbox_config.json)
+	ctr_id=$(crictl create "$pod_id" "$TESTDATA"/container_sleep.json "$TESTDATA"/sandbox_config.json)
+	crictl start "$ctr_id"
+	crictl checkpoint --export="$TESTDIR"/pod.tar "$pod_id"
+	crictl rmp -f "$pod_id"
+	RESTORE_SANDBOX_JSON=$(mktemp)
+	jq --arg archive "$TESTDIR/pod.tar" \
+		'.annotations."io.kubernetes.cri-o.annotations.checkpoint.pod.archive" = $archive' \
+		"$TESTDATA"/sandbox_config.json > "$RESTORE_SANDBOX_JSON"
+	pod_id=$(crictl runp "$RESTORE_SANDBOX_JSON")
+	rm -f "$RESTORE_SANDBOX_JSON"
+	ctr_id=$(crictl ps --quiet --pod "$pod_id" --state running)
+	[[ -n "$ctr_id" ]]
+	restored=$(crictl inspect --output go-template --template "{{(index .info.restored)}}" "$ctr_id")
+	[[ "$restored" == "true" ]]
+}
+
+@test "do not restore a pod if the archive annotation is not allowed" {
+	CONTAINER_ENABLE_CRIU_SUPPORT=true start_crio
+	RESTORE_SANDBOX_JSON=$(mktemp)
+	jq '.annotations."io.kubernetes.cri-o.annotations.checkpoint.pod.archive" = "/does/not/exist.tar"' \
+		"$TESTDATA"/sandbox_config.json > "$RESTORE_SANDBOX_JSON"
+	pod_id=$(crictl runp "$RESTORE_SANDBOX_JSON")
+	rm -f "$RESTORE_SANDBOX_JSON"
+	[[ -z "$(crictl ps --quiet --all --pod "$pod_id")" ]]
+}
@@ -156,17 +156,31 @@ func (mr *MockRuntimeImplMockRecorder) PauseContainer(arg0, arg1 interface{}) *g
 }
 
 // PortForwardContainer mocks base method.
-func (m *MockRuntimeImpl) PortForwardContainer(arg0 context.Context, arg1 *oci.Container, arg2 string, arg3 int32, arg4 io.ReadWriteCloser) error {
+func (m *MockRuntimeImpl) PortForwardContainer(arg0 context.Context, arg1 *oci.Container, arg2 string, arg3 int32, arg4 v1.Protocol, arg5 io.ReadWriteCloser) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "PortForwardContainer", arg0, arg1, arg2, arg3, arg4)
+	ret := m.ctrl.Call(m, "PortForwardContainer", arg0, arg1, arg2, arg3, arg4, arg5)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // PortForwardContainer indicates an expected call of PortForwardContainer.
-func (mr *MockRuntimeImplMockRecorder) PortForwardContainer(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+func (mr *MockRuntimeImplMockRecorder) PortForwardContainer(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PortForwardContainer", reflect.TypeOf((*MockRuntimeImpl)(nil).PortForwardContainer), arg0, arg1, arg2, arg3, arg4)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PortForwardContainer", reflect.TypeOf((*MockRuntimeImpl)(nil).PortForwardContainer), arg0, arg1, arg2, arg3, arg4, arg5)
+}
+
+// PreDumpContainer mocks base method.
+func (m *MockRuntimeImpl) PreDumpContainer(arg0 context.Context, arg1 *oci.Container, arg2 *specs.Spec) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PreDumpContainer", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PreDumpContainer indicates an expected call of PreDumpContainer.
+func (mr *MockRuntimeImplMockRecorder) PreDumpContainer(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PreDumpContainer", reflect.TypeOf((*MockRuntimeImpl)(nil).PreDumpContainer), arg0, arg1, arg2)
 }
 
 // ReopenContainerLog mocks base method.